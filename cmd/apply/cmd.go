@@ -0,0 +1,99 @@
+/*
+Copyright © 2022 James Harrington <james@harrington.net.au>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package apply
+
+import (
+	"github.com/jharrington22/aws-resource/cmd/resources"
+	"github.com/jharrington22/aws-resource/pkg/arguments"
+	logging "github.com/jharrington22/aws-resource/pkg/logging"
+	"github.com/jharrington22/aws-resource/pkg/plan"
+	rprtr "github.com/jharrington22/aws-resource/pkg/reporter"
+	"github.com/jharrington22/aws-resource/pkg/resource"
+	"github.com/spf13/cobra"
+)
+
+var (
+	dryRun bool
+)
+
+// Cmd represents the apply command
+var Cmd = &cobra.Command{
+	Use:   "apply <plan file>",
+	Short: "Apply a saved deletion plan",
+	Long: `Delete exactly the resources recorded in a plan saved by a delete command
+with --plan-out. The plan is refused if it was created for another account.
+
+aws-resource delete ec2 --plan-out plan.json
+aws-resource apply plan.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: run,
+}
+
+func run(cmd *cobra.Command, args []string) (err error) {
+	reporter := rprtr.CreateReporterOrExit()
+	logging := logging.CreateLoggerOrExit(reporter)
+
+	p, err := plan.Load(args[0])
+	if err != nil {
+		return reporter.Errorf("Unable to load plan: %s", err)
+	}
+
+	clients := resources.Clients(cmd, logging)
+	accountID, err := resources.AccountID(reporter, clients)
+	if err != nil {
+		return err
+	}
+	err = p.Verify(accountID)
+	if err != nil {
+		return reporter.Errorf("Refusing to apply plan: %s", err)
+	}
+
+	// Protected tags are checked again as they may have been configured
+	// after the plan was saved
+	protection, err := resources.NewProtection(reporter)
+	if err != nil {
+		return err
+	}
+	var planned []*resource.Resource
+	for _, action := range p.Actions {
+		planned = append(planned, action.Resource())
+	}
+	planned, err = protection.FilterUsed(reporter, planned)
+	if err != nil {
+		return err
+	}
+
+	if !dryRun {
+		err = resources.Confirm(reporter, clients, planned)
+		if err != nil {
+			return err
+		}
+		reporter.Warnf("Dry run %t will delete resources", dryRun)
+	}
+	reporter.Infof("Applying %d actions in account %s", len(planned), accountID)
+
+	return resources.DeleteInOrder(cmd.Context(), reporter, clients, planned, dryRun)
+}
+
+func init() {
+	// Add global flags
+	flags := Cmd.Flags()
+	arguments.AddFlags(flags)
+	arguments.AddProtectFlags(flags)
+	arguments.AddConfirmFlag(flags)
+
+	Cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Validate if the plan would be applied successfully")
+}
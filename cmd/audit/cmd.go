@@ -0,0 +1,33 @@
+/*
+Copyright © 2022 James Harrington <james@harrington.net.au>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package audit
+
+import (
+	"github.com/jharrington22/aws-resource/cmd/audit/show"
+	"github.com/spf13/cobra"
+)
+
+// AuditCmd represents the audit command
+var AuditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Query the audit log of deletions",
+	Long: `Every attempt to delete a resource is recorded to the audit log
+aws-resource audit show --since 7d --type ec2`,
+}
+
+func init() {
+	AuditCmd.AddCommand(show.Cmd)
+}
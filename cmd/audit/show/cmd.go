@@ -0,0 +1,154 @@
+/*
+Copyright © 2022 James Harrington <james@harrington.net.au>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package show
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jharrington22/aws-resource/cmd/resources"
+	"github.com/jharrington22/aws-resource/pkg/arguments"
+	"github.com/jharrington22/aws-resource/pkg/audit"
+	"github.com/jharrington22/aws-resource/pkg/duration"
+	"github.com/jharrington22/aws-resource/pkg/output"
+	rprtr "github.com/jharrington22/aws-resource/pkg/reporter"
+	"github.com/spf13/cobra"
+)
+
+const dateFormat = "2006-01-02"
+
+var (
+	since   string
+	until   string
+	account string
+	typ     string
+)
+
+// Cmd represents the audit show command
+var Cmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the attempts to delete resources",
+	Long: `Show the attempts to delete resources recorded to the audit log, optionally
+selected by date, account or resource type
+
+aws-resource audit show --since 2022-03-01 --account 123456789101 --type snapshots`,
+	Args: cobra.NoArgs,
+	RunE: run,
+}
+
+func run(cmd *cobra.Command, args []string) (err error) {
+	reporter := rprtr.CreateReporterOrExit()
+
+	format, err := output.ParseFormat(arguments.Output)
+	if err != nil {
+		return reporter.Errorf("%s", err)
+	}
+	if format == output.None {
+		format = output.Table
+	}
+
+	query := audit.Query{Account: account, Type: typ}
+	now := time.Now()
+	if since != "" {
+		query.Since, err = parseTime(since, now, false)
+		if err != nil {
+			return reporter.Errorf("Invalid --since: %s", err)
+		}
+	}
+	if until != "" {
+		query.Until, err = parseTime(until, now, true)
+		if err != nil {
+			return reporter.Errorf("Invalid --until: %s", err)
+		}
+	}
+
+	path, err := resources.AuditPath()
+	if err != nil {
+		return reporter.Errorf("Unable to find the audit log: %s", err)
+	}
+	entries, err := audit.Read(path)
+	if err != nil {
+		return reporter.Errorf("Unable to read the audit log: %s", err)
+	}
+	entries = query.Filter(entries)
+	if len(entries) == 0 {
+		reporter.Infof("No entries found in %s", path)
+		return nil
+	}
+
+	err = output.Write(os.Stdout, format, entries, rows(entries))
+	if err != nil {
+		return reporter.Errorf("Unable to write output: %s", err)
+	}
+	return nil
+}
+
+// parseTime parses a date, a timestamp, or a duration before now such as
+// 7d. A date given as end of a range includes the whole day.
+func parseTime(value string, now time.Time, end bool) (time.Time, error) {
+	if t, err := time.ParseInLocation(dateFormat, value, time.Local); err == nil {
+		if end {
+			t = t.AddDate(0, 0, 1)
+		}
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	d, err := duration.Parse(value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expected a date such as %s, a timestamp or a duration such as 7d", now.Format(dateFormat))
+	}
+	return now.Add(-d), nil
+}
+
+func rows(entries []audit.Entry) output.Rows {
+	rows := output.Rows{
+		Headers: []string{"time", "account", "region", "type", "id", "dry run", "result", "error", "caller"},
+	}
+	for _, e := range entries {
+		var problem []string
+		if e.ErrorCode != "" {
+			problem = append(problem, e.ErrorCode)
+		}
+		if e.Error != "" {
+			problem = append(problem, e.Error)
+		}
+		rows.Values = append(rows.Values, []string{
+			e.Time.Local().Format(time.RFC3339),
+			e.Account,
+			e.Region,
+			e.Type,
+			e.ID,
+			fmt.Sprintf("%t", e.DryRun),
+			e.Result,
+			strings.Join(problem, ": "),
+			e.Caller,
+		})
+	}
+	return rows
+}
+
+func init() {
+	flags := Cmd.Flags()
+	arguments.AddOutputFlag(flags)
+	flags.StringVar(&since, "since", "", "Only show attempts made since a date, timestamp or duration such as 7d")
+	flags.StringVar(&until, "until", "", "Only show attempts made before a timestamp or duration, or up to the end of a date")
+	flags.StringVar(&account, "account", "", "Only show attempts made in the account")
+	flags.StringVar(&typ, "type", "", "Only show attempts to delete resources of the type, such as ec2")
+}
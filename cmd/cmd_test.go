@@ -0,0 +1,1618 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/eks"
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/jharrington22/aws-resource/pkg/audit"
+	"github.com/jharrington22/aws-resource/pkg/aws"
+	"github.com/jharrington22/aws-resource/pkg/aws/fake"
+	"github.com/jharrington22/aws-resource/pkg/inventory"
+	"github.com/jharrington22/aws-resource/pkg/output"
+	"github.com/jharrington22/aws-resource/pkg/plan"
+	"github.com/jharrington22/aws-resource/pkg/resource"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func TestMain(m *testing.M) {
+	// Keep the configuration file of the user running the tests out of them
+	home, err := ioutil.TempDir("", "aws-resource")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv("HOME", home)
+	code := m.Run()
+	os.RemoveAll(home)
+	os.Exit(code)
+}
+
+// newBackend returns a backend with two regions holding one of each
+// resource type, and enough instances in us-east-1 to span several pages.
+func newBackend() *fake.Backend {
+	backend := fake.New("eu-west-1", "us-east-1")
+
+	for i := 0; i < 3; i++ {
+		backend.AddInstance("us-east-1", &ec2.Instance{
+			InstanceType: awssdk.String("m5.xlarge"),
+			Tags:         []*ec2.Tag{{Key: awssdk.String("Name"), Value: awssdk.String("worker")}},
+		})
+	}
+	backend.AddInstance("us-east-1", &ec2.Instance{
+		State: &ec2.InstanceState{Name: awssdk.String(ec2.InstanceStateNameStopped)},
+	})
+	backend.AddInstance("eu-west-1", &ec2.Instance{})
+
+	backend.AddVolume("us-east-1", &ec2.Volume{
+		Attachments: []*ec2.VolumeAttachment{{InstanceId: awssdk.String("i-1")}},
+	})
+	backend.AddVolume("us-east-1", &ec2.Volume{})
+	backend.AddVolume("eu-west-1", &ec2.Volume{})
+
+	backend.AddLoadBalancer("us-east-1", &elb.LoadBalancerDescription{})
+	backend.AddV2LoadBalancer("eu-west-1", &elbv2.LoadBalancer{})
+	backend.AddHostedZone(&route53.HostedZone{Name: awssdk.String("example.com.")})
+	backend.AddHostedZone(&route53.HostedZone{Name: awssdk.String("example.org.")})
+	backend.AddHostedZone(&route53.HostedZone{Name: awssdk.String("example.net.")})
+
+	return backend
+}
+
+// addImage adds an AMI backed by a new snapshot to the region.
+func addImage(backend *fake.Backend, region string) (*ec2.Image, *ec2.Snapshot) {
+	snapshot := backend.AddSnapshot(region, &ec2.Snapshot{})
+	image := backend.AddImage(region, &ec2.Image{
+		BlockDeviceMappings: []*ec2.BlockDeviceMapping{{
+			DeviceName: awssdk.String("/dev/xvda"),
+			Ebs:        &ec2.EbsBlockDevice{SnapshotId: snapshot.SnapshotId},
+		}},
+	})
+	return image, snapshot
+}
+
+// execute runs the root command with args against the backend and returns
+// what was written to stdout.
+func execute(t *testing.T, backend *fake.Backend, args ...string) (string, error) {
+	t.Helper()
+	return executeWith(t, backend.Factory(), args...)
+}
+
+// executeWith runs the root command with args using the factory and returns
+// what was written to stdout.
+func executeWith(t *testing.T, factory aws.Factory, args ...string) (string, error) {
+	t.Helper()
+
+	resetFlags(RootCmd)
+
+	stdout, stderr := os.Stdout, os.Stderr
+	outReader, outWriter, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	errReader, errWriter, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Stdout, os.Stderr = outWriter, errWriter
+
+	var out, log bytes.Buffer
+	done := make(chan struct{})
+	go func() {
+		_, _ = io.Copy(&out, outReader)
+		done <- struct{}{}
+	}()
+	go func() {
+		_, _ = io.Copy(&log, errReader)
+		done <- struct{}{}
+	}()
+
+	RootCmd.SetArgs(args)
+	err = RootCmd.ExecuteContext(aws.WithFactory(context.Background(), factory))
+
+	os.Stdout, os.Stderr = stdout, stderr
+	outWriter.Close()
+	errWriter.Close()
+	<-done
+	<-done
+	t.Logf("aws-resource %s\n%s", strings.Join(args, " "), log.String())
+
+	return out.String(), err
+}
+
+// resetFlags restores every flag of the command tree to its default value so
+// that flags set by one test don't leak into the next.
+func resetFlags(cmd *cobra.Command) {
+	reset := func(f *pflag.Flag) {
+		if slice, ok := f.Value.(pflag.SliceValue); ok {
+			_ = slice.Replace(nil)
+		} else {
+			_ = f.Value.Set(f.DefValue)
+		}
+		f.Changed = false
+	}
+	cmd.Flags().VisitAll(reset)
+	cmd.PersistentFlags().VisitAll(reset)
+	for _, c := range cmd.Commands() {
+		resetFlags(c)
+	}
+}
+
+// listRecords runs a list command with json output and decodes the records.
+func listRecords(t *testing.T, backend *fake.Backend, args ...string) []output.ResourceRecord {
+	t.Helper()
+	stdout, err := execute(t, backend, append(append([]string{"list"}, args...), "--output", "json")...)
+	if err != nil {
+		t.Fatalf("list %v failed: %s", args, err)
+	}
+	var records []output.ResourceRecord
+	if err := json.Unmarshal([]byte(stdout), &records); err != nil {
+		t.Fatalf("unable to decode output %q: %s", stdout, err)
+	}
+	return records
+}
+
+func TestList(t *testing.T) {
+	tests := []struct {
+		typ     string
+		regions []string
+	}{
+		{typ: "ec2", regions: []string{"eu-west-1", "us-east-1", "us-east-1", "us-east-1"}},
+		{typ: "volumes", regions: []string{"eu-west-1", "us-east-1", "us-east-1"}},
+		{typ: "elb", regions: []string{"us-east-1"}},
+		{typ: "elbv2", regions: []string{"eu-west-1"}},
+		{typ: "route53", regions: []string{"global", "global", "global"}},
+		{typ: "snapshots", regions: []string{"us-east-1"}},
+		{typ: "images", regions: []string{"us-east-1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.typ, func(t *testing.T) {
+			backend := newBackend()
+			addImage(backend, "us-east-1")
+
+			records := listRecords(t, backend, tt.typ)
+			if len(records) != len(tt.regions) {
+				t.Fatalf("expected %d records, got %d: %+v", len(tt.regions), len(records), records)
+			}
+			for i, r := range records {
+				if r.Type != tt.typ {
+					t.Errorf("expected type %s, got %s", tt.typ, r.Type)
+				}
+				if r.Region != tt.regions[i] {
+					t.Errorf("expected record %d in %s, got %s", i, tt.regions[i], r.Region)
+				}
+			}
+		})
+	}
+}
+
+func TestListAll(t *testing.T) {
+	backend := newBackend()
+	addImage(backend, "eu-west-1")
+
+	records := listRecords(t, backend, "all")
+
+	counts := map[string]int{}
+	for _, r := range records {
+		counts[r.Type]++
+	}
+	expected := map[string]int{
+		"ec2": 4, "elb": 1, "elbv2": 1, "images": 1, "route53": 3, "snapshots": 1, "volumes": 3,
+	}
+	for typ, n := range expected {
+		if counts[typ] != n {
+			t.Errorf("expected %d %s records, got %d", n, typ, counts[typ])
+		}
+	}
+}
+
+func TestListFormats(t *testing.T) {
+	backend := newBackend()
+
+	stdout, err := execute(t, backend, "list", "volumes", "--output", "csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[0], "type,region,id,name,state,created,attached") {
+		t.Errorf("unexpected csv output:\n%s", stdout)
+	}
+
+	stdout, err = execute(t, backend, "list", "elb", "--output", "table")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(stdout, "TYPE") || !strings.Contains(stdout, "us-east-1") {
+		t.Errorf("unexpected table output:\n%s", stdout)
+	}
+
+	stdout, err = execute(t, backend, "list", "elb", "--output", "yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(stdout, "- createdAt:") {
+		t.Errorf("unexpected yaml output:\n%s", stdout)
+	}
+
+	_, err = execute(t, backend, "list", "elb", "--output", "xml")
+	if err == nil {
+		t.Errorf("expected unsupported format to fail")
+	}
+}
+
+func TestListRegionFailure(t *testing.T) {
+	backend := newBackend()
+	backend.Fail("eu-west-1", errors.New("throttled"))
+
+	stdout, err := execute(t, backend, "list", "ec2", "--output", "json")
+	if err == nil {
+		t.Fatalf("expected failing region to be reported")
+	}
+
+	var records []output.ResourceRecord
+	if err := json.Unmarshal([]byte(stdout), &records); err != nil {
+		t.Fatalf("unable to decode output %q: %s", stdout, err)
+	}
+	if len(records) != 3 {
+		t.Errorf("expected the 3 instances in us-east-1, got %d", len(records))
+	}
+}
+
+func TestWhoAmI(t *testing.T) {
+	backend := newBackend()
+	if _, err := execute(t, backend, "whoami"); err != nil {
+		t.Fatal(err)
+	}
+
+	backend.Fail("us-east-1", errors.New("expired token"))
+	if _, err := execute(t, backend, "whoami"); err == nil {
+		t.Errorf("expected whoami to return the STS error")
+	}
+}
+
+func running(instances []*ec2.Instance) int {
+	var n int
+	for _, i := range instances {
+		if *i.State.Name == ec2.InstanceStateNameRunning {
+			n++
+		}
+	}
+	return n
+}
+
+func TestDeleteEC2(t *testing.T) {
+	backend := newBackend()
+
+	// The tests don't run interactively so nothing is deleted without --yes
+	if _, err := execute(t, backend, "delete", "ec2"); err == nil {
+		t.Fatalf("expected deletion without confirmation to be refused")
+	}
+	if n := running(backend.Instances("us-east-1")); n != 3 {
+		t.Fatalf("unconfirmed deletion terminated instances, %d still running", n)
+	}
+
+	if _, err := execute(t, backend, "delete", "ec2", "--dry-run"); err != nil {
+		t.Fatal(err)
+	}
+	if n := running(backend.Instances("us-east-1")); n != 3 {
+		t.Fatalf("dry run terminated instances, %d still running", n)
+	}
+
+	// Only --region is searched unless --all-regions is given
+	if _, err := execute(t, backend, "delete", "ec2", "--yes"); err != nil {
+		t.Fatal(err)
+	}
+	if running(backend.Instances("us-east-1")) != 0 || running(backend.Instances("eu-west-1")) == 0 {
+		t.Fatalf("expected only the instances in us-east-1 to be terminated")
+	}
+
+	if _, err := execute(t, backend, "delete", "ec2", "--all-regions", "--yes"); err != nil {
+		t.Fatal(err)
+	}
+	for _, region := range backend.Regions() {
+		if n := running(backend.Instances(region)); n != 0 {
+			t.Errorf("expected all instances in %s to be terminated, %d still running", region, n)
+		}
+	}
+}
+
+func TestSelector(t *testing.T) {
+	backend := fake.New("us-east-1")
+	tag := func(key, value string) *ec2.Tag {
+		return &ec2.Tag{Key: awssdk.String(key), Value: awssdk.String(value)}
+	}
+	dev := backend.AddInstance("us-east-1", &ec2.Instance{Tags: []*ec2.Tag{tag("env", "dev"), tag("cluster", "a")}})
+	kept := backend.AddInstance("us-east-1", &ec2.Instance{Tags: []*ec2.Tag{tag("env", "dev"), tag("keep", "")}})
+	backend.AddInstance("us-east-1", &ec2.Instance{Tags: []*ec2.Tag{tag("env", "prod")}})
+	backend.AddLoadBalancer("us-east-1", &elb.LoadBalancerDescription{},
+		&elb.Tag{Key: awssdk.String("env"), Value: awssdk.String("dev")})
+	backend.AddLoadBalancer("us-east-1", &elb.LoadBalancerDescription{})
+	backend.AddV2LoadBalancer("us-east-1", &elbv2.LoadBalancer{},
+		&elbv2.Tag{Key: awssdk.String("cluster"), Value: awssdk.String("b")})
+
+	tests := []struct {
+		selector string
+		typ      string
+		want     int
+	}{
+		{selector: "env=dev", typ: "ec2", want: 2},
+		{selector: "env=dev,!keep", typ: "ec2", want: 1},
+		{selector: "env!=dev", typ: "ec2", want: 1},
+		{selector: "cluster in (a,b)", typ: "ec2", want: 1},
+		{selector: "env=dev", typ: "elb", want: 1},
+		{selector: "!env", typ: "elb", want: 1},
+		{selector: "cluster notin (b)", typ: "elbv2", want: 0},
+		{selector: "cluster=b", typ: "elbv2", want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.typ+" "+tt.selector, func(t *testing.T) {
+			records := listRecords(t, backend, tt.typ, "--selector", tt.selector)
+			if len(records) != tt.want {
+				t.Fatalf("expected %d %s, got %d", tt.want, tt.typ, len(records))
+			}
+		})
+	}
+
+	if _, err := execute(t, backend, "list", "ec2", "--selector", "cluster in (a"); err == nil {
+		t.Fatalf("expected an invalid selector to fail")
+	}
+
+	if _, err := execute(t, backend, "delete", "ec2", "--selector", "env=dev,!keep", "--yes"); err != nil {
+		t.Fatal(err)
+	}
+	for _, i := range backend.Instances("us-east-1") {
+		terminated := *i.State.Name == ec2.InstanceStateNameTerminated
+		if terminated != (*i.InstanceId == *dev.InstanceId) {
+			t.Errorf("unexpected state %s for %s", *i.State.Name, *i.InstanceId)
+		}
+	}
+	if *kept.State.Name == ec2.InstanceStateNameTerminated {
+		t.Errorf("instance tagged keep was terminated")
+	}
+}
+
+func TestAge(t *testing.T) {
+	backend := fake.New("us-east-1")
+	now := time.Now()
+	old := backend.AddSnapshot("us-east-1", &ec2.Snapshot{StartTime: awssdk.Time(now.Add(-40 * 24 * time.Hour))})
+	backend.AddSnapshot("us-east-1", &ec2.Snapshot{StartTime: awssdk.Time(now.Add(-time.Hour))})
+	backend.AddImage("us-east-1", &ec2.Image{CreationDate: awssdk.String(now.Add(-10 * 24 * time.Hour).UTC().Format(time.RFC3339))})
+	backend.AddHostedZone(&route53.HostedZone{Name: awssdk.String("example.com.")})
+
+	tests := []struct {
+		args []string
+		want int
+	}{
+		{args: []string{"snapshots", "--older-than", "30d"}, want: 1},
+		{args: []string{"snapshots", "--newer-than", "2h"}, want: 1},
+		{args: []string{"snapshots", "--older-than", "30m", "--newer-than", "6w"}, want: 2},
+		{args: []string{"images", "--older-than", "1w"}, want: 1},
+		{args: []string{"images", "--older-than", "2w"}, want: 0},
+		{args: []string{"route53", "--newer-than", "1000w"}, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			if records := listRecords(t, backend, tt.args...); len(records) != tt.want {
+				t.Fatalf("expected %d resources, got %d", tt.want, len(records))
+			}
+		})
+	}
+
+	for _, args := range [][]string{
+		{"list", "snapshots", "--older-than", "30"},
+		{"list", "snapshots", "--older-than", "2h", "--newer-than", "1h"},
+	} {
+		if _, err := execute(t, backend, args...); err == nil {
+			t.Errorf("expected %v to fail", args)
+		}
+	}
+
+	if _, err := execute(t, backend, "delete", "snapshots", "--older-than", "30d", "--yes"); err != nil {
+		t.Fatal(err)
+	}
+	snapshots := backend.Snapshots("us-east-1")
+	if len(snapshots) != 1 || *snapshots[0].SnapshotId == *old.SnapshotId {
+		t.Fatalf("expected only %s to be deleted, %d snapshots left", *old.SnapshotId, len(snapshots))
+	}
+}
+
+func TestCost(t *testing.T) {
+	backend := fake.New("us-east-1")
+	for _, instanceType := range []string{"m5.xlarge", "m5.xlarge", "x9.huge"} {
+		backend.AddInstance("us-east-1", &ec2.Instance{InstanceType: awssdk.String(instanceType)})
+	}
+	backend.AddVolume("us-east-1", &ec2.Volume{VolumeType: awssdk.String("gp3"), Size: awssdk.Int64(100)})
+
+	costs := func(records []output.ResourceRecord) (total float64, unknown int) {
+		for _, r := range records {
+			if r.HourlyCost == nil {
+				unknown++
+				continue
+			}
+			total += *r.HourlyCost
+		}
+		return total, unknown
+	}
+
+	if records := listRecords(t, backend, "ec2"); records[0].HourlyCost != nil {
+		t.Fatalf("expected no cost without --cost")
+	}
+
+	total, unknown := costs(listRecords(t, backend, "ec2", "--cost"))
+	if total != 0.384 || unknown != 1 {
+		t.Fatalf("expected 0.384 per hour with 1 unknown instance, got %f with %d unknown", total, unknown)
+	}
+
+	records := listRecords(t, backend, "volumes", "--cost")
+	if len(records) != 1 || records[0].MonthlyCost == nil || *records[0].MonthlyCost < 7.99 || *records[0].MonthlyCost > 8.01 {
+		t.Fatalf("expected a gp3 volume of 100 GB to cost 8 per month, got %+v", records)
+	}
+
+	path := filepath.Join(t.TempDir(), "prices.yaml")
+	if err := os.WriteFile(path, []byte("instances:\n  x9.huge: 1.5\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	total, unknown = costs(listRecords(t, backend, "ec2", "--cost", "--price-table", path))
+	if total != 1.5 || unknown != 2 {
+		t.Fatalf("expected 1.5 per hour with 2 unknown instances, got %f with %d unknown", total, unknown)
+	}
+
+	stdout, err := execute(t, backend, "list", "all", "--cost", "--output", "csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if header := strings.SplitN(stdout, "\n", 2)[0]; !strings.Contains(header, "hourly-cost,monthly-cost") {
+		t.Fatalf("expected cost columns, got %q", header)
+	}
+}
+
+func TestAccounts(t *testing.T) {
+	management := fake.New("us-east-1")
+	management.AddInstance("us-east-1", &ec2.Instance{})
+	dev, prod := fake.New("us-east-1"), fake.New("us-east-1")
+	dev.AddInstance("us-east-1", &ec2.Instance{})
+	dev.AddInstance("us-east-1", &ec2.Instance{})
+	prod.AddInstance("us-east-1", &ec2.Instance{})
+
+	org := fake.NewOrganization(management)
+	org.AddMember("111111111111", "dev", dev)
+	org.AddMember("222222222222", "prod", prod)
+	org.AddMember("333333333333", "broken", nil)
+
+	records := func(args ...string) ([]output.ResourceRecord, error) {
+		stdout, err := executeWith(t, org.Factory(), append(args, "--output", "json")...)
+		var records []output.ResourceRecord
+		if decodeErr := json.Unmarshal([]byte(stdout), &records); decodeErr != nil {
+			t.Fatalf("unable to decode output %q: %s", stdout, decodeErr)
+		}
+		return records, err
+	}
+	accounts := func(records []output.ResourceRecord) map[string]int {
+		found := map[string]int{}
+		for _, r := range records {
+			found[r.Account]++
+		}
+		return found
+	}
+
+	found, err := records("list", "ec2", "--all-accounts", "--exclude-accounts", "333333333333")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]int{fake.DefaultAccountID: 1, "111111111111": 2, "222222222222": 1}
+	if got := accounts(found); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected instances %v, got %v", want, got)
+	}
+
+	// Accounts whose role can't be assumed fail without hiding the others
+	found, err = records("list", "ec2", "--all-accounts")
+	if err == nil {
+		t.Fatalf("expected an account failure to be returned")
+	}
+	if got := accounts(found); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected instances %v, got %v", want, got)
+	}
+
+	found, err = records("list", "all", "--accounts", "111111111111,222222222222")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = map[string]int{"111111111111": 2, "222222222222": 1}
+	if got := accounts(found); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected resources %v, got %v", want, got)
+	}
+
+	if _, err := executeWith(t, org.Factory(), "delete", "ec2", "--accounts", "222222222222", "--yes"); err != nil {
+		t.Fatal(err)
+	}
+	if n := running(prod.Instances("us-east-1")); n != 0 {
+		t.Fatalf("expected the prod instance to be terminated, %d still running", n)
+	}
+	if n := running(dev.Instances("us-east-1")); n != 2 {
+		t.Fatalf("expected dev instances to be kept, %d running", n)
+	}
+
+	for _, args := range [][]string{
+		{"list", "ec2", "--accounts", "1234"},
+		{"list", "ec2", "--accounts", "111111111111", "--account-role", "Admin"},
+		{"list", "ec2", "--all-accounts", "--role-arn", "arn:aws:iam::111111111111:role/Admin"},
+		{"delete", "ec2", "--all-accounts", "--plan-out", filepath.Join(t.TempDir(), "plan.json")},
+	} {
+		if _, err := executeWith(t, org.Factory(), args...); err == nil {
+			t.Errorf("expected %v to fail", args)
+		}
+	}
+}
+
+func TestConfig(t *testing.T) {
+	backend := fake.New("eu-west-1", "us-east-1")
+	tag := func(key, value string) *ec2.Tag {
+		return &ec2.Tag{Key: awssdk.String(key), Value: awssdk.String(value)}
+	}
+	dev := backend.AddInstance("us-east-1", &ec2.Instance{Tags: []*ec2.Tag{tag("env", "dev")}})
+	kept := backend.AddInstance("us-east-1", &ec2.Instance{Tags: []*ec2.Tag{tag("env", "dev"), tag("keep", "yes")}})
+	backend.AddInstance("us-east-1", &ec2.Instance{Tags: []*ec2.Tag{tag("env", "prod")}})
+	backend.AddInstance("eu-west-1", &ec2.Instance{Tags: []*ec2.Tag{tag("env", "dev")}})
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	err := os.WriteFile(path, []byte(`
+targets:
+  dev:
+    regions: [us-east-1]
+    selector: env=dev
+    protectedTags: [keep]
+    output: json
+`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	list := func(args ...string) []output.ResourceRecord {
+		t.Helper()
+		stdout, err := execute(t, backend, append([]string{"list", "ec2", "--config", path}, args...)...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var records []output.ResourceRecord
+		if err := json.Unmarshal([]byte(stdout), &records); err != nil {
+			t.Fatalf("unable to decode output %q: %s", stdout, err)
+		}
+		return records
+	}
+
+	if records := list("--target", "dev"); len(records) != 2 {
+		t.Fatalf("expected the dev instances of us-east-1, got %d instances", len(records))
+	}
+
+	// Flags override the file, and environment variables override both
+	if records := list("--target", "dev", "--regions", "eu-west-1"); len(records) != 1 || records[0].Region != "eu-west-1" {
+		t.Fatalf("expected the dev instance of eu-west-1, got %+v", records)
+	}
+	os.Setenv("AWS_RESOURCE_REGIONS", "eu-west-1,us-east-1")
+	os.Setenv("AWS_RESOURCE_TARGET", "dev")
+	defer os.Unsetenv("AWS_RESOURCE_REGIONS")
+	defer os.Unsetenv("AWS_RESOURCE_TARGET")
+	if records := list("--regions", "eu-west-1"); len(records) != 3 {
+		t.Fatalf("expected the dev instances of both regions, got %d instances", len(records))
+	}
+	os.Unsetenv("AWS_RESOURCE_REGIONS")
+	os.Unsetenv("AWS_RESOURCE_TARGET")
+
+	if _, err := execute(t, backend, "list", "ec2", "--config", path, "--target", "prod"); err == nil {
+		t.Fatalf("expected an unknown target to fail")
+	}
+	if _, err := execute(t, backend, "list", "ec2", "--config", filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Fatalf("expected a missing config file to fail")
+	}
+
+	if _, err := execute(t, backend, "delete", "ec2", "--config", path, "--target", "dev", "--yes"); err != nil {
+		t.Fatal(err)
+	}
+	for _, i := range backend.Instances("us-east-1") {
+		terminated := *i.State.Name == ec2.InstanceStateNameTerminated
+		if terminated != (*i.InstanceId == *dev.InstanceId) {
+			t.Errorf("unexpected state %s for %s", *i.State.Name, *i.InstanceId)
+		}
+	}
+	if *kept.State.Name == ec2.InstanceStateNameTerminated {
+		t.Errorf("protected instance was terminated")
+	}
+	if n := running(backend.Instances("eu-west-1")); n != 1 {
+		t.Errorf("expected the instance of eu-west-1 to be kept, %d running", n)
+	}
+}
+
+func TestProtect(t *testing.T) {
+	backend := fake.New("us-east-1")
+	tagged := backend.AddInstance("us-east-1", &ec2.Instance{
+		Tags: []*ec2.Tag{{Key: awssdk.String("aws-resource/protect"), Value: awssdk.String("true")}},
+	})
+	denied := backend.AddInstance("us-east-1", &ec2.Instance{})
+	late := backend.AddInstance("us-east-1", &ec2.Instance{})
+	backend.AddInstance("us-east-1", &ec2.Instance{})
+	golden := backend.AddImage("us-east-1", &ec2.Image{Name: awssdk.String("golden-2022")})
+
+	denyList := filepath.Join(t.TempDir(), "deny-list")
+	err := os.WriteFile(denyList, []byte(*denied.InstanceId+"\nimages golden-*\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Resources tagged after a plan was saved are protected when applying it
+	path := filepath.Join(t.TempDir(), "plan.json")
+	if _, err := execute(t, backend, "delete", "ec2", "--plan-out", path); err != nil {
+		t.Fatal(err)
+	}
+	p, err := plan.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Actions) != 3 {
+		t.Fatalf("expected the tagged instance to be left out of the plan, got %d actions", len(p.Actions))
+	}
+	late.Tags = append(late.Tags, &ec2.Tag{Key: awssdk.String("aws-resource/protect"), Value: awssdk.String("true")})
+
+	if _, err := execute(t, backend, "apply", path, "--deny-list", denyList, "--yes"); err != nil {
+		t.Fatal(err)
+	}
+	for _, i := range backend.Instances("us-east-1") {
+		protected := *i.InstanceId == *tagged.InstanceId || *i.InstanceId == *denied.InstanceId || *i.InstanceId == *late.InstanceId
+		if terminated := *i.State.Name == ec2.InstanceStateNameTerminated; terminated == protected {
+			t.Errorf("unexpected state %s for %s", *i.State.Name, *i.InstanceId)
+		}
+	}
+
+	// Resources given by ID are looked up to be checked
+	if _, err := execute(t, backend, "delete", "images", "--image-id", *golden.ImageId, "--deny-list", denyList, "--yes"); err != nil {
+		t.Fatal(err)
+	}
+	if len(backend.Images("us-east-1")) != 1 {
+		t.Errorf("protected image was deregistered")
+	}
+
+	if _, err := execute(t, backend, "delete", "images", "--deny-list", filepath.Join(t.TempDir(), "missing"), "--yes"); err == nil {
+		t.Errorf("expected a missing deny-list to fail")
+	}
+}
+
+func TestAudit(t *testing.T) {
+	backend := fake.New("us-east-1")
+	backend.AddInstance("us-east-1", &ec2.Instance{})
+	protected := backend.AddInstance("us-east-1", &ec2.Instance{
+		Tags: []*ec2.Tag{{Key: awssdk.String("aws-resource/protect"), Value: awssdk.String("true")}},
+	})
+	image, _ := addImage(backend, "us-east-1")
+	log := filepath.Join(t.TempDir(), "audit.log")
+
+	if _, err := execute(t, backend, "delete", "images", "--image-id", *image.ImageId, "--dry-run", "--audit-log", log); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := execute(t, backend, "delete", "ec2", "--audit-log", log, "--yes"); err != nil {
+		t.Fatal(err)
+	}
+
+	show := func(args ...string) []audit.Entry {
+		t.Helper()
+		stdout, err := execute(t, backend, append([]string{"audit", "show", "--audit-log", log, "--output", "json"}, args...)...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stdout == "" {
+			return nil
+		}
+		var entries []audit.Entry
+		if err := json.Unmarshal([]byte(stdout), &entries); err != nil {
+			t.Fatalf("unable to decode output %q: %s", stdout, err)
+		}
+		return entries
+	}
+
+	entries := show()
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %+v", entries)
+	}
+	if e := entries[0]; e.Type != "images" || e.ID != *image.ImageId || !e.DryRun || e.Result != audit.Succeeded {
+		t.Errorf("unexpected entry for the dry run: %+v", e)
+	}
+	if e := entries[1]; e.Type != "ec2" || e.DryRun || e.Result != audit.Succeeded ||
+		e.Account != fake.DefaultAccountID || e.Caller != "arn:aws:iam::123456789012:user/fake" {
+		t.Errorf("unexpected entry for the instance: %+v", e)
+	}
+
+	// Protected resources missing from the plan's tags are recorded as
+	// refused
+	p := plan.New(fake.DefaultAccountID)
+	p.Delete(&resource.Resource{Type: "ec2", ID: *protected.InstanceId, Region: "us-east-1"})
+	path := filepath.Join(t.TempDir(), "plan.json")
+	if err := p.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := execute(t, backend, "apply", path, "--audit-log", log, "--yes"); err != nil {
+		t.Fatal(err)
+	}
+	entries = show("--type", "ec2")
+	if len(entries) != 2 || entries[1].Result != audit.Protected || entries[1].Tags["aws-resource/protect"] != "true" {
+		t.Fatalf("expected the protected instance to be recorded, got %+v", entries)
+	}
+
+	if entries := show("--account", "210987654321"); len(entries) != 0 {
+		t.Errorf("expected no entries for another account, got %+v", entries)
+	}
+	if entries := show("--since", time.Now().Add(time.Hour).Format(time.RFC3339)); len(entries) != 0 {
+		t.Errorf("expected no entries in the future, got %+v", entries)
+	}
+	if entries := show("--since", "1h", "--until", time.Now().Format("2006-01-02")); len(entries) != 3 {
+		t.Errorf("expected every entry of today, got %+v", entries)
+	}
+	if _, err := execute(t, backend, "audit", "show", "--audit-log", log, "--since", "yesterday"); err == nil {
+		t.Errorf("expected an invalid date to fail")
+	}
+
+	// Nothing is deleted when the audit log can't be written
+	backend.AddInstance("us-east-1", &ec2.Instance{})
+	if _, err := execute(t, backend, "delete", "ec2", "--audit-log", t.TempDir(), "--yes"); err == nil {
+		t.Errorf("expected deleting without an audit log to fail")
+	}
+	if n := running(backend.Instances("us-east-1")); n != 2 {
+		t.Errorf("expected the instances to be kept without an audit log, %d running", n)
+	}
+}
+
+func TestInventory(t *testing.T) {
+	backend := newBackend()
+	snapshot := backend.AddSnapshot("us-east-1", &ec2.Snapshot{})
+	dir := t.TempDir()
+	before, after := filepath.Join(dir, "before.json"), filepath.Join(dir, "after.json")
+
+	if _, err := execute(t, backend, "inventory", "save", "-o", before); err != nil {
+		t.Fatal(err)
+	}
+
+	added := backend.AddInstance("eu-west-1", &ec2.Instance{})
+	instance := backend.Instances("us-east-1")[0]
+	instance.Tags = append(instance.Tags, &ec2.Tag{Key: awssdk.String("env"), Value: awssdk.String("dev")})
+	if _, err := backend.Client("us-east-1").DeleteSnapshot(&ec2.DeleteSnapshotInput{SnapshotId: snapshot.SnapshotId}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := execute(t, backend, "inventory", "save", "-o", after); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout, err := execute(t, backend, "inventory", "diff", before, after, "--output", "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var records []inventory.Record
+	if err := json.Unmarshal([]byte(stdout), &records); err != nil {
+		t.Fatalf("unable to decode output %q: %s", stdout, err)
+	}
+	want := []struct{ change, id, fields string }{
+		{inventory.Added, *added.InstanceId, ""},
+		{inventory.Removed, *snapshot.SnapshotId, ""},
+		{inventory.Changed, *instance.InstanceId, "tags.env"},
+	}
+	if len(records) != len(want) {
+		t.Fatalf("expected %d changes, got %+v", len(want), records)
+	}
+	for i, w := range want {
+		r := records[i]
+		if r.Change != w.change || r.Resource.ID != w.id || strings.Join(r.Fields, " ") != w.fields {
+			t.Errorf("got %s %s %v, want %s %s %s", r.Change, r.Resource.ID, r.Fields, w.change, w.id, w.fields)
+		}
+	}
+
+	stdout, err = execute(t, backend, "inventory", "diff", after, after)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stdout != "" {
+		t.Errorf("expected no differences, got %q", stdout)
+	}
+
+	// An inventory missing a region would report its resources as removed
+	backend.Fail("eu-west-1", errors.New("throttled"))
+	partial := filepath.Join(dir, "partial.json")
+	if _, err := execute(t, backend, "inventory", "save", "-o", partial); err == nil {
+		t.Fatalf("expected failing region to be reported")
+	}
+	if _, err := os.Stat(partial); !os.IsNotExist(err) {
+		t.Errorf("expected no inventory to be saved, got %v", err)
+	}
+}
+
+func TestDeleteImages(t *testing.T) {
+	backend := newBackend()
+	image, _ := addImage(backend, "us-east-1")
+	addImage(backend, "us-east-1")
+	addImage(backend, "eu-west-1")
+
+	if _, err := execute(t, backend, "delete", "images", "--image-id", *image.ImageId, "--yes"); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(backend.Images("us-east-1")); n != 1 {
+		t.Fatalf("expected only %s to be deregistered, %d images left", *image.ImageId, n)
+	}
+
+	if _, err := execute(t, backend, "delete", "images", "--dry-run"); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(backend.Images("us-east-1")) + len(backend.Images("eu-west-1")); n != 2 {
+		t.Fatalf("dry run deregistered images, %d left", n)
+	}
+
+	if _, err := execute(t, backend, "delete", "images", "--yes"); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(backend.Images("us-east-1")) + len(backend.Images("eu-west-1")); n != 0 {
+		t.Errorf("expected every image to be deregistered, %d left", n)
+	}
+}
+
+func TestDeleteImagesWithSnapshots(t *testing.T) {
+	backend := fake.New("us-east-1")
+	image, snapshot := addImage(backend, "us-east-1")
+	shared, sharedSnapshot := addImage(backend, "us-east-1")
+	backend.AddImage("us-east-1", &ec2.Image{
+		BlockDeviceMappings: []*ec2.BlockDeviceMapping{{
+			DeviceName: awssdk.String("/dev/xvda"),
+			Ebs:        &ec2.EbsBlockDevice{SnapshotId: sharedSnapshot.SnapshotId},
+		}},
+	})
+	unrelated := backend.AddSnapshot("us-east-1", &ec2.Snapshot{})
+
+	if _, err := execute(t, backend, "delete", "images", "--image-id", *image.ImageId, "--with-snapshots", "--dry-run"); err != nil {
+		t.Fatal(err)
+	}
+	if len(backend.Images("us-east-1")) != 3 || len(backend.Snapshots("us-east-1")) != 3 {
+		t.Fatalf("dry run deleted resources")
+	}
+
+	if _, err := execute(t, backend, "delete", "images", "--image-id", *image.ImageId, "--with-snapshots", "--yes"); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range backend.Snapshots("us-east-1") {
+		if *s.SnapshotId == *snapshot.SnapshotId {
+			t.Fatalf("expected the snapshot of %s to be deleted", *image.ImageId)
+		}
+	}
+
+	// Snapshots shared with an image that isn't deregistered are kept
+	if _, err := execute(t, backend, "delete", "images", "--image-id", *shared.ImageId, "--with-snapshots", "--yes"); err != nil {
+		t.Fatal(err)
+	}
+	if len(backend.Images("us-east-1")) != 1 || len(backend.Snapshots("us-east-1")) != 2 {
+		t.Fatalf("expected the shared snapshot to be kept, %d images and %d snapshots left",
+			len(backend.Images("us-east-1")), len(backend.Snapshots("us-east-1")))
+	}
+
+	if _, err := execute(t, backend, "delete", "images", "--with-snapshots", "--yes"); err != nil {
+		t.Fatal(err)
+	}
+	snapshots := backend.Snapshots("us-east-1")
+	if len(backend.Images("us-east-1")) != 0 || len(snapshots) != 1 || *snapshots[0].SnapshotId != *unrelated.SnapshotId {
+		t.Errorf("expected only the unrelated snapshot to be left, got %v", snapshots)
+	}
+}
+
+func TestDeleteSnapshots(t *testing.T) {
+	backend := newBackend()
+	_, inUse := addImage(backend, "us-east-1")
+	unused := backend.AddSnapshot("us-east-1", &ec2.Snapshot{})
+	backend.AddSnapshot("eu-west-1", &ec2.Snapshot{})
+
+	if _, err := execute(t, backend, "delete", "snapshots", "--snapshot-id", *unused.SnapshotId, "--dry-run"); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(backend.Snapshots("us-east-1")); n != 2 {
+		t.Fatalf("dry run deleted snapshots, %d left", n)
+	}
+
+	// Snapshots backing an image are skipped without --delete-backing-image
+	if _, err := execute(t, backend, "delete", "snapshots", "--yes"); err != nil {
+		t.Fatal(err)
+	}
+	snapshots := backend.Snapshots("us-east-1")
+	if len(snapshots) != 1 || *snapshots[0].SnapshotId != *inUse.SnapshotId {
+		t.Fatalf("expected only the in use snapshot %s to be left, got %v", *inUse.SnapshotId, snapshots)
+	}
+	if n := len(backend.Snapshots("eu-west-1")); n != 1 {
+		t.Fatalf("expected snapshots outside of --region to be left, %d left", n)
+	}
+
+	if _, err := execute(t, backend, "delete", "snapshots", "--all-regions", "--delete-backing-image", "--yes"); err != nil {
+		t.Fatal(err)
+	}
+	for _, region := range backend.Regions() {
+		if n := len(backend.Snapshots(region)); n != 0 {
+			t.Errorf("expected every snapshot in %s to be deleted, %d left", region, n)
+		}
+	}
+	if n := len(backend.Images("us-east-1")); n != 0 {
+		t.Errorf("expected the backing image to be deregistered, %d left", n)
+	}
+}
+
+func TestDeleteVolumes(t *testing.T) {
+	backend := fake.New("us-east-1")
+	attached := backend.AddVolume("us-east-1", &ec2.Volume{
+		Attachments: []*ec2.VolumeAttachment{{InstanceId: awssdk.String("i-0123456789")}},
+	})
+	unattached := backend.AddVolume("us-east-1", &ec2.Volume{Size: awssdk.Int64(8)})
+	backend.AddVolume("us-east-1", &ec2.Volume{})
+
+	if _, err := execute(t, backend, "delete", "volumes", "--dry-run", "--snapshot-first"); err != nil {
+		t.Fatal(err)
+	}
+	if len(backend.Volumes("us-east-1")) != 3 || len(backend.Snapshots("us-east-1")) != 0 {
+		t.Fatalf("dry run deleted volumes or created snapshots")
+	}
+
+	// Attached volumes are skipped by default
+	if _, err := execute(t, backend, "delete", "volumes", "--volume-id", *attached.VolumeId, "--yes"); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(backend.Volumes("us-east-1")); n != 3 {
+		t.Fatalf("expected the attached volume to be skipped, %d volumes left", n)
+	}
+
+	if _, err := execute(t, backend, "delete", "volumes", "--volume-id", *unattached.VolumeId, "--snapshot-first", "--yes"); err != nil {
+		t.Fatal(err)
+	}
+	snapshots := backend.Snapshots("us-east-1")
+	if len(snapshots) != 1 {
+		t.Fatalf("expected a final snapshot, got %v", snapshots)
+	}
+	if s := snapshots[0]; *s.VolumeId != *unattached.VolumeId || *s.State != ec2.SnapshotStateCompleted {
+		t.Errorf("expected a completed snapshot of %s, got %v", *unattached.VolumeId, s)
+	}
+	if n := len(backend.Volumes("us-east-1")); n != 2 {
+		t.Fatalf("expected %s to be deleted, %d volumes left", *unattached.VolumeId, n)
+	}
+
+	if _, err := execute(t, backend, "delete", "volumes", "--yes"); err != nil {
+		t.Fatal(err)
+	}
+	volumes := backend.Volumes("us-east-1")
+	if len(volumes) != 1 || *volumes[0].VolumeId != *attached.VolumeId {
+		t.Errorf("expected only the attached volume to be left, got %v", volumes)
+	}
+
+	if _, err := execute(t, backend, "delete", "volumes", "--volume-id", "vol-missing", "--yes"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Volumes protected after being listed aren't snapshotted
+	late := backend.AddVolume("us-east-1", &ec2.Volume{})
+	factory := tagOnGet{backend: backend, volume: late, tag: &ec2.Tag{Key: awssdk.String("aws-resource/protect"), Value: awssdk.String("true")}}
+	if _, err := executeWith(t, factory, "delete", "volumes", "--volume-id", *late.VolumeId, "--snapshot-first", "--yes"); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(backend.Snapshots("us-east-1")); n != 1 {
+		t.Errorf("expected the protected volume not to be snapshotted, got %d snapshots", n)
+	}
+	if n := len(backend.Volumes("us-east-1")); n != 2 {
+		t.Errorf("expected the protected volume to be kept, %d volumes left", n)
+	}
+}
+
+// tagOnGet is a factory whose clients tag the volume when it's looked up on
+// its own, as if it was tagged after being listed.
+type tagOnGet struct {
+	backend *fake.Backend
+	volume  *ec2.Volume
+	tag     *ec2.Tag
+}
+
+func (f tagOnGet) Client(target aws.Target, region string) (aws.Client, error) {
+	return &tagOnGetClient{Client: f.backend.Client(region), factory: f}, nil
+}
+
+type tagOnGetClient struct {
+	aws.Client
+	factory tagOnGet
+}
+
+func (c *tagOnGetClient) DescribeVolumes(input *ec2.DescribeVolumesInput) (*ec2.DescribeVolumesOutput, error) {
+	if v := c.factory.volume; len(input.VolumeIds) == 1 && *input.VolumeIds[0] == *v.VolumeId {
+		v.Tags = append(v.Tags, c.factory.tag)
+	}
+	return c.Client.DescribeVolumes(input)
+}
+
+func TestDeleteLoadBalancers(t *testing.T) {
+	backend := fake.New("us-east-1", "eu-west-1")
+	backend.AddLoadBalancer("us-east-1", &elb.LoadBalancerDescription{})
+	backend.AddLoadBalancer("eu-west-1", &elb.LoadBalancerDescription{})
+
+	if _, err := execute(t, backend, "delete", "elb", "--dry-run"); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(backend.LoadBalancers("us-east-1")); n != 1 {
+		t.Fatalf("dry run deleted load balancers, %d left", n)
+	}
+	if _, err := execute(t, backend, "delete", "elb", "--yes"); err != nil {
+		t.Fatal(err)
+	}
+	if len(backend.LoadBalancers("us-east-1")) != 0 || len(backend.LoadBalancers("eu-west-1")) != 1 {
+		t.Fatalf("expected only the load balancer in us-east-1 to be deleted")
+	}
+
+	protected := backend.AddV2LoadBalancer("us-east-1", &elbv2.LoadBalancer{})
+	backend.SetV2LoadBalancerAttribute("us-east-1", *protected.LoadBalancerArn, "deletion_protection.enabled", "true")
+	lb := backend.AddV2LoadBalancer("us-east-1", &elbv2.LoadBalancer{})
+	other := backend.AddV2LoadBalancer("us-east-1", &elbv2.LoadBalancer{}, &elbv2.Tag{Key: awssdk.String("env"), Value: awssdk.String("prod")})
+	unused := backend.AddTargetGroup("us-east-1", &elbv2.TargetGroup{LoadBalancerArns: []*string{lb.LoadBalancerArn}})
+	shared := backend.AddTargetGroup("us-east-1", &elbv2.TargetGroup{LoadBalancerArns: []*string{lb.LoadBalancerArn, other.LoadBalancerArn}})
+
+	// Listing doesn't look the attributes of each load balancer up
+	for _, r := range listRecords(t, backend, "elbv2") {
+		if _, ok := r.Properties["deletion-protection"]; ok {
+			t.Errorf("expected deletion protection not to be listed for %s", r.Name)
+		}
+	}
+
+	if _, err := execute(t, backend, "delete", "elbv2", "--selector", "!env", "--with-target-groups", "--dry-run"); err != nil {
+		t.Fatal(err)
+	}
+	if len(backend.V2LoadBalancers("us-east-1")) != 3 || len(backend.TargetGroups("us-east-1")) != 2 {
+		t.Fatalf("dry run deleted resources")
+	}
+
+	// Load balancers with deletion protection are skipped
+	if _, err := execute(t, backend, "delete", "elbv2", "--selector", "!env", "--with-target-groups", "--yes"); err != nil {
+		t.Fatal(err)
+	}
+	lbs := backend.V2LoadBalancers("us-east-1")
+	if len(lbs) != 2 || *lbs[0].LoadBalancerArn != *protected.LoadBalancerArn {
+		t.Fatalf("expected %s to be deleted and the protected load balancer kept, got %v", *lb.LoadBalancerName, lbs)
+	}
+	tgs := backend.TargetGroups("us-east-1")
+	if len(tgs) != 1 || *tgs[0].TargetGroupArn != *shared.TargetGroupArn {
+		t.Errorf("expected %s to be deleted and the shared target group kept, got %v", *unused.TargetGroupName, tgs)
+	}
+
+	// Target groups are only deleted along with their load balancers
+	if c, _, err := RootCmd.Find([]string{"delete", "targetgroups"}); err == nil && c.Name() == "targetgroups" {
+		t.Errorf("expected target groups not to have a delete command")
+	}
+}
+
+func TestDeleteRoute53(t *testing.T) {
+	backend := fake.New("us-east-1")
+	zone := backend.AddHostedZone(&route53.HostedZone{Name: awssdk.String("example.com.")})
+	other := backend.AddHostedZone(&route53.HostedZone{Name: awssdk.String("example.org.")})
+	record := func(name, typ, value string) *route53.ResourceRecordSet {
+		return &route53.ResourceRecordSet{
+			Name:            awssdk.String(name),
+			Type:            awssdk.String(typ),
+			TTL:             awssdk.Int64(300),
+			ResourceRecords: []*route53.ResourceRecord{{Value: awssdk.String(value)}},
+		}
+	}
+	backend.AddRecordSet(*zone.Id, record("www.example.com.", "A", "192.0.2.1"))
+	backend.AddRecordSet(*zone.Id, record("dev.example.com.", "NS", "ns-3.awsdns-03.net."))
+	weighted := record("api.example.com.", "CNAME", "www.example.com.")
+	weighted.SetIdentifier, weighted.Weight = awssdk.String("blue"), awssdk.Int64(10)
+	backend.AddRecordSet(*zone.Id, weighted)
+	backend.AddRecordSet(*other.Id, record("www.example.org.", "A", "192.0.2.2"))
+
+	stdout, err := execute(t, backend, "delete", "route53", "--zone-name", "example.com", "--dry-run")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var batch struct {
+		Changes []struct {
+			Action            string
+			ResourceRecordSet struct{ Name, Type string }
+		}
+	}
+	if err := json.Unmarshal([]byte(stdout), &batch); err != nil {
+		t.Fatalf("unable to decode change batch %q: %s", stdout, err)
+	}
+	// Fields that aren't set are left out rather than written as null
+	if strings.Contains(stdout, "null") || !strings.Contains(stdout, `"Weight": 10`) {
+		t.Errorf("unexpected change batch %s", stdout)
+	}
+	if len(batch.Changes) != 3 {
+		t.Fatalf("expected 3 changes, got %+v", batch.Changes)
+	}
+	for _, c := range batch.Changes {
+		if c.Action != "DELETE" || c.ResourceRecordSet.Name == "example.com." {
+			t.Errorf("unexpected change %+v", c)
+		}
+	}
+	if len(backend.HostedZones()) != 2 || len(backend.RecordSets(*zone.Id)) != 5 {
+		t.Fatalf("dry run deleted record sets")
+	}
+
+	if _, err := execute(t, backend, "delete", "route53", "--zone-id", *zone.Id, "--yes"); err != nil {
+		t.Fatal(err)
+	}
+	zones := backend.HostedZones()
+	if len(zones) != 1 || *zones[0].Id != *other.Id {
+		t.Fatalf("expected only %s to be left, got %v", *other.Name, zones)
+	}
+	if n := len(backend.RecordSets(*other.Id)); n != 3 {
+		t.Errorf("expected the record sets of %s to be kept, %d left", *other.Name, n)
+	}
+}
+
+func TestDeleteOrder(t *testing.T) {
+	backend := newBackend()
+	image, inUse := addImage(backend, "us-east-1")
+	unused := backend.AddSnapshot("us-east-1", &ec2.Snapshot{})
+
+	// A dry run can't validate deleting a snapshot whose image is still
+	// registered
+	if _, err := execute(t, backend, "delete", "snapshots", "--delete-backing-image", "--dry-run"); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(backend.Snapshots("us-east-1")) + len(backend.Images("us-east-1")); n != 3 {
+		t.Fatalf("dry run deleted resources, %d left", n)
+	}
+
+	path := filepath.Join(t.TempDir(), "plan.json")
+	if _, err := execute(t, backend, "delete", "snapshots", "--delete-backing-image", "--plan-out", path); err != nil {
+		t.Fatal(err)
+	}
+
+	// Snapshots used by an image protected since the plan was saved are kept
+	image.Tags = append(image.Tags, &ec2.Tag{Key: awssdk.String("aws-resource/protect"), Value: awssdk.String("true")})
+	if _, err := execute(t, backend, "apply", path, "--yes"); err != nil {
+		t.Fatal(err)
+	}
+	snapshots := backend.Snapshots("us-east-1")
+	if len(snapshots) != 1 || *snapshots[0].SnapshotId != *inUse.SnapshotId {
+		t.Errorf("expected only the snapshot of the protected image to be left, got %v", snapshots)
+	}
+	if len(backend.Images("us-east-1")) != 1 {
+		t.Errorf("protected image was deregistered")
+	}
+	for _, s := range snapshots {
+		if *s.SnapshotId == *unused.SnapshotId {
+			t.Errorf("expected %s to be deleted", *unused.SnapshotId)
+		}
+	}
+}
+
+func TestDeleteOrderWaits(t *testing.T) {
+	backend := fake.New("us-east-1")
+	instance := backend.AddInstance("us-east-1", &ec2.Instance{})
+	volume := backend.AddVolume("us-east-1", &ec2.Volume{
+		Attachments: []*ec2.VolumeAttachment{{InstanceId: instance.InstanceId}},
+	})
+
+	// Volumes are only detached once their instance is terminated, which
+	// is waited for before deleting them
+	p := plan.New(fake.DefaultAccountID)
+	p.Delete(&resource.Resource{Type: "volumes", ID: *volume.VolumeId, Region: "us-east-1"})
+	p.Delete(&resource.Resource{Type: "ec2", ID: *instance.InstanceId, Region: "us-east-1",
+		Properties: map[string]string{"volumes": *volume.VolumeId}})
+	path := filepath.Join(t.TempDir(), "plan.json")
+	if err := p.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := execute(t, backend, "apply", path, "--yes"); err != nil {
+		t.Fatal(err)
+	}
+	if n := running(backend.Instances("us-east-1")); n != 0 {
+		t.Errorf("expected the instance to be terminated, %d running", n)
+	}
+	if n := len(backend.Volumes("us-east-1")); n != 0 {
+		t.Errorf("expected the volume to be deleted once detached, %d left", n)
+	}
+}
+
+func TestPlanApply(t *testing.T) {
+	backend := newBackend()
+	path := filepath.Join(t.TempDir(), "plan.json")
+
+	if _, err := execute(t, backend, "delete", "ec2", "--all-regions", "--plan-out", path); err != nil {
+		t.Fatal(err)
+	}
+	if n := running(backend.Instances("us-east-1")); n != 3 {
+		t.Fatalf("saving a plan terminated instances, %d still running", n)
+	}
+
+	p, err := plan.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.AccountID != fake.DefaultAccountID || len(p.Actions) != 4 {
+		t.Fatalf("unexpected plan: %+v", p)
+	}
+
+	// Instances started after the plan was saved are left alone
+	late := backend.AddInstance("us-east-1", &ec2.Instance{})
+
+	backend.SetIdentity("210987654321", "arn:aws:iam::210987654321:user/other")
+	if _, err := execute(t, backend, "apply", path, "--yes"); err == nil {
+		t.Fatalf("expected plan for another account to be refused")
+	}
+	if n := running(backend.Instances("us-east-1")); n != 4 {
+		t.Fatalf("refused plan terminated instances, %d still running", n)
+	}
+
+	backend.SetIdentity(fake.DefaultAccountID, "arn:aws:iam::123456789012:user/fake")
+	if _, err := execute(t, backend, "apply", path, "--yes"); err != nil {
+		t.Fatal(err)
+	}
+	instances := backend.Instances("us-east-1")
+	if n := running(instances); n != 1 {
+		t.Fatalf("expected only the instance started after the plan to be running, %d running", n)
+	}
+	for _, i := range instances {
+		if *i.State.Name == ec2.InstanceStateNameRunning && *i.InstanceId != *late.InstanceId {
+			t.Errorf("expected %s to be running, got %s", *late.InstanceId, *i.InstanceId)
+		}
+	}
+}
+
+func TestPlanSnapshots(t *testing.T) {
+	backend := newBackend()
+	image, inUse := addImage(backend, "us-east-1")
+	unused := backend.AddSnapshot("us-east-1", &ec2.Snapshot{})
+	path := filepath.Join(t.TempDir(), "plan.json")
+
+	if _, err := execute(t, backend, "delete", "snapshots", "--plan-out", path); err != nil {
+		t.Fatal(err)
+	}
+	p, err := plan.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Actions) != 1 || p.Actions[0].ID != *unused.SnapshotId {
+		t.Fatalf("expected only the unused snapshot to be planned, got %+v", p.Actions)
+	}
+
+	if _, err := execute(t, backend, "delete", "snapshots", "--delete-backing-image", "--plan-out", path); err != nil {
+		t.Fatal(err)
+	}
+	p, err = plan.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, a := range p.Actions {
+		ids = append(ids, a.ID)
+	}
+	expected := []string{*image.ImageId, *inUse.SnapshotId, *unused.SnapshotId}
+	if strings.Join(ids, ",") != strings.Join(expected, ",") {
+		t.Fatalf("expected plan %v, got %v", expected, ids)
+	}
+
+	if _, err := execute(t, backend, "apply", path, "--yes"); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(backend.Snapshots("us-east-1")) + len(backend.Images("us-east-1")); n != 0 {
+		t.Errorf("expected the plan to remove the image and snapshots, %d left", n)
+	}
+}
+
+func TestNatGatewaysAndEIPs(t *testing.T) {
+	backend := fake.New("us-east-1")
+	gatewayAddress := backend.AddAddress("us-east-1", &ec2.Address{})
+	instanceAddress := backend.AddAddress("us-east-1", &ec2.Address{InstanceId: awssdk.String("i-0123456789")})
+	idle := backend.AddAddress("us-east-1", &ec2.Address{})
+	gateway := backend.AddNatGateway("us-east-1", &ec2.NatGateway{
+		NatGatewayAddresses: []*ec2.NatGatewayAddress{{AllocationId: gatewayAddress.AllocationId}},
+	})
+
+	stdout, err := execute(t, backend, "list", "eips", "--output", "csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected 3 addresses, got:\n%s", stdout)
+	}
+	for _, line := range lines[1:] {
+		if strings.Contains(line, *idle.AllocationId) != strings.Contains(line, ",false,") {
+			t.Errorf("expected only %s to be unassociated, got %s", *idle.AllocationId, line)
+		}
+	}
+
+	stdout, err = execute(t, backend, "list", "natgateways", "--output", "csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(stdout, *gateway.NatGatewayId) || !strings.Contains(stdout, *gatewayAddress.PublicIp) {
+		t.Errorf("expected %s with %s, got:\n%s", *gateway.NatGatewayId, *gatewayAddress.PublicIp, stdout)
+	}
+
+	// Associated addresses are skipped by default
+	if _, err := execute(t, backend, "delete", "eips", "--yes"); err != nil {
+		t.Fatal(err)
+	}
+	addresses := backend.Addresses("us-east-1")
+	if len(addresses) != 2 {
+		t.Fatalf("expected only %s to be released, got %v", *idle.AllocationId, addresses)
+	}
+
+	if _, err := execute(t, backend, "delete", "natgateways", "--yes"); err != nil {
+		t.Fatal(err)
+	}
+	if state := *backend.NatGateways("us-east-1")[0].State; state != ec2.NatGatewayStateDeleted {
+		t.Fatalf("expected %s to be deleted, got %s", *gateway.NatGatewayId, state)
+	}
+
+	// The address of the deleted gateway is now unassociated
+	if _, err := execute(t, backend, "delete", "eips", "--yes"); err != nil {
+		t.Fatal(err)
+	}
+	addresses = backend.Addresses("us-east-1")
+	if len(addresses) != 1 || *addresses[0].AllocationId != *instanceAddress.AllocationId {
+		t.Errorf("expected only %s to be left, got %v", *instanceAddress.AllocationId, addresses)
+	}
+}
+
+func TestRDS(t *testing.T) {
+	backend := fake.New("us-east-1")
+	standalone := backend.AddDBInstance("us-east-1", &rds.DBInstance{
+		Engine:           awssdk.String("postgres"),
+		DBInstanceClass:  awssdk.String("db.t3.micro"),
+		AllocatedStorage: awssdk.Int64(20),
+		MultiAZ:          awssdk.Bool(true),
+	})
+	protected := backend.AddDBInstance("us-east-1", &rds.DBInstance{DeletionProtection: awssdk.Bool(true)})
+	cluster := backend.AddDBCluster("us-east-1", &rds.DBCluster{Engine: awssdk.String("aurora-postgresql")})
+	backend.AddDBInstance("us-east-1", &rds.DBInstance{DBClusterIdentifier: cluster.DBClusterIdentifier})
+
+	stdout, err := execute(t, backend, "list", "rds", "--output", "csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("expected 2 instances, a cluster and its instance, got:\n%s", stdout)
+	}
+	for _, line := range lines {
+		if strings.Contains(line, *standalone.DBInstanceArn) && !strings.Contains(line, ",postgres,") {
+			t.Errorf("expected the engine of %s, got %s", *standalone.DBInstanceIdentifier, line)
+		}
+	}
+
+	if _, err := execute(t, backend, "delete", "rds", "--skip-final-snapshot", "--plan-out", filepath.Join(t.TempDir(), "plan.json")); err == nil {
+		t.Errorf("expected --skip-final-snapshot to be refused with --plan-out")
+	}
+
+	if _, err := execute(t, backend, "delete", "rds", "--dry-run"); err != nil {
+		t.Fatal(err)
+	}
+	if len(backend.DBInstances("us-east-1")) != 3 || len(backend.DBClusters("us-east-1")) != 1 {
+		t.Fatalf("dry run deleted databases")
+	}
+
+	// Deleting the cluster deletes its instance first, and databases with
+	// deletion protection are skipped
+	if _, err := execute(t, backend, "delete", "rds", "--yes"); err != nil {
+		t.Fatal(err)
+	}
+	instances := backend.DBInstances("us-east-1")
+	if len(instances) != 1 || *instances[0].DBInstanceIdentifier != *protected.DBInstanceIdentifier {
+		t.Fatalf("expected only %s to be left, got %v", *protected.DBInstanceIdentifier, instances)
+	}
+	if n := len(backend.DBClusters("us-east-1")); n != 0 {
+		t.Fatalf("expected the cluster to be deleted, %d left", n)
+	}
+	snapshots := backend.DBSnapshots("us-east-1")
+	if len(snapshots) != 1 || *snapshots[0].DBInstanceIdentifier != *standalone.DBInstanceIdentifier {
+		t.Errorf("expected a final snapshot of %s, got %v", *standalone.DBInstanceIdentifier, snapshots)
+	}
+	if n := len(backend.DBClusterSnapshots("us-east-1")); n != 1 {
+		t.Errorf("expected a final snapshot of the cluster, got %d", n)
+	}
+
+	if _, err := execute(t, backend, "delete", "rds", "--db-identifier", *protected.DBInstanceIdentifier,
+		"--disable-deletion-protection", "--skip-final-snapshot", "--yes"); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(backend.DBInstances("us-east-1")); n != 0 {
+		t.Errorf("expected %s to be deleted, %d instances left", *protected.DBInstanceIdentifier, n)
+	}
+	if n := len(backend.DBSnapshots("us-east-1")); n != 1 {
+		t.Errorf("expected no final snapshot, got %d snapshots", n)
+	}
+}
+
+func TestS3(t *testing.T) {
+	backend := fake.New("us-east-1", "eu-west-1")
+	backend.AddBucket("eu-west-1", &s3.Bucket{Name: awssdk.String("logs")})
+	backend.SetBucketVersioning("logs", s3.BucketVersioningStatusEnabled)
+	backend.PutObject("logs", "a", 10)
+	backend.PutObject("logs", "a", 20)
+	backend.PutObject("logs", "b", 5)
+	backend.DeleteObject("logs", "b")
+	backend.AddBucket("us-east-1", &s3.Bucket{Name: awssdk.String("data")})
+	for _, key := range []string{"a", "b", "c", "d", "e"} {
+		backend.PutObject("data", key, 100)
+	}
+	backend.AddBucket("us-east-1", &s3.Bucket{Name: awssdk.String("keep")},
+		&s3.Tag{Key: awssdk.String("aws-resource/protect"), Value: awssdk.String("true")})
+
+	stdout, err := execute(t, backend, "list", "s3", "--sample-objects", "3", "--output", "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var records []output.ResourceRecord
+	if err := json.Unmarshal([]byte(stdout), &records); err != nil {
+		t.Fatalf("invalid json output: %s\n%s", err, stdout)
+	}
+	buckets := map[string]output.ResourceRecord{}
+	for _, r := range records {
+		buckets[r.ID] = r
+	}
+	if len(buckets) != 3 {
+		t.Fatalf("expected 3 buckets, got %v", records)
+	}
+	want := map[string]string{"versioning": "Enabled", "objects": "1", "size": "20", "sampled": "false"}
+	if logs := buckets["logs"]; logs.Region != "eu-west-1" || !reflect.DeepEqual(logs.Properties, want) {
+		t.Errorf("expected the current object of logs in eu-west-1, got %s %v", logs.Region, logs.Properties)
+	}
+	want = map[string]string{"versioning": "Disabled", "objects": "3", "size": "300", "sampled": "true"}
+	if data := buckets["data"]; data.Region != "us-east-1" || !reflect.DeepEqual(data.Properties, want) {
+		t.Errorf("expected a sample of 3 objects of data in us-east-1, got %s %v", data.Region, data.Properties)
+	}
+
+	if _, err := execute(t, backend, "delete", "s3", "--bucket", "logs", "--dry-run"); err != nil {
+		t.Fatal(err)
+	}
+	if n := backend.ObjectVersions("logs"); n != 4 {
+		t.Fatalf("dry run deleted object versions, %d left", n)
+	}
+
+	// Versions and delete markers are deleted across several batches, and
+	// protected buckets are kept
+	if _, err := execute(t, backend, "delete", "s3", "--yes"); err != nil {
+		t.Fatal(err)
+	}
+	left := backend.Buckets()
+	if len(left) != 1 || *left[0].Name != "keep" {
+		t.Errorf("expected only keep to be left, got %v", left)
+	}
+}
+
+func TestEKS(t *testing.T) {
+	backend := fake.New("us-east-1")
+	sandbox := backend.AddEKSCluster("us-east-1", &eks.Cluster{
+		Name:      awssdk.String("sandbox"),
+		Version:   awssdk.String("1.21"),
+		CreatedAt: awssdk.Time(time.Now().Add(-45 * 24 * time.Hour)),
+	})
+	backend.AddNodegroup("us-east-1", &eks.Nodegroup{
+		ClusterName:   sandbox.Name,
+		InstanceTypes: []*string{awssdk.String("t3.large")},
+		ScalingConfig: &eks.NodegroupScalingConfig{DesiredSize: awssdk.Int64(3)},
+	})
+	backend.AddNodegroup("us-east-1", &eks.Nodegroup{ClusterName: sandbox.Name})
+	for _, namespace := range []string{"default", "kube-system"} {
+		backend.AddFargateProfile("us-east-1", &eks.FargateProfile{
+			ClusterName: sandbox.Name,
+			Selectors:   []*eks.FargateProfileSelector{{Namespace: awssdk.String(namespace)}},
+		})
+	}
+	team := backend.AddEKSCluster("us-east-1", &eks.Cluster{Name: awssdk.String("team")})
+	backend.AddNodegroup("us-east-1", &eks.Nodegroup{
+		ClusterName: team.Name,
+		Tags:        map[string]*string{"aws-resource/protect": awssdk.String("true")},
+	})
+
+	stdout, err := execute(t, backend, "list", "eks", "--output", "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var records []output.ResourceRecord
+	if err := json.Unmarshal([]byte(stdout), &records); err != nil {
+		t.Fatalf("invalid json output: %s\n%s", err, stdout)
+	}
+	kinds := map[string]int{}
+	for _, r := range records {
+		kinds[r.Properties["kind"]]++
+		if r.ID == *sandbox.Arn && (r.Properties["nodegroups"] != "2" || r.Properties["fargate-profiles"] != "2") {
+			t.Errorf("expected sandbox to have 2 nodegroups and 2 Fargate profiles, got %v", r.Properties)
+		}
+	}
+	if want := map[string]int{"cluster": 2, "nodegroup": 3, "fargate-profile": 2}; !reflect.DeepEqual(kinds, want) {
+		t.Fatalf("expected %v, got %v", want, kinds)
+	}
+
+	if _, err := execute(t, backend, "delete", "eks", "--dry-run"); err != nil {
+		t.Fatal(err)
+	}
+	if len(backend.EKSClusters("us-east-1")) != 2 || len(backend.Nodegroups("us-east-1")) != 3 {
+		t.Fatalf("dry run deleted EKS resources")
+	}
+
+	// Nodegroups and Fargate profiles are deleted one at a time before their
+	// cluster, and a cluster with a protected nodegroup is kept
+	if _, err := execute(t, backend, "delete", "eks", "--cluster-name", "sandbox", "--yes"); err != nil {
+		t.Fatal(err)
+	}
+	clusters := backend.EKSClusters("us-east-1")
+	if len(clusters) != 1 || *clusters[0].Name != "team" {
+		t.Fatalf("expected only team to be left, got %v", clusters)
+	}
+	if n := len(backend.FargateProfiles("us-east-1")); n != 0 {
+		t.Errorf("expected the Fargate profiles to be deleted, %d left", n)
+	}
+
+	if _, err := execute(t, backend, "delete", "eks", "--cluster-name", "team", "--yes"); err != nil {
+		t.Fatal(err)
+	}
+	if len(backend.EKSClusters("us-east-1")) != 1 || len(backend.Nodegroups("us-east-1")) != 1 {
+		t.Errorf("expected team and its protected nodegroup to be kept")
+	}
+}
+
+func TestStacks(t *testing.T) {
+	backend := fake.New("us-east-1")
+	web := backend.AddStack("us-east-1", &cloudformation.Stack{StackName: awssdk.String("web")})
+	core := backend.AddStack("us-east-1", &cloudformation.Stack{
+		StackName:                   awssdk.String("core"),
+		EnableTerminationProtection: awssdk.Bool(true),
+	})
+	owned := backend.AddInstance("us-east-1", &ec2.Instance{Tags: fake.StackTags(web)})
+	backend.AddInstance("us-east-1", &ec2.Instance{Tags: fake.StackTags(core)})
+	unowned := backend.AddInstance("us-east-1", &ec2.Instance{})
+	var lbTags []*elb.Tag
+	for _, t := range fake.StackTags(web) {
+		lbTags = append(lbTags, &elb.Tag{Key: t.Key, Value: t.Value})
+	}
+	backend.AddLoadBalancer("us-east-1", &elb.LoadBalancerDescription{}, lbTags...)
+
+	if n := len(listRecords(t, backend, "stacks")); n != 2 {
+		t.Fatalf("expected 2 stacks, got %d", n)
+	}
+	stacks := map[string]string{}
+	for _, r := range listRecords(t, backend, "ec2") {
+		stacks[r.ID] = r.Stack
+	}
+	if stacks[*owned.InstanceId] != "web" || stacks[*unowned.InstanceId] != "" {
+		t.Errorf("expected only %s to belong to web, got %v", *owned.InstanceId, stacks)
+	}
+
+	// Resources of a stack are only deleted by deleting their stack
+	if _, err := execute(t, backend, "delete", "ec2", "--yes"); err != nil {
+		t.Fatal(err)
+	}
+	if n := running(backend.Instances("us-east-1")); n != 2 {
+		t.Fatalf("expected the instances of stacks to be kept, %d running", n)
+	}
+
+	if _, err := execute(t, backend, "delete", "ec2", "--delete-stack", "--dry-run"); err == nil {
+		t.Fatalf("expected the dry run to fail on a stack with termination protection")
+	}
+	core.EnableTerminationProtection = awssdk.Bool(false)
+
+	if _, err := execute(t, backend, "delete", "ec2", "--delete-stack", "--yes"); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(backend.Stacks("us-east-1")); n != 0 {
+		t.Errorf("expected the stacks to be deleted, %d left", n)
+	}
+	if n := running(backend.Instances("us-east-1")); n != 0 {
+		t.Errorf("expected the instances of the stacks to be terminated, %d running", n)
+	}
+	if n := len(listRecords(t, backend, "elb")); n != 0 {
+		t.Errorf("expected the load balancer of web to be deleted with it, %d left", n)
+	}
+}
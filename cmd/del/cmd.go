@@ -18,9 +18,18 @@ package del
 import (
 	"fmt"
 
-	"github.com/jharrington22/aws-resource/cmd/del/ec2"
+	"github.com/jharrington22/aws-resource/cmd/del/eips"
+	"github.com/jharrington22/aws-resource/cmd/del/eks"
+	"github.com/jharrington22/aws-resource/cmd/del/elb"
+	"github.com/jharrington22/aws-resource/cmd/del/elbv2"
 	"github.com/jharrington22/aws-resource/cmd/del/images"
+	"github.com/jharrington22/aws-resource/cmd/del/rds"
+	"github.com/jharrington22/aws-resource/cmd/del/route53"
+	"github.com/jharrington22/aws-resource/cmd/del/s3"
 	"github.com/jharrington22/aws-resource/cmd/del/snapshots"
+	"github.com/jharrington22/aws-resource/cmd/del/volumes"
+	"github.com/jharrington22/aws-resource/cmd/resources"
+	"github.com/jharrington22/aws-resource/pkg/resource"
 	"github.com/spf13/cobra"
 )
 
@@ -37,8 +46,28 @@ aws-resource delete snapshots`,
 
 func init() {
 
-	DelCmd.AddCommand(ec2.Cmd)
+	DelCmd.AddCommand(eips.Cmd)
+	DelCmd.AddCommand(eks.Cmd)
+	DelCmd.AddCommand(elb.Cmd)
+	DelCmd.AddCommand(elbv2.Cmd)
 	DelCmd.AddCommand(images.Cmd)
+	DelCmd.AddCommand(rds.Cmd)
+	DelCmd.AddCommand(route53.Cmd)
+	DelCmd.AddCommand(s3.Cmd)
 	DelCmd.AddCommand(snapshots.Cmd)
+	DelCmd.AddCommand(volumes.Cmd)
+
+	// Deletable resource types without a dedicated command get a generic one
+	for _, typ := range resource.Types() {
+		// Target groups are only deleted with the load balancers forwarding
+		// to them, by delete elbv2 --with-target-groups
+		if typ == "targetgroups" {
+			continue
+		}
+		p, _ := resource.New(typ, nil)
+		if resource.Deletable(p) && !resources.HasCommand(DelCmd, typ) {
+			DelCmd.AddCommand(resources.NewDeleteCmd(typ))
+		}
+	}
 
 }
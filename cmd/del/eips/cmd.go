@@ -0,0 +1,133 @@
+/*
+Copyright © 2022 James Harrington <james@harrington.net.au>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package eips
+
+import (
+	"github.com/jharrington22/aws-resource/cmd/resources"
+	"github.com/jharrington22/aws-resource/pkg/arguments"
+	logging "github.com/jharrington22/aws-resource/pkg/logging"
+	rprtr "github.com/jharrington22/aws-resource/pkg/reporter"
+	"github.com/jharrington22/aws-resource/pkg/resource"
+	"github.com/spf13/cobra"
+)
+
+var (
+	allRegions       bool
+	dryRun           bool
+	unassociatedOnly bool
+	allocationId     string
+)
+
+// Cmd represents the eips command
+var Cmd = &cobra.Command{
+	Use:   "eips",
+	Short: "Release Elastic IPs",
+	Long: `Release the Elastic IPs that aren't associated for all or a specific region
+
+aws-resource delete eips --region <region name>`,
+	RunE: resources.AcrossAccounts(run),
+}
+
+func run(cmd *cobra.Command, args []string) (err error) {
+
+	reporter := rprtr.CreateReporterOrExit()
+	logging := logging.CreateLoggerOrExit(reporter)
+
+	clients := resources.Clients(cmd, logging)
+	provider, err := resource.New("eips", clients)
+	if err != nil {
+		return reporter.Errorf("%s", err)
+	}
+
+	protection, err := resources.NewProtection(reporter)
+	if err != nil {
+		return err
+	}
+
+	var results []resource.RegionResult
+	if allRegions {
+		reporter.Infof("Releasing Elastic IPs in all regions")
+		results, err = resources.Find(cmd.Context(), reporter, clients, provider)
+	} else {
+		reporter.Infof("Releasing Elastic IPs in %s", arguments.Region)
+		results, err = resources.FindIn(cmd.Context(), reporter, provider, []string{arguments.Region})
+	}
+	if err != nil {
+		return err
+	}
+
+	var selected []resource.RegionResult
+	for _, result := range results {
+		var addresses, associated []*resource.Resource
+		for _, a := range result.Resources {
+			if allocationId != "" && a.ID != allocationId {
+				continue
+			}
+			if unassociatedOnly && a.Properties["associated"] == "true" {
+				associated = append(associated, a)
+				continue
+			}
+			addresses = append(addresses, a)
+		}
+		switch {
+		case len(associated) == 0:
+		case allocationId != "":
+			a := associated[0]
+			reporter.Infof("Skipping %s (%s), associated with %s", a.ID, a.Properties["public-ip"], associatedWith(a))
+		default:
+			reporter.Infof("Skipping %d associated addresses in %s", len(associated), result.Region)
+		}
+		selected = append(selected, resource.RegionResult{Region: result.Region, Resources: addresses})
+	}
+	selected = protection.FilterResults(reporter, selected)
+
+	if resources.Planning() {
+		return resources.SavePlan(reporter, clients, resources.Flatten(selected))
+	}
+	if !dryRun {
+		err = resources.Confirm(reporter, clients, resources.Flatten(selected))
+		if err != nil {
+			return err
+		}
+	}
+
+	return resources.Delete(cmd.Context(), reporter, clients, provider, selected, dryRun)
+}
+
+// associatedWith returns what the address is associated with.
+func associatedWith(a *resource.Resource) string {
+	if id := a.Properties["instance-id"]; id != "" {
+		return id
+	}
+	return a.Properties["network-interface-id"]
+}
+
+func init() {
+	// Add global flags
+	flags := Cmd.Flags()
+	arguments.AddFlags(flags)
+	arguments.AddFilterFlags(flags)
+	arguments.AddAccountFlags(flags)
+	arguments.AddProtectFlags(flags)
+	arguments.AddStackFlag(flags)
+	arguments.AddPlanFlag(flags)
+	arguments.AddConfirmFlag(flags)
+
+	Cmd.Flags().BoolVar(&allRegions, "all-regions", false, "Release Elastic IPs in all regions")
+	Cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Validate if delete would be successful")
+	Cmd.Flags().BoolVar(&unassociatedOnly, "unassociated-only", true, "Only release addresses that aren't associated with an instance or network interface")
+	Cmd.Flags().StringVar(&allocationId, "allocation-id", "", "Release specific allocation id")
+}
@@ -0,0 +1,190 @@
+/*
+Copyright © 2022 James Harrington <james@harrington.net.au>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package eks
+
+import (
+	"context"
+
+	"github.com/jharrington22/aws-resource/cmd/resources"
+	"github.com/jharrington22/aws-resource/pkg/arguments"
+	logging "github.com/jharrington22/aws-resource/pkg/logging"
+	rprtr "github.com/jharrington22/aws-resource/pkg/reporter"
+	"github.com/jharrington22/aws-resource/pkg/resource"
+	"github.com/spf13/cobra"
+)
+
+var (
+	allRegions  bool
+	dryRun      bool
+	clusterName string
+)
+
+// Cmd represents the eks command
+var Cmd = &cobra.Command{
+	Use:   "eks",
+	Short: "Delete EKS clusters",
+	Long: `Delete EKS clusters for all or a specific region. The nodegroups and
+Fargate profiles of a cluster are deleted before it, waiting for each
+deletion to complete.
+
+aws-resource delete eks --region <region name>
+aws-resource delete eks --cluster-name <cluster name>`,
+	RunE: resources.AcrossAccounts(run),
+}
+
+func run(cmd *cobra.Command, args []string) (err error) {
+
+	reporter := rprtr.CreateReporterOrExit()
+	logging := logging.CreateLoggerOrExit(reporter)
+
+	clients := resources.Clients(cmd, logging)
+	provider, err := resource.New("eks", clients)
+	if err != nil {
+		return reporter.Errorf("%s", err)
+	}
+
+	protection, err := resources.NewProtection(reporter)
+	if err != nil {
+		return err
+	}
+
+	var results []resource.RegionResult
+	if allRegions {
+		reporter.Infof("Deleting EKS clusters in all regions")
+		results, err = resources.Find(cmd.Context(), reporter, clients, provider)
+	} else {
+		reporter.Infof("Deleting EKS clusters in %s", arguments.Region)
+		results, err = resources.FindIn(cmd.Context(), reporter, provider, []string{arguments.Region})
+	}
+	if err != nil {
+		return err
+	}
+
+	var selected []*resource.Resource
+	for _, r := range resources.Flatten(results) {
+		if clusterName != "" && (r.Properties["kind"] != resource.EKSCluster || r.Name != clusterName) {
+			continue
+		}
+		selected = append(selected, r)
+	}
+	selected = protection.Filter(reporter, selected)
+
+	candidates, err := withMembers(cmd.Context(), reporter, provider, protection, selected)
+	if err != nil {
+		return err
+	}
+	ordered, err := resources.Order(reporter, candidates)
+	if err != nil {
+		return err
+	}
+
+	if len(ordered) == 0 {
+		reporter.Infof("No %s found", provider.Describe())
+		return nil
+	}
+	if resources.Planning() {
+		return resources.SavePlan(reporter, clients, ordered)
+	}
+	if !dryRun {
+		err = resources.Confirm(reporter, clients, ordered)
+		if err != nil {
+			return err
+		}
+		reporter.Warnf("Dry run %t will delete resources", dryRun)
+		reporter.Infof("Waiting for each deletion to complete, which can take several minutes for nodegroups and clusters")
+	}
+
+	return resources.DeleteInOrder(cmd.Context(), reporter, clients, ordered, dryRun)
+}
+
+// withMembers returns the resources along with the nodegroups and Fargate
+// profiles of the clusters among them, which are deleted before their
+// cluster. Clusters with a nodegroup or profile that is protected are left
+// out.
+func withMembers(ctx context.Context, reporter *rprtr.Object, provider resource.Provider, protection resources.Protection, selected []*resource.Resource) ([]*resource.Resource, error) {
+	var regions []string
+	seen := map[string]bool{}
+	ids := map[string]bool{}
+	for _, r := range selected {
+		if r.Properties["kind"] == resource.EKSCluster && !seen[r.Region] {
+			seen[r.Region] = true
+			regions = append(regions, r.Region)
+		}
+		ids[r.ID] = true
+	}
+	if len(regions) == 0 {
+		return selected, nil
+	}
+
+	// The members are looked up whatever the filters
+	results, err := resources.Scan(ctx, reporter, provider, regions)
+	if err != nil {
+		return nil, err
+	}
+	members := map[string][]*resource.Resource{}
+	for _, r := range resources.Flatten(results) {
+		if cluster := r.Properties["cluster"]; cluster != "" {
+			members[cluster] = append(members[cluster], r)
+		}
+	}
+
+	var candidates []*resource.Resource
+	for _, r := range selected {
+		if r.Properties["kind"] != resource.EKSCluster {
+			candidates = append(candidates, r)
+			continue
+		}
+
+		var added []*resource.Resource
+		kept := ""
+		for _, member := range members[r.ID] {
+			if ids[member.ID] {
+				continue
+			}
+			if reason, ok := protection.Match(member); ok {
+				kept = member.Properties["kind"] + " " + member.Name + ", protected by " + reason
+				break
+			}
+			added = append(added, member)
+		}
+		if kept != "" {
+			reporter.Infof("Skipping cluster %s in %s, its %s can't be deleted", r.Name, r.Region, kept)
+			continue
+		}
+		for _, member := range added {
+			ids[member.ID] = true
+			candidates = append(candidates, member)
+		}
+		candidates = append(candidates, r)
+	}
+	return candidates, nil
+}
+
+func init() {
+	// Add global flags
+	flags := Cmd.Flags()
+	arguments.AddFlags(flags)
+	arguments.AddFilterFlags(flags)
+	arguments.AddAccountFlags(flags)
+	arguments.AddProtectFlags(flags)
+	arguments.AddStackFlag(flags)
+	arguments.AddPlanFlag(flags)
+	arguments.AddConfirmFlag(flags)
+
+	Cmd.Flags().BoolVar(&allRegions, "all-regions", false, "Delete EKS clusters in all regions")
+	Cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Validate if delete would be successful")
+	Cmd.Flags().StringVar(&clusterName, "cluster-name", "", "Delete specific EKS cluster name")
+}
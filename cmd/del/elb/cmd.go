@@ -0,0 +1,97 @@
+/*
+Copyright © 2022 James Harrington <james@harrington.net.au>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package elb
+
+import (
+	"github.com/jharrington22/aws-resource/cmd/resources"
+	"github.com/jharrington22/aws-resource/pkg/arguments"
+	logging "github.com/jharrington22/aws-resource/pkg/logging"
+	rprtr "github.com/jharrington22/aws-resource/pkg/reporter"
+	"github.com/jharrington22/aws-resource/pkg/resource"
+	"github.com/spf13/cobra"
+)
+
+var (
+	allRegions bool
+	dryRun     bool
+)
+
+// Cmd represents the elb command
+var Cmd = &cobra.Command{
+	Use:   "elb",
+	Short: "Delete classic load balancers",
+	Long: `Delete classic load balancers for all or a specific region
+
+aws-resource delete elb --region <region name>`,
+	RunE: resources.AcrossAccounts(run),
+}
+
+func run(cmd *cobra.Command, args []string) (err error) {
+
+	reporter := rprtr.CreateReporterOrExit()
+	logging := logging.CreateLoggerOrExit(reporter)
+
+	clients := resources.Clients(cmd, logging)
+	provider, err := resource.New("elb", clients)
+	if err != nil {
+		return reporter.Errorf("%s", err)
+	}
+
+	protection, err := resources.NewProtection(reporter)
+	if err != nil {
+		return err
+	}
+
+	var results []resource.RegionResult
+	if allRegions {
+		reporter.Infof("Deleting load balancers in all regions")
+		results, err = resources.Find(cmd.Context(), reporter, clients, provider)
+	} else {
+		reporter.Infof("Deleting load balancers in %s", arguments.Region)
+		results, err = resources.FindIn(cmd.Context(), reporter, provider, []string{arguments.Region})
+	}
+	if err != nil {
+		return err
+	}
+	results = protection.FilterResults(reporter, results)
+
+	if resources.Planning() {
+		return resources.SavePlan(reporter, clients, resources.Flatten(results))
+	}
+	if !dryRun {
+		err = resources.Confirm(reporter, clients, resources.Flatten(results))
+		if err != nil {
+			return err
+		}
+	}
+
+	return resources.Delete(cmd.Context(), reporter, clients, provider, results, dryRun)
+}
+
+func init() {
+	// Add global flags
+	flags := Cmd.Flags()
+	arguments.AddFlags(flags)
+	arguments.AddFilterFlags(flags)
+	arguments.AddAccountFlags(flags)
+	arguments.AddProtectFlags(flags)
+	arguments.AddStackFlag(flags)
+	arguments.AddPlanFlag(flags)
+	arguments.AddConfirmFlag(flags)
+
+	Cmd.Flags().BoolVar(&allRegions, "all-regions", false, "Delete load balancers in all regions")
+	Cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Validate if delete would be successful")
+}
@@ -0,0 +1,201 @@
+/*
+Copyright © 2022 James Harrington <james@harrington.net.au>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package elbv2
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jharrington22/aws-resource/cmd/resources"
+	"github.com/jharrington22/aws-resource/pkg/arguments"
+	"github.com/jharrington22/aws-resource/pkg/aws"
+	logging "github.com/jharrington22/aws-resource/pkg/logging"
+	rprtr "github.com/jharrington22/aws-resource/pkg/reporter"
+	"github.com/jharrington22/aws-resource/pkg/resource"
+	"github.com/spf13/cobra"
+)
+
+var (
+	allRegions       bool
+	dryRun           bool
+	withTargetGroups bool
+)
+
+// Cmd represents the elbv2 command
+var Cmd = &cobra.Command{
+	Use:   "elbv2",
+	Short: "Delete application and network load balancers",
+	Long: `Delete application, network and gateway load balancers for all or a
+specific region. Load balancers with deletion protection enabled are skipped.
+
+aws-resource delete elbv2 --region <region name>
+aws-resource delete elbv2 --all-regions --with-target-groups`,
+	RunE: resources.AcrossAccounts(run),
+}
+
+func run(cmd *cobra.Command, args []string) (err error) {
+
+	reporter := rprtr.CreateReporterOrExit()
+	logging := logging.CreateLoggerOrExit(reporter)
+
+	clients := resources.Clients(cmd, logging)
+	provider, err := resource.New("elbv2", clients)
+	if err != nil {
+		return reporter.Errorf("%s", err)
+	}
+
+	protection, err := resources.NewProtection(reporter)
+	if err != nil {
+		return err
+	}
+
+	var results []resource.RegionResult
+	if allRegions {
+		reporter.Infof("Deleting v2 load balancers in all regions")
+		results, err = resources.Find(cmd.Context(), reporter, clients, provider)
+	} else {
+		reporter.Infof("Deleting v2 load balancers in %s", arguments.Region)
+		results, err = resources.FindIn(cmd.Context(), reporter, provider, []string{arguments.Region})
+	}
+	if err != nil {
+		return err
+	}
+
+	var lbs []*resource.Resource
+	for _, listed := range resources.Flatten(results) {
+		// Deletion protection isn't listed, each load balancer is looked up
+		// for it
+		lb, err := resource.Get(cmd.Context(), provider, listed.Region, listed.ID)
+		if err != nil {
+			return reporter.Errorf("Unable to describe load balancer %s: %s", listed.Name, err)
+		}
+		if lb == nil {
+			continue
+		}
+		if lb.Properties["deletion-protection"] == "true" {
+			reporter.Infof("Skipping %s in %s, deletion protection is enabled", lb.Name, lb.Region)
+			continue
+		}
+		lbs = append(lbs, lb)
+	}
+	lbs = protection.Filter(reporter, lbs)
+
+	candidates := lbs
+	if withTargetGroups {
+		candidates, err = withUnusedTargetGroups(cmd.Context(), reporter, clients, protection, lbs)
+		if err != nil {
+			return err
+		}
+	}
+	ordered, err := resources.Order(reporter, candidates)
+	if err != nil {
+		return err
+	}
+
+	if len(ordered) == 0 {
+		reporter.Infof("No %s found", provider.Describe())
+		return nil
+	}
+	if resources.Planning() {
+		return resources.SavePlan(reporter, clients, ordered)
+	}
+	if !dryRun {
+		err = resources.Confirm(reporter, clients, ordered)
+		if err != nil {
+			return err
+		}
+		reporter.Warnf("Dry run %t will delete resources", dryRun)
+	}
+
+	return resources.DeleteInOrder(cmd.Context(), reporter, clients, ordered, dryRun)
+}
+
+// withUnusedTargetGroups returns the load balancers along with the target
+// groups they forward to, which are deleted once the load balancers and
+// their listeners are. Target groups also used by a load balancer that
+// isn't deleted, or protected, are left out.
+func withUnusedTargetGroups(ctx context.Context, reporter *rprtr.Object, clients aws.ClientFunc, protection resources.Protection, lbs []*resource.Resource) ([]*resource.Resource, error) {
+	var regions []string
+	seen := map[string]bool{}
+	deleting := map[string]bool{}
+	for _, lb := range lbs {
+		if !seen[lb.Region] {
+			seen[lb.Region] = true
+			regions = append(regions, lb.Region)
+		}
+		deleting[lb.ID] = true
+	}
+
+	provider, err := resource.New("targetgroups", clients)
+	if err != nil {
+		return nil, reporter.Errorf("%s", err)
+	}
+	results, err := resources.Scan(ctx, reporter, provider, regions)
+	if err != nil {
+		return nil, err
+	}
+	tgs := map[string]*resource.Resource{}
+	for _, tg := range resources.Flatten(results) {
+		tgs[tg.ID] = tg
+	}
+
+	var candidates []*resource.Resource
+	added := map[string]bool{}
+	for _, lb := range lbs {
+		candidates = append(candidates, lb)
+		for _, arn := range strings.Split(lb.Properties["target-groups"], ",") {
+			tg, ok := tgs[arn]
+			if !ok || added[arn] {
+				continue
+			}
+			added[arn] = true
+
+			var shared string
+			for _, user := range strings.Split(tg.Properties["load-balancers"], ",") {
+				if user != "" && !deleting[user] {
+					shared = user
+					break
+				}
+			}
+			if shared != "" {
+				reporter.Infof("Keeping target group %s of %s, it's also used by %s", tg.Name, lb.Name, shared)
+				continue
+			}
+			if reason, ok := protection.Match(tg); ok {
+				reporter.Infof("Skipping target group %s of %s, protected by %s", tg.Name, lb.Name, reason)
+				continue
+			}
+			candidates = append(candidates, tg)
+		}
+	}
+	return candidates, nil
+}
+
+func init() {
+	// Add global flags
+	flags := Cmd.Flags()
+	arguments.AddFlags(flags)
+	arguments.AddFilterFlags(flags)
+	arguments.AddAccountFlags(flags)
+	arguments.AddProtectFlags(flags)
+	arguments.AddStackFlag(flags)
+	arguments.AddPlanFlag(flags)
+	arguments.AddConfirmFlag(flags)
+
+	Cmd.Flags().BoolVar(&allRegions, "all-regions", false, "Delete load balancers in all regions")
+	Cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Validate if delete would be successful")
+	Cmd.Flags().BoolVar(&withTargetGroups, "with-target-groups", false, "Delete the target groups of the load balancers once they and their listeners are deleted, unless other load balancers use them")
+}
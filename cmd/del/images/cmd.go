@@ -16,21 +16,22 @@ limitations under the License.
 package images
 
 import (
-	"fmt"
-	"os"
+	"context"
+	"strings"
 
-	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/jharrington22/aws-resource/cmd/resources"
 	"github.com/jharrington22/aws-resource/pkg/arguments"
 	"github.com/jharrington22/aws-resource/pkg/aws"
 	logging "github.com/jharrington22/aws-resource/pkg/logging"
 	rprtr "github.com/jharrington22/aws-resource/pkg/reporter"
-	"github.com/sirupsen/logrus"
+	"github.com/jharrington22/aws-resource/pkg/resource"
 	"github.com/spf13/cobra"
 )
 
 var (
-	imageId string
-	dryRun  bool
+	imageId       string
+	dryRun        bool
+	withSnapshots bool
 )
 
 // Cmd represents the images command
@@ -40,7 +41,7 @@ var Cmd = &cobra.Command{
 	Long: `Delete images for all or a specific region
 
 aws-resource delete images`,
-	RunE: run,
+	RunE: resources.AcrossAccounts(run),
 }
 
 func run(cmd *cobra.Command, args []string) (err error) {
@@ -48,116 +49,179 @@ func run(cmd *cobra.Command, args []string) (err error) {
 	reporter := rprtr.CreateReporterOrExit()
 	logging := logging.CreateLoggerOrExit(reporter)
 
-	awsClient, err := aws.NewClient().
-		Logger(logging).
-		Profile(arguments.Profile).
-		RoleArn(arguments.RoleArn).
-		Region(arguments.Region).
-		Build()
-
+	clients := resources.Clients(cmd, logging)
+	provider, err := resource.New("images", clients)
 	if err != nil {
-		return reporter.Errorf("Unable to build AWS client")
+		return reporter.Errorf("%s", err)
 	}
 
-	regions, err := awsClient.DescribeRegions(&ec2.DescribeRegionsInput{})
+	protection, err := resources.NewProtection(reporter)
 	if err != nil {
-		return reporter.Errorf("Failed to describe regions")
+		return err
 	}
 
+	var results []resource.RegionResult
 	if imageId != "" {
-		_, err := deleteImageId(awsClient, imageId, dryRun)
+		if arguments.Selector != "" || arguments.OlderThan != "" || arguments.NewerThan != "" {
+			return reporter.Errorf("--selector, --older-than and --newer-than can't be used with --image-id")
+		}
+		if resources.MultiAccount() {
+			return reporter.Errorf("--image-id can't be used with --all-accounts or --accounts")
+		}
+		image := &resource.Resource{
+			Type:   provider.Type(),
+			ID:     imageId,
+			Region: arguments.Region,
+		}
+		if withSnapshots {
+			// The snapshots backing the image are only known once it's
+			// looked up
+			image, err = resource.Get(cmd.Context(), provider, arguments.Region, imageId)
+			if err != nil {
+				return reporter.Errorf("Unable to describe image %s: %s", imageId, err)
+			}
+			if image == nil {
+				return reporter.Errorf("Image %s not found in %s", imageId, arguments.Region)
+			}
+		}
+		results = []resource.RegionResult{{Region: arguments.Region, Resources: []*resource.Resource{image}}}
+	} else {
+		reporter.Infof("No image id specified")
+		results, err = resources.Find(cmd.Context(), reporter, clients, provider)
 		if err != nil {
-			_ = reporter.Errorf("Unable to delete image: %s", err)
+			return err
 		}
-		reporter.Infof("Image %s deregistered", imageId)
+		results = protection.FilterResults(reporter, results)
 	}
 
-	if imageId == "" {
-		reporter.Infof("No image id specified")
-		err := deleteAllImages(reporter, logging, regions, dryRun)
+	if withSnapshots {
+		candidates, err := withBackingSnapshots(cmd.Context(), reporter, clients, protection, resources.Flatten(results))
+		if err != nil {
+			return err
+		}
+		ordered, err := resources.Order(reporter, candidates)
 		if err != nil {
-			_ = reporter.Errorf("Unable to delete image: %s", err)
+			return err
+		}
+		if resources.Planning() {
+			return resources.SavePlan(reporter, clients, ordered)
 		}
+		if !dryRun {
+			err = resources.Confirm(reporter, clients, ordered)
+			if err != nil {
+				return err
+			}
+			reporter.Warnf("Dry run %t will delete resources", dryRun)
+		}
+		return resources.DeleteInOrder(cmd.Context(), reporter, clients, ordered, dryRun)
 	}
 
-	return
-}
-
-func init() {
-	// Add global flags
-	flags := Cmd.Flags()
-	arguments.AddFlags(flags)
-	Cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Validate if delete would be successful")
-	Cmd.Flags().StringVarP(&imageId, "image-id", "i", "", "Delete specific image id")
-}
-
-func deleteImageId(client aws.Client, imageId string, dryRun bool) (*ec2.DeregisterImageOutput, error) {
-
-	input := &ec2.DeregisterImageInput{
-		DryRun:  &dryRun,
-		ImageId: &imageId,
+	if resources.Planning() {
+		return resources.SavePlan(reporter, clients, resources.Flatten(results))
 	}
-
-	output, err := client.DeregisterImage(input)
-	if err != nil {
-		return nil, fmt.Errorf("Unable to deregister image: %s", err)
+	if !dryRun {
+		err = resources.Confirm(reporter, clients, resources.Flatten(results))
+		if err != nil {
+			return err
+		}
 	}
 
-	return output, err
+	return resources.Delete(cmd.Context(), reporter, clients, provider, results, dryRun)
 }
 
-func deleteAllImages(reporter *rprtr.Object, logging *logrus.Logger, regions *ec2.DescribeRegionsOutput, dryRun bool) error {
-
-	for _, region := range regions.Regions {
-
-		regionName := *region.RegionName
-
-		awsClient, err := aws.NewClient().
-			Logger(logging).
-			Profile(arguments.Profile).
-			RoleArn(arguments.RoleArn).
-			Region(regionName).
-			Build()
-
-		if err != nil {
-			_ = reporter.Errorf("Unable to build AWS client in %s", regionName)
-			os.Exit(1)
+// withBackingSnapshots returns the images along with the snapshots backing
+// them, which are deleted once the images are deregistered. Snapshots also
+// backing an image that isn't deleted, owned by another account, or
+// protected are left out.
+func withBackingSnapshots(ctx context.Context, reporter *rprtr.Object, clients aws.ClientFunc, protection resources.Protection, images []*resource.Resource) ([]*resource.Resource, error) {
+	var regions []string
+	seen := map[string]bool{}
+	deleting := map[string]bool{}
+	for _, image := range images {
+		if !seen[image.Region] {
+			seen[image.Region] = true
+			regions = append(regions, image.Region)
 		}
+		deleting[image.Region+"/"+image.ID] = true
+	}
 
-		owner := "self"
-
-		input := &ec2.DescribeImagesInput{
-			Owners: []*string{&owner},
-		}
+	snapshots, err := resource.New("snapshots", clients)
+	if err != nil {
+		return nil, reporter.Errorf("%s", err)
+	}
+	results, err := resources.Scan(ctx, reporter, snapshots, regions)
+	if err != nil {
+		return nil, err
+	}
+	owned := map[string]*resource.Resource{}
+	for _, s := range resources.Flatten(results) {
+		owned[s.Region+"/"+s.ID] = s
+	}
 
-		var images []*ec2.Image
-		output, err := awsClient.DescribeImages(input)
-		if err != nil {
-			return reporter.Errorf("Unable to describe images %s", err)
+	// Every image of the regions is needed to find the snapshots they share
+	provider, err := resource.New("images", clients)
+	if err != nil {
+		return nil, reporter.Errorf("%s", err)
+	}
+	results, err = resources.Scan(ctx, reporter, provider, regions)
+	if err != nil {
+		return nil, err
+	}
+	users := map[string][]string{}
+	for _, image := range resources.Flatten(results) {
+		for _, id := range strings.Split(image.Properties["snapshots"], ",") {
+			users[image.Region+"/"+id] = append(users[image.Region+"/"+id], image.ID)
 		}
+	}
 
-		for _, image := range output.Images {
-			images = append(images, image)
-			input := &ec2.DeregisterImageInput{
-				DryRun:  &dryRun,
-				ImageId: image.ImageId,
+	var candidates []*resource.Resource
+	added := map[string]bool{}
+	for _, image := range images {
+		candidates = append(candidates, image)
+		for _, id := range strings.Split(image.Properties["snapshots"], ",") {
+			k := image.Region + "/" + id
+			if id == "" || added[k] {
+				continue
 			}
+			added[k] = true
 
-			_, err := awsClient.DeregisterImage(input)
-			if err != nil {
-				return fmt.Errorf("Unable to deregister image: %s", err)
+			snapshot, ok := owned[k]
+			if !ok {
+				reporter.Infof("Keeping snapshot %s of image %s, it isn't owned by the account", id, image.ID)
+				continue
 			}
-			// Here we now can delete the backing snapshot by calling delete
-			// snapshot again
-
-			reporter.Infof("Image %s deregistered", imageId)
-		}
-
-		if len(images) > 0 {
-			reporter.Infof("Found %d images in %s", len(images), regionName)
+			var shared string
+			for _, user := range users[k] {
+				if !deleting[image.Region+"/"+user] {
+					shared = user
+					break
+				}
+			}
+			if shared != "" {
+				reporter.Infof("Keeping snapshot %s of image %s, it also backs image %s", id, image.ID, shared)
+				continue
+			}
+			if reason, ok := protection.Match(snapshot); ok {
+				reporter.Infof("Skipping snapshot %s of image %s, protected by %s", id, image.ID, reason)
+				continue
+			}
+			candidates = append(candidates, snapshot)
 		}
-
 	}
+	return candidates, nil
+}
 
-	return nil
+func init() {
+	// Add global flags
+	flags := Cmd.Flags()
+	arguments.AddFlags(flags)
+	arguments.AddFilterFlags(flags)
+	arguments.AddAccountFlags(flags)
+	arguments.AddProtectFlags(flags)
+	arguments.AddStackFlag(flags)
+	arguments.AddPlanFlag(flags)
+	arguments.AddConfirmFlag(flags)
+	Cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Validate if delete would be successful")
+	Cmd.Flags().StringVarP(&imageId, "image-id", "i", "", "Delete specific image id")
+	Cmd.Flags().BoolVar(&withSnapshots, "with-snapshots", false, "Delete the snapshots backing the images once they're deregistered, unless other images use them")
 }
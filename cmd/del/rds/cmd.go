@@ -0,0 +1,224 @@
+/*
+Copyright © 2022 James Harrington <james@harrington.net.au>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package rds
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jharrington22/aws-resource/cmd/resources"
+	"github.com/jharrington22/aws-resource/pkg/arguments"
+	logging "github.com/jharrington22/aws-resource/pkg/logging"
+	rprtr "github.com/jharrington22/aws-resource/pkg/reporter"
+	"github.com/jharrington22/aws-resource/pkg/resource"
+	"github.com/spf13/cobra"
+)
+
+var (
+	allRegions                bool
+	dryRun                    bool
+	skipFinalSnapshot         bool
+	disableDeletionProtection bool
+	dbIdentifier              string
+)
+
+// Cmd represents the rds command
+var Cmd = &cobra.Command{
+	Use:   "rds",
+	Short: "Delete RDS instances and clusters",
+	Long: `Delete RDS DB instances and clusters, including Aurora clusters, for all
+or a specific region. A final snapshot of every database is taken unless
+--skip-final-snapshot is given, and databases with deletion protection
+enabled are skipped unless --disable-deletion-protection is given. The
+instances of a cluster are deleted along with it.
+
+aws-resource delete rds --region <region name>
+aws-resource delete rds --db-identifier <identifier> --skip-final-snapshot`,
+	RunE: resources.AcrossAccounts(run),
+}
+
+func run(cmd *cobra.Command, args []string) (err error) {
+
+	reporter := rprtr.CreateReporterOrExit()
+	logging := logging.CreateLoggerOrExit(reporter)
+
+	if (skipFinalSnapshot || disableDeletionProtection) && resources.Planning() {
+		return reporter.Errorf("--skip-final-snapshot and --disable-deletion-protection can't be used with --plan-out, applying a plan takes final snapshots and leaves deletion protection enabled")
+	}
+
+	clients := resources.Clients(cmd, logging)
+	provider, err := resource.New("rds", clients)
+	if err != nil {
+		return reporter.Errorf("%s", err)
+	}
+
+	protection, err := resources.NewProtection(reporter)
+	if err != nil {
+		return err
+	}
+
+	var results []resource.RegionResult
+	if allRegions {
+		reporter.Infof("Deleting RDS instances and clusters in all regions")
+		results, err = resources.Find(cmd.Context(), reporter, clients, provider)
+	} else {
+		reporter.Infof("Deleting RDS instances and clusters in %s", arguments.Region)
+		results, err = resources.FindIn(cmd.Context(), reporter, provider, []string{arguments.Region})
+	}
+	if err != nil {
+		return err
+	}
+
+	var databases []*resource.Resource
+	for _, db := range resources.Flatten(results) {
+		if dbIdentifier != "" && db.Name != dbIdentifier {
+			continue
+		}
+		if skip(reporter, db) {
+			continue
+		}
+		databases = append(databases, db)
+	}
+	databases = protection.Filter(reporter, databases)
+
+	candidates, err := withInstances(cmd.Context(), reporter, provider, protection, databases)
+	if err != nil {
+		return err
+	}
+	ordered, err := resources.Order(reporter, candidates)
+	if err != nil {
+		return err
+	}
+
+	if len(ordered) == 0 {
+		reporter.Infof("No %s found", provider.Describe())
+		return nil
+	}
+	if resources.Planning() {
+		return resources.SavePlan(reporter, clients, ordered)
+	}
+	if !dryRun {
+		err = resources.Confirm(reporter, clients, ordered)
+		if err != nil {
+			return err
+		}
+		reporter.Warnf("Dry run %t will delete resources", dryRun)
+	}
+
+	ctx := resource.WithDatabaseDeletion(cmd.Context(), resource.DatabaseDeletion{
+		SkipFinalSnapshot:         skipFinalSnapshot,
+		DisableDeletionProtection: disableDeletionProtection,
+	})
+	return resources.DeleteInOrder(ctx, reporter, clients, ordered, dryRun)
+}
+
+// skip reports whether the database can't be deleted, as it's already being
+// deleted or deletion protection is enabled.
+func skip(reporter *rprtr.Object, db *resource.Resource) bool {
+	kind := db.Properties["kind"]
+	switch {
+	case db.State == "deleting":
+		reporter.Infof("Skipping %s %s in %s, it's already being deleted", kind, db.Name, db.Region)
+		return true
+	case db.Properties["deletion-protection"] == "true" && !disableDeletionProtection:
+		reporter.Infof("Skipping %s %s in %s, deletion protection is enabled", kind, db.Name, db.Region)
+		return true
+	}
+	return false
+}
+
+// withInstances returns the databases along with the instances of the
+// clusters among them, which are deleted before their cluster. Clusters
+// with an instance that can't be deleted are left out.
+func withInstances(ctx context.Context, reporter *rprtr.Object, provider resource.Provider, protection resources.Protection, databases []*resource.Resource) ([]*resource.Resource, error) {
+	var regions []string
+	seen := map[string]bool{}
+	selected := map[string]bool{}
+	for _, db := range databases {
+		if db.Properties["kind"] == resource.DBCluster && !seen[db.Region] {
+			seen[db.Region] = true
+			regions = append(regions, db.Region)
+		}
+		selected[db.ID] = true
+	}
+	if len(regions) == 0 {
+		return databases, nil
+	}
+
+	// The instances are looked up whatever the filters
+	results, err := resources.Scan(ctx, reporter, provider, regions)
+	if err != nil {
+		return nil, err
+	}
+	all := map[string]*resource.Resource{}
+	for _, db := range resources.Flatten(results) {
+		all[db.ID] = db
+	}
+
+	var candidates []*resource.Resource
+	for _, db := range databases {
+		if db.Properties["kind"] != resource.DBCluster {
+			candidates = append(candidates, db)
+			continue
+		}
+
+		var instances []*resource.Resource
+		kept := ""
+		for _, arn := range strings.Split(db.Properties["members"], ",") {
+			instance, ok := all[arn]
+			if !ok || selected[arn] || instance.State == "deleting" {
+				continue
+			}
+			if reason, ok := protection.Match(instance); ok {
+				kept = instance.Name + ", protected by " + reason
+				break
+			}
+			if skip(reporter, instance) {
+				kept = instance.Name
+				break
+			}
+			instances = append(instances, instance)
+		}
+		if kept != "" {
+			reporter.Infof("Skipping cluster %s in %s, its instance %s can't be deleted", db.Name, db.Region, kept)
+			continue
+		}
+		for _, instance := range instances {
+			selected[instance.ID] = true
+			candidates = append(candidates, instance)
+		}
+		candidates = append(candidates, db)
+	}
+	return candidates, nil
+}
+
+func init() {
+	// Add global flags
+	flags := Cmd.Flags()
+	arguments.AddFlags(flags)
+	arguments.AddFilterFlags(flags)
+	arguments.AddAccountFlags(flags)
+	arguments.AddProtectFlags(flags)
+	arguments.AddStackFlag(flags)
+	arguments.AddPlanFlag(flags)
+	arguments.AddConfirmFlag(flags)
+
+	Cmd.Flags().BoolVar(&allRegions, "all-regions", false, "Delete databases in all regions")
+	Cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Validate if delete would be successful")
+	Cmd.Flags().BoolVar(&skipFinalSnapshot, "skip-final-snapshot", false, "Delete databases without taking a final snapshot")
+	Cmd.Flags().BoolVar(&disableDeletionProtection, "disable-deletion-protection", false, "Disable deletion protection of databases before deleting them rather than skipping them")
+	Cmd.Flags().StringVar(&dbIdentifier, "db-identifier", "", "Delete specific DB instance or cluster identifier")
+}
@@ -0,0 +1,209 @@
+/*
+Copyright © 2022 James Harrington <james@harrington.net.au>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package route53
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/jharrington22/aws-resource/cmd/resources"
+	"github.com/jharrington22/aws-resource/pkg/arguments"
+	"github.com/jharrington22/aws-resource/pkg/aws"
+	logging "github.com/jharrington22/aws-resource/pkg/logging"
+	rprtr "github.com/jharrington22/aws-resource/pkg/reporter"
+	"github.com/jharrington22/aws-resource/pkg/resource"
+	"github.com/spf13/cobra"
+)
+
+var (
+	dryRun   bool
+	zoneId   string
+	zoneName string
+)
+
+// Cmd represents the route53 command
+var Cmd = &cobra.Command{
+	Use:   "route53",
+	Short: "Delete hosted zones",
+	Long: `Delete Route53 hosted zones along with their record sets. A dry run
+writes the change batches deleting the record sets of each zone to stdout.
+
+aws-resource delete route53 --zone-name <zone name> --dry-run`,
+	RunE: resources.AcrossAccounts(run),
+}
+
+func run(cmd *cobra.Command, args []string) (err error) {
+
+	reporter := rprtr.CreateReporterOrExit()
+	logging := logging.CreateLoggerOrExit(reporter)
+
+	clients := resources.Clients(cmd, logging)
+	provider, err := resource.New("route53", clients)
+	if err != nil {
+		return reporter.Errorf("%s", err)
+	}
+
+	protection, err := resources.NewProtection(reporter)
+	if err != nil {
+		return err
+	}
+
+	results, err := resources.Find(cmd.Context(), reporter, clients, provider)
+	if err != nil {
+		return err
+	}
+
+	var zones []*resource.Resource
+	for _, z := range resources.Flatten(results) {
+		if zoneId != "" && strings.TrimPrefix(z.ID, "/hostedzone/") != strings.TrimPrefix(zoneId, "/hostedzone/") {
+			continue
+		}
+		if zoneName != "" && strings.TrimSuffix(z.Name, ".") != strings.TrimSuffix(zoneName, ".") {
+			continue
+		}
+		zones = append(zones, z)
+	}
+	zones = protection.Filter(reporter, zones)
+
+	if len(zones) == 0 {
+		reporter.Infof("No %s found", provider.Describe())
+		return nil
+	}
+	if resources.Planning() {
+		return resources.SavePlan(reporter, clients, zones)
+	}
+	if !dryRun {
+		err = resources.Confirm(reporter, clients, zones)
+		if err != nil {
+			return err
+		}
+		reporter.Warnf("Dry run %t will delete resources", dryRun)
+	}
+
+	var stacks []*resource.Resource
+	for _, z := range zones {
+		if z.Type != provider.Type() {
+			stacks = append(stacks, z)
+			continue
+		}
+		if err := deleteZone(cmd.Context(), reporter, clients, provider, z); err != nil {
+			return err
+		}
+	}
+	if len(stacks) > 0 {
+		return resources.DeleteInOrder(cmd.Context(), reporter, clients, stacks, dryRun)
+	}
+	return nil
+}
+
+// deleteZone deletes the record sets of the zone, then the zone. A dry run
+// writes the change batches that would be sent to stdout.
+func deleteZone(ctx context.Context, reporter *rprtr.Object, clients aws.ClientFunc, provider resource.Provider, zone *resource.Resource) error {
+	batches, err := resource.EmptyZoneChanges(ctx, clients, zone)
+	if err != nil {
+		return reporter.Errorf("Unable to list the record sets of %s: %s", zone.Name, err)
+	}
+	changes := 0
+	for _, batch := range batches {
+		changes += len(batch.ChangeBatch.Changes)
+	}
+	reporter.Infof("Deleting %d record sets of %s (%s) in %d change batches", changes, zone.Name, zone.ID, len(batches))
+
+	if dryRun {
+		for n, batch := range batches {
+			data, err := changeBatchJSON(batch.ChangeBatch)
+			if err != nil {
+				return reporter.Errorf("Unable to encode change batch: %s", err)
+			}
+			reporter.Infof("Change batch %d of %d for %s", n+1, len(batches), zone.ID)
+			fmt.Println(string(data))
+		}
+	}
+
+	// The batches shown are the ones sent, the record sets aren't listed
+	// again. A dry run still goes through the provider to be checked
+	// against the protection and recorded in the audit log.
+	err = provider.Delete(resource.WithZoneChanges(ctx, zone, batches), zone, dryRun)
+	if resources.Skipped(reporter, err) {
+		return nil
+	}
+	err = resources.Unrecorded(reporter, err)
+	if err != nil {
+		return reporter.Errorf("Unable to delete %s: %s", zone.Name, err)
+	}
+	if dryRun {
+		reporter.Infof("Deletion of %s would have succeeded", zone.Name)
+	} else {
+		reporter.Infof("Deleted %s", zone.Name)
+	}
+	return nil
+}
+
+// changeBatchJSON encodes the change batch in the form accepted by the
+// --change-batch option of the AWS CLI, leaving out the fields that aren't
+// set.
+func changeBatchJSON(batch *route53.ChangeBatch) ([]byte, error) {
+	data, err := json.Marshal(batch)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(strings.NewReader(string(data)))
+	decoder.UseNumber()
+	var v interface{}
+	if err := decoder.Decode(&v); err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(withoutNulls(v), "", "  ")
+}
+
+// withoutNulls returns the decoded json value without the null members of
+// its objects.
+func withoutNulls(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, member := range v {
+			if member == nil {
+				delete(v, k)
+				continue
+			}
+			v[k] = withoutNulls(member)
+		}
+	case []interface{}:
+		for n, item := range v {
+			v[n] = withoutNulls(item)
+		}
+	}
+	return v
+}
+
+func init() {
+	// Add global flags
+	flags := Cmd.Flags()
+	arguments.AddFlags(flags)
+	arguments.AddFilterFlags(flags)
+	arguments.AddAccountFlags(flags)
+	arguments.AddProtectFlags(flags)
+	arguments.AddStackFlag(flags)
+	arguments.AddPlanFlag(flags)
+	arguments.AddConfirmFlag(flags)
+
+	Cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Write the change batches that would be sent without deleting anything")
+	Cmd.Flags().StringVar(&zoneId, "zone-id", "", "Delete specific hosted zone id")
+	Cmd.Flags().StringVar(&zoneName, "zone-name", "", "Delete the hosted zone with this domain name")
+}
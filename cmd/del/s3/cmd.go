@@ -0,0 +1,117 @@
+/*
+Copyright © 2022 James Harrington <james@harrington.net.au>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package s3
+
+import (
+	"github.com/jharrington22/aws-resource/cmd/resources"
+	"github.com/jharrington22/aws-resource/pkg/arguments"
+	logging "github.com/jharrington22/aws-resource/pkg/logging"
+	rprtr "github.com/jharrington22/aws-resource/pkg/reporter"
+	"github.com/jharrington22/aws-resource/pkg/resource"
+	"github.com/spf13/cobra"
+)
+
+var (
+	dryRun     bool
+	bucketName string
+)
+
+// Cmd represents the s3 command
+var Cmd = &cobra.Command{
+	Use:   "s3",
+	Short: "Delete S3 buckets",
+	Long: `Delete S3 buckets, first emptying them of every object version and
+delete marker
+
+aws-resource delete s3 --bucket <bucket name>
+aws-resource delete s3 --selector env=dev --dry-run`,
+	RunE: resources.AcrossAccounts(run),
+}
+
+func run(cmd *cobra.Command, args []string) (err error) {
+
+	reporter := rprtr.CreateReporterOrExit()
+	logging := logging.CreateLoggerOrExit(reporter)
+
+	clients := resources.Clients(cmd, logging)
+	provider, err := resource.New("s3", clients)
+	if err != nil {
+		return reporter.Errorf("%s", err)
+	}
+
+	protection, err := resources.NewProtection(reporter)
+	if err != nil {
+		return err
+	}
+
+	// Objects are counted when emptying buckets rather than sampled
+	ctx := resource.WithObjectSample(cmd.Context(), 0)
+	reporter.Infof("Deleting S3 buckets")
+	results, err := resources.Find(ctx, reporter, clients, provider)
+	if err != nil {
+		return err
+	}
+
+	var selected []resource.RegionResult
+	for _, result := range results {
+		var buckets []*resource.Resource
+		for _, b := range result.Resources {
+			if bucketName == "" || b.ID == bucketName {
+				buckets = append(buckets, b)
+			}
+		}
+		selected = append(selected, resource.RegionResult{Region: result.Region, Resources: buckets})
+	}
+	selected = protection.FilterResults(reporter, selected)
+
+	if resources.Planning() {
+		return resources.SavePlan(reporter, clients, resources.Flatten(selected))
+	}
+	if dryRun {
+		for _, b := range resources.Flatten(selected) {
+			if b.Type != provider.Type() {
+				continue
+			}
+			objects, err := resource.EmptyBucket(ctx, clients, b, true)
+			if err != nil {
+				return reporter.Errorf("Unable to list the objects of %s: %s", b.ID, err)
+			}
+			reporter.Infof("Emptying %s in %s would delete %d object versions and delete markers", b.ID, b.Region, objects)
+		}
+	} else {
+		err = resources.Confirm(reporter, clients, resources.Flatten(selected))
+		if err != nil {
+			return err
+		}
+	}
+
+	return resources.Delete(ctx, reporter, clients, provider, selected, dryRun)
+}
+
+func init() {
+	// Add global flags
+	flags := Cmd.Flags()
+	arguments.AddFlags(flags)
+	arguments.AddFilterFlags(flags)
+	arguments.AddAccountFlags(flags)
+	arguments.AddProtectFlags(flags)
+	arguments.AddStackFlag(flags)
+	arguments.AddPlanFlag(flags)
+	arguments.AddConfirmFlag(flags)
+
+	Cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Validate if delete would be successful")
+	Cmd.Flags().StringVar(&bucketName, "bucket", "", "Delete specific bucket name")
+}
@@ -15,24 +15,18 @@ limitations under the License.
 package snapshots
 
 import (
-	"fmt"
-	"regexp"
-	"strconv"
+	"context"
+	"strings"
 
-	"github.com/aws/aws-sdk-go/aws/awserr"
-	"github.com/aws/aws-sdk-go/service/ec2"
-	"github.com/jharrington22/aws-resource/cmd/del/images"
+	"github.com/jharrington22/aws-resource/cmd/resources"
 	"github.com/jharrington22/aws-resource/pkg/arguments"
 	"github.com/jharrington22/aws-resource/pkg/aws"
 	logging "github.com/jharrington22/aws-resource/pkg/logging"
 	rprtr "github.com/jharrington22/aws-resource/pkg/reporter"
+	"github.com/jharrington22/aws-resource/pkg/resource"
 	"github.com/spf13/cobra"
 )
 
-const (
-	InvalidSnapshotInUse = "InvalidSnapshot.InUse"
-)
-
 var (
 	allRegions         bool
 	dryRun             bool
@@ -48,7 +42,7 @@ var Cmd = &cobra.Command{
 	Long: `Delete EBS snapshots for all or a specific region
 
 aws-resource delete snapshots --region <region name>`,
-	RunE: run,
+	RunE: resources.AcrossAccounts(run),
 }
 
 func run(cmd *cobra.Command, args []string) (err error) {
@@ -56,86 +50,46 @@ func run(cmd *cobra.Command, args []string) (err error) {
 	reporter := rprtr.CreateReporterOrExit()
 	logging := logging.CreateLoggerOrExit(reporter)
 
-	awsClient, err := aws.NewClient().
-		Logger(logging).
-		Profile(arguments.Profile).
-		RoleArn(arguments.RoleArn).
-		Region(arguments.Region).
-		Build()
+	clients := resources.Clients(cmd, logging)
+	provider, err := resource.New("snapshots", clients)
+	if err != nil {
+		return reporter.Errorf("%s", err)
+	}
 
+	protection, err := resources.NewProtection(reporter)
 	if err != nil {
-		return reporter.Errorf("Unable to build AWS client")
+		return err
 	}
 
 	if !dryRun {
 		reporter.Warnf("Dry run %t will delete resources", dryRun)
 	}
 
-	var snapshots []*ec2.Snapshot
+	var results []resource.RegionResult
 	if allRegions {
 		reporter.Infof("Deleting ebs snapshots in all regions")
-		regions, err := awsClient.DescribeRegions(&ec2.DescribeRegionsInput{})
+		results, err = resources.Find(cmd.Context(), reporter, clients, provider)
 		if err != nil {
-			return reporter.Errorf("Failed to describe regions")
+			return err
 		}
-
-		for _, region := range regions.Regions {
-			awsClient, err := aws.NewClient().
-				Logger(logging).
-				Profile(arguments.Profile).
-				RoleArn(arguments.RoleArn).
-				Region(*region.RegionName).
-				Build()
-
-			if err != nil {
-				_ = reporter.Errorf("Unable to build AWS client for region: %s: %s", *region.RegionName, err)
-			}
-
-			ss, err := describeSnapshots(awsClient, reporter)
-			if err != nil {
-				return reporter.Errorf("Unable to describe snapshots for region %s: err", *region.RegionName)
-			}
-			snapshots = append(snapshots, ss...)
-			for _, s := range ss {
-				err = deleteSnapshot(awsClient, cmd, reporter, s, dryRun)
-				if err != nil {
-					return fmt.Errorf("Unable to delete shapshot: %s", err)
-				}
-				reporter.Infof("Deleted snapshot %s", *s.SnapshotId)
-
-			}
+	} else {
+		reporter.Infof("Deleting ebs snapshots in %s", arguments.Region)
+		results, err = resources.FindIn(cmd.Context(), reporter, provider, []string{arguments.Region})
+		if err != nil {
+			return err
 		}
-
 	}
 
-	if snapshotId != "" {
-		snapshot, err := awsClient.DescribeSnapshots(&ec2.DescribeSnapshotsInput{
-			SnapshotIds: []*string{&snapshotId},
-		})
-
-		if err != nil {
-			return reporter.Errorf("Unable to describe snapshot id: %s", snapshotId)
-		}
-		snapshots = append(snapshots, snapshot.Snapshots...)
-		for _, s := range snapshot.Snapshots {
-			err = deleteSnapshot(awsClient, cmd, reporter, s, dryRun)
-			if err != nil {
-				return fmt.Errorf("Unable to delete shapshot: %s", err)
+	var snapshots []*resource.Resource
+	for _, result := range results {
+		for _, s := range result.Resources {
+			if snapshotId == "" || s.ID == snapshotId {
+				snapshots = append(snapshots, s)
 			}
-			reporter.Infof("Deleted snapshot %s", *s.SnapshotId)
 		}
-
 	}
 
-	// FIXME: Check if the default has been specified or remove the
-	// default of us-east-1 else this will always match
-	if (snapshotId == "" && arguments.Region != "") && !allRegions {
-		reporter.Infof("Deleting ebs snapshots in %s", arguments.Region)
-		snapshots, err = describeSnapshots(awsClient, reporter)
-		if err != nil {
-			return reporter.Errorf("Unable to describe snapshots for region %s", arguments.Region)
-		}
-	}
+	snapshots = protection.Filter(reporter, snapshots)
 
 	if len(snapshots) == 0 {
 		msg := arguments.Region
@@ -146,97 +100,101 @@ func run(cmd *cobra.Command, args []string) (err error) {
 		return nil
 	}
 
-	return nil
+	candidates, err := withBackingImages(cmd.Context(), reporter, clients, protection, snapshots)
+	if err != nil {
+		return err
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+	ordered, err := resources.Order(reporter, candidates)
+	if err != nil {
+		return err
+	}
+	if resources.Planning() {
+		return resources.SavePlan(reporter, clients, ordered)
+	}
+	if !dryRun {
+		err = resources.Confirm(reporter, clients, ordered)
+		if err != nil {
+			return err
+		}
+	}
 
+	return resources.DeleteInOrder(cmd.Context(), reporter, clients, ordered, dryRun)
 }
 
 func init() {
 	// Add global flags
 	flags := Cmd.Flags()
 	arguments.AddFlags(flags)
+	arguments.AddFilterFlags(flags)
+	arguments.AddAccountFlags(flags)
+	arguments.AddProtectFlags(flags)
+	arguments.AddStackFlag(flags)
+	arguments.AddPlanFlag(flags)
+	arguments.AddConfirmFlag(flags)
 
 	Cmd.Flags().BoolVar(&allRegions, "all-regions", false, "Delete snapshots in all regions")
 	Cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Validate if delete would be successful")
 	Cmd.Flags().BoolVar(&deleteBackingImage, "delete-backing-image", false, "Delete snapshots backing AMI")
+	Cmd.Flags().StringVar(&snapshotId, "snapshot-id", "", "Delete specific snapshot id")
 }
 
-func parseInUseSnapshotImageIdErr(errorMsg string) (string, error) {
-	pattern := regexp.MustCompile(`.*(ami-[0-9a-z]{17}).*`)
-	matches := pattern.FindStringSubmatch(errorMsg)
-	if len(matches) != 2 {
-		return "", fmt.Errorf("Unexpected number of in use Image ID's for snapshot: %v", matches)
+// withBackingImages returns the snapshots to delete along with the AMIs
+// they back with --delete-backing-image. Snapshots backing an AMI are left
+// out without it, or when the AMI is protected.
+func withBackingImages(ctx context.Context, reporter *rprtr.Object, clients aws.ClientFunc, protection resources.Protection, snapshots []*resource.Resource) ([]*resource.Resource, error) {
+	images, err := resource.New("images", clients)
+	if err != nil {
+		return nil, reporter.Errorf("%s", err)
 	}
-	return matches[1], nil
-}
-
-func deleteSnapshot(awsClient aws.Client, cmd *cobra.Command, reporter *rprtr.Object, snapshot *ec2.Snapshot, dryRun bool) error {
-	input := &ec2.DeleteSnapshotInput{
-		DryRun:     &dryRun,
-		SnapshotId: snapshot.SnapshotId,
-	}
-
-	// Output from delete snapshot doesn't contain information we need
-	_, err := awsClient.DeleteSnapshot(input)
-	if aerr, ok := err.(awserr.Error); ok {
-		switch aerr.Code() {
-		case InvalidSnapshotInUse:
-			reporter.Infof("Snapshot in use with backing AMI use --delete-backing-image to force delete: %s", aerr.Message())
-			amiId, err := parseInUseSnapshotImageIdErr(aerr.Message())
-			if err != nil {
-				return reporter.Errorf("Parsing of snapshot (%s) in use error: %v", *snapshot.SnapshotId, err)
-			}
-			if deleteBackingImage {
-				flags := images.Cmd.Flags()
-				// Setting image ID for deletion
-				err = flags.Set("image-id", amiId)
-				if err != nil {
-					_ = reporter.Errorf("Unable to set image flag: %s", err)
-				}
-				// Setting dry-run flag if specified
-				err = flags.Set("dry-run", strconv.FormatBool(dryRun))
-				if err != nil {
-					_ = reporter.Errorf("Unable to set dry run flag: %s", err)
-				}
-				err = images.Cmd.RunE(cmd, []string{})
-				if err != nil {
-					return reporter.Errorf("Unable to list EC2 instances: %s", err)
-				}
-				_, err = awsClient.DeleteSnapshot(input)
-				if err != nil {
-					return reporter.Errorf("Unable to delete snapshot: %s", err)
-				}
-				reporter.Infof("Snapshot %s deleted", *snapshot.SnapshotId)
-			}
-		// Don't return the error here just report that the deletion would have been
-		// successful without the dryRun flag set
-		case "DryRunOperation":
-			reporter.Infof("deletion of %s in %s", *snapshot.SnapshotId, aerr.Message())
-			return nil
-		default:
-			return reporter.Errorf("error %s", err)
 
+	var regions []string
+	seen := map[string]bool{}
+	for _, s := range snapshots {
+		if !seen[s.Region] {
+			seen[s.Region] = true
+			regions = append(regions, s.Region)
 		}
-	} else {
-		return reporter.Errorf("Delete snapshot failed: %s", err)
-
 	}
-	return nil
-}
-
-func describeSnapshots(awsClient aws.Client, reporter *rprtr.Object) ([]*ec2.Snapshot, error) {
-	owner := "self"
+	results, err := resources.Scan(ctx, reporter, images, regions)
+	if err != nil {
+		return nil, err
+	}
 
-	input := &ec2.DescribeSnapshotsInput{
-		OwnerIds: []*string{&owner},
+	backing := map[string][]*resource.Resource{}
+	for _, image := range resources.Flatten(results) {
+		for _, id := range strings.Split(image.Properties["snapshots"], ",") {
+			backing[id] = append(backing[id], image)
+		}
 	}
 
-	var snapshots []*ec2.Snapshot
-	err := awsClient.DescribeSnapshotsPages(input, func(output *ec2.DescribeSnapshotsOutput, lastPage bool) bool {
-		snapshots = append(snapshots, output.Snapshots...)
-		return !lastPage
-	})
-	if err != nil {
-		return nil, reporter.Errorf("Unable to describe snapshots %s", err)
+	var candidates []*resource.Resource
+	deregistered := map[string]bool{}
+	for _, s := range snapshots {
+		if len(backing[s.ID]) > 0 && !deleteBackingImage {
+			reporter.Infof("Snapshot %s is in use by %s, use --delete-backing-image to include it", s.ID, backing[s.ID][0].ID)
+			continue
+		}
+		var protected bool
+		for _, image := range backing[s.ID] {
+			if reason, ok := protection.Match(image); ok {
+				reporter.Infof("Skipping snapshot %s, its image %s is protected by %s", s.ID, image.ID, reason)
+				protected = true
+				break
+			}
+		}
+		if protected {
+			continue
+		}
+		for _, image := range backing[s.ID] {
+			if !deregistered[image.ID] {
+				deregistered[image.ID] = true
+				candidates = append(candidates, image)
+			}
+		}
+		candidates = append(candidates, s)
 	}
-	return snapshots, err
+	return candidates, nil
 }
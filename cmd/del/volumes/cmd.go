@@ -0,0 +1,212 @@
+/*
+Copyright © 2022 James Harrington <james@harrington.net.au>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package volumes
+
+import (
+	"context"
+
+	"github.com/jharrington22/aws-resource/cmd/resources"
+	"github.com/jharrington22/aws-resource/pkg/arguments"
+	"github.com/jharrington22/aws-resource/pkg/aws"
+	logging "github.com/jharrington22/aws-resource/pkg/logging"
+	rprtr "github.com/jharrington22/aws-resource/pkg/reporter"
+	"github.com/jharrington22/aws-resource/pkg/resource"
+	"github.com/spf13/cobra"
+)
+
+var (
+	allRegions     bool
+	dryRun         bool
+	unattachedOnly bool
+	snapshotFirst  bool
+	volumeId       string
+)
+
+// Cmd represents the volumes command
+var Cmd = &cobra.Command{
+	Use:     "volumes",
+	Aliases: []string{"volume"},
+	Short:   "Delete EBS volumes",
+	Long: `Delete unattached EBS volumes for all or a specific region
+
+aws-resource delete volumes --region <region name>
+aws-resource delete volumes --volume-id <volume id> --snapshot-first`,
+	RunE: resources.AcrossAccounts(run),
+}
+
+func run(cmd *cobra.Command, args []string) (err error) {
+
+	reporter := rprtr.CreateReporterOrExit()
+	logging := logging.CreateLoggerOrExit(reporter)
+
+	if snapshotFirst && resources.Planning() {
+		return reporter.Errorf("--snapshot-first can't be used with --plan-out, applying a plan only deletes resources")
+	}
+	if snapshotFirst && arguments.DeleteStack {
+		return reporter.Errorf("--snapshot-first can't be used with --delete-stack, deleting a stack doesn't snapshot its volumes")
+	}
+
+	clients := resources.Clients(cmd, logging)
+	provider, err := resource.New("volumes", clients)
+	if err != nil {
+		return reporter.Errorf("%s", err)
+	}
+
+	protection, err := resources.NewProtection(reporter)
+	if err != nil {
+		return err
+	}
+
+	var results []resource.RegionResult
+	if allRegions {
+		reporter.Infof("Deleting ebs volumes in all regions")
+		results, err = resources.Find(cmd.Context(), reporter, clients, provider)
+	} else {
+		reporter.Infof("Deleting ebs volumes in %s", arguments.Region)
+		results, err = resources.FindIn(cmd.Context(), reporter, provider, []string{arguments.Region})
+	}
+	if err != nil {
+		return err
+	}
+
+	var selected []resource.RegionResult
+	var found bool
+	for _, result := range results {
+		var volumes, attached []*resource.Resource
+		for _, v := range result.Resources {
+			if volumeId != "" && v.ID != volumeId {
+				continue
+			}
+			found = true
+			if unattachedOnly && v.Properties["attached"] == "true" {
+				attached = append(attached, v)
+				continue
+			}
+			volumes = append(volumes, v)
+		}
+		switch {
+		case len(attached) == 0:
+		case volumeId != "":
+			reporter.Infof("Skipping volume %s, attached to %s", volumeId, attached[0].Properties["instances"])
+		default:
+			reporter.Infof("Skipping %d attached volumes in %s", len(attached), result.Region)
+		}
+		selected = append(selected, resource.RegionResult{Region: result.Region, Resources: volumes})
+	}
+
+	msg := arguments.Region
+	if allRegions {
+		msg = "all regions"
+	}
+	if volumeId != "" && !found {
+		reporter.Infof("Volume %s not found in %s", volumeId, msg)
+		return nil
+	}
+
+	selected = protection.FilterResults(reporter, selected)
+	volumes := resources.Flatten(selected)
+
+	if len(volumes) == 0 {
+		reporter.Infof("No volumes to delete found in %s", msg)
+		return nil
+	}
+
+	if resources.Planning() {
+		return resources.SavePlan(reporter, clients, volumes)
+	}
+	if !dryRun {
+		err = resources.Confirm(reporter, clients, volumes)
+		if err != nil {
+			return err
+		}
+	}
+
+	if snapshotFirst {
+		return deleteAfterSnapshot(cmd.Context(), reporter, clients, provider, protection, volumes)
+	}
+	return resources.Delete(cmd.Context(), reporter, clients, provider, selected, dryRun)
+}
+
+// deleteAfterSnapshot deletes every volume once a final snapshot of it has
+// completed. A volume is kept when its snapshot fails. Volumes are looked up
+// again before being snapshotted, so that only the ones still deletable get
+// a snapshot.
+func deleteAfterSnapshot(ctx context.Context, reporter *rprtr.Object, clients aws.ClientFunc, provider resource.Provider, protection resources.Protection, volumes []*resource.Resource) error {
+	if !dryRun {
+		reporter.Warnf("Dry run %t will delete resources", dryRun)
+	}
+
+	for _, v := range volumes {
+		current, err := resource.Get(ctx, provider, v.Region, v.ID)
+		if err != nil {
+			return reporter.Errorf("Unable to check whether %s is protected: %s", v.ID, err)
+		}
+		if current == nil {
+			reporter.Infof("Skipping %s in %s, it no longer exists", v.ID, v.Region)
+			continue
+		}
+		if reason, ok := protection.Protected(current); ok {
+			reporter.Infof("Skipping %s %s in %s, protected by %s", current.Type, current.ID, current.Region, reason)
+			continue
+		}
+		if unattachedOnly && current.Properties["attached"] == "true" {
+			reporter.Infof("Skipping volume %s, attached to %s", current.ID, current.Properties["instances"])
+			continue
+		}
+
+		reporter.Infof("Creating a final snapshot of %s in %s", v.ID, v.Region)
+		snapshot, err := resource.SnapshotVolume(ctx, clients, v, dryRun)
+		if err != nil {
+			return reporter.Errorf("Unable to snapshot %s, keeping it: %s", v.ID, err)
+		}
+		if snapshot != nil {
+			reporter.Infof("Snapshot %s of %s completed", snapshot.ID, v.ID)
+		}
+
+		err = provider.Delete(ctx, v, dryRun)
+		if resources.Skipped(reporter, err) {
+			continue
+		}
+		err = resources.Unrecorded(reporter, err)
+		if err != nil {
+			return reporter.Errorf("Unable to delete %s: %s", v.ID, err)
+		}
+		if dryRun {
+			reporter.Infof("Deletion of %s would have succeeded", v.ID)
+		} else {
+			reporter.Infof("Deleted %s", v.ID)
+		}
+	}
+	return nil
+}
+
+func init() {
+	// Add global flags
+	flags := Cmd.Flags()
+	arguments.AddFlags(flags)
+	arguments.AddFilterFlags(flags)
+	arguments.AddAccountFlags(flags)
+	arguments.AddProtectFlags(flags)
+	arguments.AddStackFlag(flags)
+	arguments.AddPlanFlag(flags)
+	arguments.AddConfirmFlag(flags)
+
+	Cmd.Flags().BoolVar(&allRegions, "all-regions", false, "Delete volumes in all regions")
+	Cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Validate if delete would be successful")
+	Cmd.Flags().BoolVar(&unattachedOnly, "unattached-only", true, "Only delete volumes that aren't attached to an instance")
+	Cmd.Flags().BoolVar(&snapshotFirst, "snapshot-first", false, "Create a final snapshot of each volume and wait for it to complete before deleting the volume")
+	Cmd.Flags().StringVar(&volumeId, "volume-id", "", "Delete specific volume id")
+}
@@ -0,0 +1,36 @@
+/*
+Copyright © 2022 James Harrington <james@harrington.net.au>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package inventory
+
+import (
+	"github.com/jharrington22/aws-resource/cmd/inventory/diff"
+	"github.com/jharrington22/aws-resource/cmd/inventory/save"
+	"github.com/spf13/cobra"
+)
+
+// InventoryCmd represents the inventory command
+var InventoryCmd = &cobra.Command{
+	Use:   "inventory",
+	Short: "Save and compare inventories of AWS resources",
+	Long: `Save every resource of an account to a file and compare saved inventories
+aws-resource inventory save -o inv.json
+aws-resource inventory diff old.json new.json`,
+}
+
+func init() {
+	InventoryCmd.AddCommand(diff.Cmd)
+	InventoryCmd.AddCommand(save.Cmd)
+}
@@ -0,0 +1,127 @@
+/*
+Copyright © 2022 James Harrington <james@harrington.net.au>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package diff
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jharrington22/aws-resource/pkg/arguments"
+	"github.com/jharrington22/aws-resource/pkg/inventory"
+	"github.com/jharrington22/aws-resource/pkg/output"
+	rprtr "github.com/jharrington22/aws-resource/pkg/reporter"
+	"github.com/spf13/cobra"
+)
+
+// Cmd represents the inventory diff command
+var Cmd = &cobra.Command{
+	Use:   "diff <old inventory> <new inventory>",
+	Short: "Compare two saved inventories",
+	Long: `Report the resources added, removed and changed between two inventories
+saved by inventory save
+
+aws-resource inventory diff old.json new.json`,
+	Args: cobra.ExactArgs(2),
+	RunE: run,
+}
+
+func run(cmd *cobra.Command, args []string) (err error) {
+	reporter := rprtr.CreateReporterOrExit()
+
+	format, err := output.ParseFormat(arguments.Output)
+	if err != nil {
+		return reporter.Errorf("%s", err)
+	}
+
+	old, err := inventory.Load(args[0])
+	if err != nil {
+		return reporter.Errorf("Unable to load inventory: %s", err)
+	}
+	new, err := inventory.Load(args[1])
+	if err != nil {
+		return reporter.Errorf("Unable to load inventory: %s", err)
+	}
+	if old.AccountID != new.AccountID {
+		reporter.Warnf("Comparing inventories of different accounts, %s and %s", old.AccountID, new.AccountID)
+	}
+
+	diff := inventory.Compare(old, new)
+	reporter.Infof("Comparing %s (%s) with %s (%s)",
+		args[0], old.CreatedAt.Format("2006-01-02 15:04:05 MST"), args[1], new.CreatedAt.Format("2006-01-02 15:04:05 MST"))
+
+	if format == output.None {
+		write(diff)
+	} else {
+		err = output.Write(os.Stdout, format, diff.Records(), rows(diff))
+		if err != nil {
+			return reporter.Errorf("Unable to write output: %s", err)
+		}
+	}
+
+	if diff.Empty() {
+		reporter.Infof("No differences found")
+	} else {
+		reporter.Infof("%d added, %d removed, %d changed", len(diff.Added), len(diff.Removed), len(diff.Changed))
+	}
+	return nil
+}
+
+// write prints the diff to stdout with a line per resource, prefixed with
+// + when it was added, - when it was removed and ~ when it changed.
+func write(diff *inventory.Diff) {
+	for _, r := range diff.Added {
+		fmt.Printf("+ %s\n", describe(r))
+	}
+	for _, r := range diff.Removed {
+		fmt.Printf("- %s\n", describe(r))
+	}
+	for _, c := range diff.Changed {
+		var changes []string
+		for _, field := range c.Fields {
+			changes = append(changes, fmt.Sprintf("%s %q -> %q",
+				field, inventory.Value(c.Old, field), inventory.Value(c.New, field)))
+		}
+		fmt.Printf("~ %s: %s\n", describe(c.New), strings.Join(changes, ", "))
+	}
+}
+
+func describe(r output.ResourceRecord) string {
+	parts := []string{r.Type, r.Region, r.ID}
+	if r.Account != "" {
+		parts = append([]string{r.Account}, parts...)
+	}
+	if r.Name != "" && r.Name != r.ID {
+		parts = append(parts, fmt.Sprintf("(%s)", r.Name))
+	}
+	return strings.Join(parts, " ")
+}
+
+func rows(diff *inventory.Diff) output.Rows {
+	rows := output.Rows{
+		Headers: []string{"change", "type", "region", "id", "name", "fields"},
+	}
+	for _, r := range diff.Records() {
+		rows.Values = append(rows.Values, []string{
+			r.Change, r.Resource.Type, r.Resource.Region, r.Resource.ID, r.Resource.Name, strings.Join(r.Fields, " "),
+		})
+	}
+	return rows
+}
+
+func init() {
+	arguments.AddOutputFlag(Cmd.Flags())
+}
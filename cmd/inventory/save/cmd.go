@@ -0,0 +1,97 @@
+/*
+Copyright © 2022 James Harrington <james@harrington.net.au>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package save
+
+import (
+	"strings"
+
+	"github.com/jharrington22/aws-resource/cmd/resources"
+	"github.com/jharrington22/aws-resource/pkg/arguments"
+	"github.com/jharrington22/aws-resource/pkg/inventory"
+	logging "github.com/jharrington22/aws-resource/pkg/logging"
+	rprtr "github.com/jharrington22/aws-resource/pkg/reporter"
+	"github.com/jharrington22/aws-resource/pkg/resource"
+	"github.com/spf13/cobra"
+)
+
+var (
+	out string
+)
+
+// Cmd represents the inventory save command
+var Cmd = &cobra.Command{
+	Use:   "save",
+	Short: "Save an inventory of every AWS resource",
+	Long: `Save every resource found by the list commands, along with its tags, state
+and creation time, to a file that can be compared with inventory diff
+
+aws-resource inventory save -o inv.json`,
+	Args: cobra.NoArgs,
+	RunE: run,
+}
+
+func run(cmd *cobra.Command, args []string) (err error) {
+	reporter := rprtr.CreateReporterOrExit()
+	logging := logging.CreateLoggerOrExit(reporter)
+
+	if out == "" {
+		return reporter.Errorf("The file to save the inventory to must be given with -o")
+	}
+
+	clients := resources.Clients(cmd, logging)
+	accountID, err := resources.AccountID(reporter, clients)
+	if err != nil {
+		return err
+	}
+
+	// An inventory missing a resource type or region would show all of
+	// its resources as removed when compared, so any failure aborts
+	var found []*resource.Resource
+	var failed []string
+	for _, typ := range resource.Types() {
+		p, err := resource.New(typ, clients)
+		if err != nil {
+			return reporter.Errorf("%s", err)
+		}
+		results, err := resources.Find(cmd.Context(), reporter, clients, p)
+		if err != nil {
+			failed = append(failed, typ)
+			continue
+		}
+		typeResources := resources.Flatten(results)
+		reporter.Infof("Found %d %s", len(typeResources), p.Describe())
+		found = append(found, typeResources...)
+	}
+	if len(failed) > 0 {
+		return reporter.Errorf("Unable to list %s, not saving an incomplete inventory", strings.Join(failed, ", "))
+	}
+
+	inv := inventory.New(accountID, found)
+	err = inv.Save(out)
+	if err != nil {
+		return reporter.Errorf("Unable to save inventory: %s", err)
+	}
+	reporter.Infof("Saved inventory of %d resources in account %s to %s", len(inv.Resources), accountID, out)
+	return nil
+}
+
+func init() {
+	flags := Cmd.Flags()
+	arguments.AddFlags(flags)
+	arguments.AddFilterFlags(flags)
+
+	flags.StringVarP(&out, "out", "o", "", "File to save the inventory to")
+}
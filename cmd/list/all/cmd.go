@@ -16,15 +16,13 @@ limitations under the License.
 package all
 
 import (
-	ec2cmd "github.com/jharrington22/aws-resource/cmd/list/ec2"
-	elbcmd "github.com/jharrington22/aws-resource/cmd/list/elb"
-	elbv2cmd "github.com/jharrington22/aws-resource/cmd/list/elbv2"
-	route53cmd "github.com/jharrington22/aws-resource/cmd/list/route53"
-	snapshotscmd "github.com/jharrington22/aws-resource/cmd/list/snapshots"
-	volumescmd "github.com/jharrington22/aws-resource/cmd/list/volumes"
+	"strings"
+
+	"github.com/jharrington22/aws-resource/cmd/resources"
 	"github.com/jharrington22/aws-resource/cmd/whoami"
 	"github.com/jharrington22/aws-resource/pkg/arguments"
 	rprtr "github.com/jharrington22/aws-resource/pkg/reporter"
+	"github.com/jharrington22/aws-resource/pkg/resource"
 	"github.com/spf13/cobra"
 )
 
@@ -35,12 +33,22 @@ var Cmd = &cobra.Command{
 	Long: `List all AWS resources supported by aws-resource
 
 aws-resource list all`,
-	RunE: run,
+	RunE: resources.AcrossAccounts(run),
 }
 
 func run(cmd *cobra.Command, args []string) (err error) {
 	reporter := rprtr.CreateReporterOrExit()
 
+	if _, err := resources.OutputFormat(reporter); err != nil {
+		return err
+	}
+	if _, err := resources.NewFilter(reporter); err != nil {
+		return err
+	}
+	if _, err := resources.Prices(reporter); err != nil {
+		return err
+	}
+
 	reporter.Infof("Listing all resources")
 
 	if arguments.Profile != "" || arguments.RoleArn != "" {
@@ -50,36 +58,32 @@ func run(cmd *cobra.Command, args []string) (err error) {
 		}
 	}
 
-	err = ec2cmd.Cmd.RunE(cmd, args)
-	if err != nil {
-		return reporter.Errorf("Unable to list EC2 instances: %s", err)
-	}
-
-	err = elbcmd.Cmd.RunE(cmd, args)
-	if err != nil {
-		return reporter.Errorf("Unable to list ELB instances: %s", err)
-	}
-
-	err = elbv2cmd.Cmd.RunE(cmd, args)
-	if err != nil {
-		return reporter.Errorf("Unable to list ELB V2 instances: %s", err)
-	}
-
-	err = route53cmd.Cmd.RunE(cmd, args)
-	if err != nil {
-		return reporter.Errorf("Unable to list route53 hosted zones: %s", err)
+	// Run the list command of every registered resource type so that any
+	// type specific output is included. A failing type doesn't stop the
+	// others from being listed.
+	resources.StartBatch()
+	var failed []string
+	for _, typ := range resource.Types() {
+		typeCmd, _, err := cmd.Parent().Find([]string{typ})
+		if err != nil || typeCmd == cmd.Parent() {
+			_ = reporter.Errorf("Unable to find list command for %s", typ)
+			failed = append(failed, typ)
+			continue
+		}
+		err = typeCmd.RunE(cmd, args)
+		if err != nil {
+			failed = append(failed, typ)
+		}
 	}
 
-	err = snapshotscmd.Cmd.RunE(cmd, args)
+	err = resources.FlushBatch(reporter)
 	if err != nil {
-		return reporter.Errorf("Unable to list snapshots: %s", err)
+		return err
 	}
 
-	err = volumescmd.Cmd.RunE(cmd, args)
-	if err != nil {
-		return reporter.Errorf("Unable to list volumes: %s", err)
+	if len(failed) > 0 {
+		return reporter.Errorf("Unable to list %s", strings.Join(failed, ", "))
 	}
-
 	return
 }
 
@@ -87,4 +91,8 @@ func init() {
 	// Add global flags
 	flags := Cmd.Flags()
 	arguments.AddFlags(flags)
+	arguments.AddOutputFlag(flags)
+	arguments.AddFilterFlags(flags)
+	arguments.AddAccountFlags(flags)
+	arguments.AddCostFlags(flags)
 }
@@ -20,12 +20,15 @@ import (
 
 	"github.com/jharrington22/aws-resource/cmd/list/all"
 	"github.com/jharrington22/aws-resource/cmd/list/ec2"
-	"github.com/jharrington22/aws-resource/cmd/list/elb"
-	"github.com/jharrington22/aws-resource/cmd/list/elbv2"
+	"github.com/jharrington22/aws-resource/cmd/list/eips"
+	"github.com/jharrington22/aws-resource/cmd/list/eks"
 	"github.com/jharrington22/aws-resource/cmd/list/images"
-	"github.com/jharrington22/aws-resource/cmd/list/route53"
+	"github.com/jharrington22/aws-resource/cmd/list/s3"
 	"github.com/jharrington22/aws-resource/cmd/list/snapshots"
+	"github.com/jharrington22/aws-resource/cmd/list/stacks"
 	"github.com/jharrington22/aws-resource/cmd/list/volumes"
+	"github.com/jharrington22/aws-resource/cmd/resources"
+	"github.com/jharrington22/aws-resource/pkg/resource"
 	"github.com/spf13/cobra"
 )
 
@@ -35,11 +38,18 @@ var ListCmd = &cobra.Command{
 	Short: "List AWS resources",
 	Long: `List AWS resources
 aws-resource list ec2
+aws-resource list eips
+aws-resource list eks
 aws-resource list elb
 aws-resource list elbv2
 aws-resource list images
+aws-resource list natgateways
+aws-resource list rds
 aws-resource list route53
+aws-resource list s3
 aws-resource list snapshots
+aws-resource list stacks
+aws-resource list targetgroups
 aws-resource list volumes`,
 	Run: func(cmd *cobra.Command, args []string) {
 		fmt.Println("list called")
@@ -50,13 +60,21 @@ func init() {
 
 	ListCmd.AddCommand(all.Cmd)
 	ListCmd.AddCommand(ec2.Cmd)
-	ListCmd.AddCommand(elb.Cmd)
-	ListCmd.AddCommand(elbv2.Cmd)
+	ListCmd.AddCommand(eips.Cmd)
+	ListCmd.AddCommand(eks.Cmd)
 	ListCmd.AddCommand(images.Cmd)
-	ListCmd.AddCommand(route53.Cmd)
+	ListCmd.AddCommand(s3.Cmd)
 	ListCmd.AddCommand(snapshots.Cmd)
+	ListCmd.AddCommand(stacks.Cmd)
 	ListCmd.AddCommand(volumes.Cmd)
 
+	// Resource types without a dedicated command get a generic one
+	for _, typ := range resource.Types() {
+		if !resources.HasCommand(ListCmd, typ) {
+			ListCmd.AddCommand(resources.NewListCmd(typ))
+		}
+	}
+
 	// Here you will define your flags and configuration settings.
 
 	// Cobra supports Persistent Flags which will work for this command
@@ -18,11 +18,11 @@ package ec2
 import (
 	"strings"
 
-	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/jharrington22/aws-resource/cmd/resources"
 	"github.com/jharrington22/aws-resource/pkg/arguments"
-	"github.com/jharrington22/aws-resource/pkg/aws"
 	logging "github.com/jharrington22/aws-resource/pkg/logging"
 	rprtr "github.com/jharrington22/aws-resource/pkg/reporter"
+	"github.com/jharrington22/aws-resource/pkg/resource"
 	"github.com/spf13/cobra"
 )
 
@@ -40,103 +40,48 @@ var Cmd = &cobra.Command{
 	Long: `List EC2 instances for all or a specific region
 
 aws-resource list ec2`,
-	RunE: run,
+	RunE: resources.AcrossAccounts(run),
 }
 
 func run(cmd *cobra.Command, args []string) (err error) {
 	reporter := rprtr.CreateReporterOrExit()
 	logging := logging.CreateLoggerOrExit(reporter)
 
-	reporter.Infof("Listing ec2 instances")
-
-	awsClient, err := aws.NewClient().
-		Logger(logging).
-		Profile(arguments.Profile).
-		RoleArn(arguments.RoleArn).
-		Region(arguments.Region).
-		Build()
-
-	if err != nil {
-		return reporter.Errorf("Unable to build AWS client")
-	}
-
-	regions, err := awsClient.DescribeRegions(&ec2.DescribeRegionsInput{})
+	clients := resources.Clients(cmd, logging)
+	provider, err := resource.New("ec2", clients)
 	if err != nil {
-		return reporter.Errorf("Failed to describe regions; %s", err)
+		return reporter.Errorf("%s", err)
 	}
 
-	var instancesFound bool
-
-	for _, region := range regions.Regions {
-
-		regionName := *region.RegionName
-
-		awsClient, err := aws.NewClient().
-			Logger(logging).
-			Profile(arguments.Profile).
-			RoleArn(arguments.RoleArn).
-			Region(regionName).
-			Build()
-
-		if err != nil {
-			return reporter.Errorf("Unable to build AWS client in %s", regionName)
-		}
-
-		input := &ec2.DescribeInstancesInput{}
-
-		result, err := awsClient.DescribeInstances(input)
-		if err != nil {
-			return reporter.Errorf("Unable to describe instances: %s", err)
-		}
+	_, err = resources.List(cmd.Context(), reporter, clients, provider, resources.ListOptions{
+		Detail: detail,
+	})
+	return
+}
 
-		var runningInstanceList []*ec2.Instance
-		var detail [][]string
-		for _, r := range result.Reservations {
-			for _, i := range r.Instances {
-				var instanceDetail []string
-				if *i.State.Name == "running" {
-					runningInstanceList = append(runningInstanceList, i)
-					if instanceNames {
-						instanceDetail = append(instanceDetail, getInstanceName(i.Tags))
-					}
-					if launchTime {
-						instanceDetail = append(instanceDetail, i.LaunchTime.String())
-					}
-					if instanceType {
-						instanceDetail = append(instanceDetail, *i.InstanceType)
-					}
-					if imageId {
-						instanceDetail = append(instanceDetail, *i.ImageId)
-					}
-				}
-				detail = append(detail, instanceDetail)
-			}
-		}
-		if len(runningInstanceList) > 0 {
-			instancesFound = true
-			reporter.Infof("Found %d running instances in %s", len(runningInstanceList), regionName)
-		}
-		if (instanceNames || launchTime || instanceType) && len(detail) > 0 {
-			for _, d := range detail {
-				reporter.Infof("%s", strings.Join(d, ", "))
-			}
-		}
+func detail(r *resource.Resource) string {
+	var instanceDetail []string
+	if instanceNames {
+		instanceDetail = append(instanceDetail, getInstanceName(r.Tags))
 	}
-	if !instancesFound {
-		reporter.Infof("No instances found in account")
+	if launchTime {
+		instanceDetail = append(instanceDetail, r.CreatedAt.String())
 	}
-
-	return
+	if instanceType {
+		instanceDetail = append(instanceDetail, r.Properties["instance-type"])
+	}
+	if imageId {
+		instanceDetail = append(instanceDetail, r.Properties["image-id"])
+	}
+	return strings.Join(instanceDetail, ", ")
 }
 
-func getInstanceName(tags []*ec2.Tag) string {
+func getInstanceName(tags map[string]string) string {
 	if len(tags) == 0 {
 		return "Instance has no tags"
 	}
-	for _, t := range tags {
-		if *t.Key == "Name" {
-			return *t.Value
-		}
+	if name, ok := tags["Name"]; ok {
+		return name
 	}
 	return "Instance has no tag \"Name\""
 }
@@ -145,6 +90,10 @@ func init() {
 	// Add global flags
 	flags := Cmd.Flags()
 	arguments.AddFlags(flags)
+	arguments.AddOutputFlag(flags)
+	arguments.AddFilterFlags(flags)
+	arguments.AddAccountFlags(flags)
+	arguments.AddCostFlags(flags)
 
 	Cmd.Flags().BoolVar(&imageId, "image-id", false, "Print image id")
 	Cmd.Flags().BoolVar(&instanceNames, "instance-names", false, "Print instance names")
@@ -0,0 +1,76 @@
+/*
+Copyright © 2022 James Harrington <james@harrington.net.au>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package eips
+
+import (
+	"strings"
+
+	"github.com/jharrington22/aws-resource/cmd/resources"
+	"github.com/jharrington22/aws-resource/pkg/arguments"
+	logging "github.com/jharrington22/aws-resource/pkg/logging"
+	rprtr "github.com/jharrington22/aws-resource/pkg/reporter"
+	"github.com/jharrington22/aws-resource/pkg/resource"
+	"github.com/spf13/cobra"
+)
+
+// Cmd represents the eips command
+var Cmd = &cobra.Command{
+	Use:   "eips",
+	Short: "List Elastic IPs",
+	Long: `List Elastic IPs for all or a specific region, flagging the addresses
+that aren't associated, which are charged while idle
+
+aws-resource list eips`,
+	RunE: resources.AcrossAccounts(run),
+}
+
+func run(cmd *cobra.Command, args []string) (err error) {
+	reporter := rprtr.CreateReporterOrExit()
+	logging := logging.CreateLoggerOrExit(reporter)
+
+	clients := resources.Clients(cmd, logging)
+	provider, err := resource.New("eips", clients)
+	if err != nil {
+		return reporter.Errorf("%s", err)
+	}
+
+	_, err = resources.List(cmd.Context(), reporter, clients, provider, resources.ListOptions{
+		Summary: func(result resource.RegionResult) {
+			var unassociated []string
+			for _, r := range result.Resources {
+				if r.Properties["associated"] != "true" {
+					unassociated = append(unassociated, r.Properties["public-ip"])
+				}
+			}
+			reporter.Infof("%d associated addresses in %s", len(result.Resources)-len(unassociated), result.Region)
+			if len(unassociated) > 0 {
+				reporter.Warnf("%d unassociated addresses in %s: %s", len(unassociated), result.Region, strings.Join(unassociated, ", "))
+			}
+		},
+	})
+	return
+
+}
+
+func init() {
+	// Add global flags
+	flags := Cmd.Flags()
+	arguments.AddFlags(flags)
+	arguments.AddOutputFlag(flags)
+	arguments.AddFilterFlags(flags)
+	arguments.AddAccountFlags(flags)
+	arguments.AddCostFlags(flags)
+}
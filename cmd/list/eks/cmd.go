@@ -0,0 +1,94 @@
+/*
+Copyright © 2022 James Harrington <james@harrington.net.au>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package eks
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jharrington22/aws-resource/cmd/resources"
+	"github.com/jharrington22/aws-resource/pkg/arguments"
+	"github.com/jharrington22/aws-resource/pkg/duration"
+	logging "github.com/jharrington22/aws-resource/pkg/logging"
+	rprtr "github.com/jharrington22/aws-resource/pkg/reporter"
+	"github.com/jharrington22/aws-resource/pkg/resource"
+	"github.com/spf13/cobra"
+)
+
+// Cmd represents the eks command
+var Cmd = &cobra.Command{
+	Use:   "eks",
+	Short: "List EKS clusters",
+	Long: `List EKS clusters for all or a specific region, with the age of their
+control plane, along with their nodegroups and Fargate profiles
+
+aws-resource list eks`,
+	RunE: resources.AcrossAccounts(run),
+}
+
+func run(cmd *cobra.Command, args []string) (err error) {
+	reporter := rprtr.CreateReporterOrExit()
+	logging := logging.CreateLoggerOrExit(reporter)
+
+	clients := resources.Clients(cmd, logging)
+	provider, err := resource.New("eks", clients)
+	if err != nil {
+		return reporter.Errorf("%s", err)
+	}
+
+	_, err = resources.List(cmd.Context(), reporter, clients, provider, resources.ListOptions{
+		Detail: detail,
+	})
+	return
+}
+
+func detail(r *resource.Resource) string {
+	cluster := r.Properties["cluster"]
+	cluster = cluster[strings.LastIndex(cluster, "/")+1:]
+	switch r.Properties["kind"] {
+	case resource.EKSCluster:
+		return fmt.Sprintf("Cluster %s in %s, Kubernetes %s, control plane %s old, %s nodegroups and %s Fargate profiles",
+			r.Name, r.Region, r.Properties["version"], age(time.Since(r.CreatedAt)), r.Properties["nodegroups"], r.Properties["fargate-profiles"])
+	case resource.EKSNodegroup:
+		nodes := r.Properties["desired-size"] + " nodes"
+		if types := r.Properties["instance-types"]; types != "" {
+			nodes = r.Properties["desired-size"] + " " + types + " nodes"
+		}
+		return fmt.Sprintf("  Nodegroup %s of %s, %s", r.Name, cluster, nodes)
+	case resource.EKSFargateProfile:
+		return fmt.Sprintf("  Fargate profile %s of %s, namespaces %s", r.Name, cluster, r.Properties["namespaces"])
+	}
+	return ""
+}
+
+// age formats a duration in days, or in hours below a day.
+func age(d time.Duration) string {
+	if d < duration.Day {
+		return fmt.Sprintf("%d hours", int(d/time.Hour))
+	}
+	return fmt.Sprintf("%d days", int(d/duration.Day))
+}
+
+func init() {
+	// Add global flags
+	flags := Cmd.Flags()
+	arguments.AddFlags(flags)
+	arguments.AddOutputFlag(flags)
+	arguments.AddFilterFlags(flags)
+	arguments.AddAccountFlags(flags)
+	arguments.AddCostFlags(flags)
+}
@@ -16,21 +16,14 @@ limitations under the License.
 package images
 
 import (
-	"os"
-
-	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/jharrington22/aws-resource/cmd/resources"
 	"github.com/jharrington22/aws-resource/pkg/arguments"
-	"github.com/jharrington22/aws-resource/pkg/aws"
 	logging "github.com/jharrington22/aws-resource/pkg/logging"
 	rprtr "github.com/jharrington22/aws-resource/pkg/reporter"
-	"github.com/sirupsen/logrus"
+	"github.com/jharrington22/aws-resource/pkg/resource"
 	"github.com/spf13/cobra"
 )
 
-var (
-	imageId string
-)
-
 // Cmd represents the images command
 var Cmd = &cobra.Command{
 	Use:   "images",
@@ -38,7 +31,7 @@ var Cmd = &cobra.Command{
 	Long: `List AMIs for all or a specific region
 
 aws-resource list images`,
-	RunE: run,
+	RunE: resources.AcrossAccounts(run),
 }
 
 func run(cmd *cobra.Command, args []string) (err error) {
@@ -46,29 +39,31 @@ func run(cmd *cobra.Command, args []string) (err error) {
 	reporter := rprtr.CreateReporterOrExit()
 	logging := logging.CreateLoggerOrExit(reporter)
 
-	reporter.Infof("Listing images")
-
-	awsClient, err := aws.NewClient().
-		Logger(logging).
-		Profile(arguments.Profile).
-		RoleArn(arguments.RoleArn).
-		Region(arguments.Region).
-		Build()
-
-	if err != nil {
-		return reporter.Errorf("Unable to build AWS client")
-	}
-
-	regions, err := awsClient.DescribeRegions(&ec2.DescribeRegionsInput{})
+	clients := resources.Clients(cmd, logging)
+	provider, err := resource.New("images", clients)
 	if err != nil {
-		return reporter.Errorf("Failed to describe regions")
-	}
-
-	err = listAllImages(reporter, logging, regions)
-	if err != nil {
-		_ = reporter.Errorf("Unable to delete image: %s", err)
+		return reporter.Errorf("%s", err)
 	}
 
+	_, err = resources.List(cmd.Context(), reporter, clients, provider, resources.ListOptions{
+		Summary: func(result resource.RegionResult) {
+			var snapshots []string
+			var backed int
+			for _, r := range result.Resources {
+				if s := r.Properties["snapshots"]; s != "" {
+					backed++
+					snapshots = append(snapshots, s)
+				}
+			}
+			reporter.Infof("Found %d snapshot backed images in %s", backed, result.Region)
+			if len(snapshots) > 0 {
+				reporter.Infof("Snapshots:")
+				for _, s := range snapshots {
+					reporter.Infof("%s", s)
+				}
+			}
+		},
+	})
 	return
 }
 
@@ -76,59 +71,8 @@ func init() {
 	// Add global flags
 	flags := Cmd.Flags()
 	arguments.AddFlags(flags)
-	Cmd.Flags().StringVarP(&imageId, "image-id", "i", "", "Delete specific image id")
-}
-
-func listAllImages(reporter *rprtr.Object, logging *logrus.Logger, regions *ec2.DescribeRegionsOutput) error {
-
-	var allSnapshots []*string
-	for _, region := range regions.Regions {
-
-		regionName := *region.RegionName
-
-		awsClient, err := aws.NewClient().
-			Logger(logging).
-			Profile(arguments.Profile).
-			RoleArn(arguments.RoleArn).
-			Region(regionName).
-			Build()
-
-		if err != nil {
-			_ = reporter.Errorf("Unable to build AWS client in %s", regionName)
-			os.Exit(1)
-		}
-
-		owner := "self"
-
-		input := &ec2.DescribeImagesInput{
-			Owners: []*string{&owner},
-		}
-
-		var images []*ec2.Image
-		output, err := awsClient.DescribeImages(input)
-		if err != nil {
-			return reporter.Errorf("Unable to describe images %s", err)
-		}
-
-		for _, image := range output.Images {
-			for _, bdm := range image.BlockDeviceMappings {
-				if bdm.Ebs != nil && bdm.Ebs.SnapshotId != nil {
-					allSnapshots = append(allSnapshots, bdm.Ebs.SnapshotId)
-				}
-			}
-			images = append(images, image)
-		}
-
-		if len(images) > 0 {
-			reporter.Infof("Found %d images in %s", len(images), regionName)
-			reporter.Infof("Found %d snapshot backed images in %s", len(images), regionName)
-			reporter.Infof("Snapshots:")
-			for _, s := range allSnapshots {
-				reporter.Infof("%s", *s)
-			}
-		}
-
-	}
-
-	return nil
+	arguments.AddOutputFlag(flags)
+	arguments.AddFilterFlags(flags)
+	arguments.AddAccountFlags(flags)
+	arguments.AddCostFlags(flags)
 }
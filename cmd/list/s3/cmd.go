@@ -0,0 +1,101 @@
+/*
+Copyright © 2022 James Harrington <james@harrington.net.au>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package s3
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/jharrington22/aws-resource/cmd/resources"
+	"github.com/jharrington22/aws-resource/pkg/arguments"
+	logging "github.com/jharrington22/aws-resource/pkg/logging"
+	rprtr "github.com/jharrington22/aws-resource/pkg/reporter"
+	"github.com/jharrington22/aws-resource/pkg/resource"
+	"github.com/spf13/cobra"
+)
+
+var sampleObjects int
+
+// Cmd represents the s3 command
+var Cmd = &cobra.Command{
+	Use:   "s3",
+	Short: "List S3 buckets",
+	Long: `List S3 buckets with their region, versioning state and an estimate of
+their object count and size, from the first objects of each bucket
+
+aws-resource list s3
+aws-resource list s3 --sample-objects 0`,
+	RunE: resources.AcrossAccounts(run),
+}
+
+func run(cmd *cobra.Command, args []string) (err error) {
+	reporter := rprtr.CreateReporterOrExit()
+	logging := logging.CreateLoggerOrExit(reporter)
+
+	if sampleObjects < 0 {
+		return reporter.Errorf("--sample-objects must not be negative")
+	}
+
+	clients := resources.Clients(cmd, logging)
+	provider, err := resource.New("s3", clients)
+	if err != nil {
+		return reporter.Errorf("%s", err)
+	}
+
+	ctx := resource.WithObjectSample(cmd.Context(), sampleObjects)
+	_, err = resources.List(ctx, reporter, clients, provider, resources.ListOptions{
+		Detail: detail,
+	})
+	return
+}
+
+func detail(r *resource.Resource) string {
+	d := fmt.Sprintf("%s in %s, versioning %s", r.ID, r.Region, r.Properties["versioning"])
+	if r.Properties["objects"] == "" {
+		return d
+	}
+	size, _ := strconv.ParseInt(r.Properties["size"], 10, 64)
+	if r.Properties["sampled"] == "true" {
+		return fmt.Sprintf("%s, more than %s objects and %s", d, r.Properties["objects"], formatSize(size))
+	}
+	return fmt.Sprintf("%s, %s objects, %s", d, r.Properties["objects"], formatSize(size))
+}
+
+// formatSize formats a number of bytes with a binary unit.
+func formatSize(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
+
+func init() {
+	// Add global flags
+	flags := Cmd.Flags()
+	arguments.AddFlags(flags)
+	arguments.AddOutputFlag(flags)
+	arguments.AddFilterFlags(flags)
+	arguments.AddAccountFlags(flags)
+	arguments.AddCostFlags(flags)
+
+	Cmd.Flags().IntVar(&sampleObjects, "sample-objects", resource.DefaultObjectSample, "Number of objects listed in each bucket to estimate its object count and size, 0 to skip the estimate")
+}
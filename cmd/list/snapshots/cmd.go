@@ -17,14 +17,14 @@ package snapshots
 
 import (
 	"fmt"
-	"os"
+	"sort"
 	"strings"
 
-	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/jharrington22/aws-resource/cmd/resources"
 	"github.com/jharrington22/aws-resource/pkg/arguments"
-	"github.com/jharrington22/aws-resource/pkg/aws"
 	logging "github.com/jharrington22/aws-resource/pkg/logging"
 	rprtr "github.com/jharrington22/aws-resource/pkg/reporter"
+	"github.com/jharrington22/aws-resource/pkg/resource"
 	"github.com/spf13/cobra"
 )
 
@@ -41,7 +41,7 @@ var Cmd = &cobra.Command{
 	Long: `List EBS snapshots for all or a specific region
 
 aws-resource list snapshots`,
-	RunE: run,
+	RunE: resources.AcrossAccounts(run),
 }
 
 func run(cmd *cobra.Command, args []string) (err error) {
@@ -49,99 +49,54 @@ func run(cmd *cobra.Command, args []string) (err error) {
 	reporter := rprtr.CreateReporterOrExit()
 	logging := logging.CreateLoggerOrExit(reporter)
 
-	reporter.Infof("Listing ebs snapshots")
-
-	awsClient, err := aws.NewClient().
-		Logger(logging).
-		Profile(arguments.Profile).
-		RoleArn(arguments.RoleArn).
-		Region(arguments.Region).
-		Build()
-
+	clients := resources.Clients(cmd, logging)
+	provider, err := resource.New("snapshots", clients)
 	if err != nil {
-		return reporter.Errorf("Unable to build AWS client")
+		return reporter.Errorf("%s", err)
 	}
 
-	regions, err := awsClient.DescribeRegions(&ec2.DescribeRegionsInput{})
-	if err != nil {
-		return reporter.Errorf("Failed to describe regions")
-	}
+	_, err = resources.List(cmd.Context(), reporter, clients, provider, resources.ListOptions{
+		Detail: detail,
+	})
+	return
 
-	var availableSnapshots []*ec2.Snapshot
-	for _, region := range regions.Regions {
-
-		regionName := *region.RegionName
-
-		awsClient, err := aws.NewClient().
-			Logger(logging).
-			Profile(arguments.Profile).
-			RoleArn(arguments.RoleArn).
-			Region(regionName).
-			Build()
-
-		if err != nil {
-			_ = reporter.Errorf("Unable to build AWS client in %s", regionName)
-			os.Exit(1)
-		}
-
-		owner := "self"
-
-		input := &ec2.DescribeSnapshotsInput{
-			OwnerIds: []*string{&owner},
-		}
-
-		var snapshots []*ec2.Snapshot
-		err = awsClient.DescribeSnapshotsPages(input, func(page *ec2.DescribeSnapshotsOutput, lastPage bool) bool {
-			snapshots = append(snapshots, page.Snapshots...)
-			return page.NextToken != nil
-		})
-		if err != nil {
-			return reporter.Errorf("Unable to describe snapshots %s", err)
-		}
-
-		for _, snapshot := range snapshots {
-			availableSnapshots = append(availableSnapshots, snapshot)
-			if snapshotId || startTime {
-				var detail []string
-				if snapshotId {
-					detail = append(detail, *snapshot.SnapshotId)
-				}
-				if startTime {
-					detail = append(detail, snapshot.StartTime.String())
-				}
-				if tags {
-					detail = append(detail, parseTags(snapshot.Tags)...)
-				}
-				reporter.Infof("Snapshot: %s", strings.Join(detail, ","))
-			}
-		}
-
-		if len(snapshots) > 0 {
-			reporter.Infof("Found %d snapshots in %s", len(snapshots), regionName)
-		}
-	}
+}
 
-	if len(availableSnapshots) == 0 {
-		reporter.Infof("No snapshots found")
+func detail(r *resource.Resource) string {
+	if !snapshotId && !startTime && !tags {
+		return ""
 	}
-
-	return
-
+	var detail []string
+	if snapshotId {
+		detail = append(detail, r.ID)
+	}
+	if startTime {
+		detail = append(detail, r.CreatedAt.String())
+	}
+	if tags {
+		detail = append(detail, parseTags(r.Tags)...)
+	}
+	return fmt.Sprintf("Snapshot: %s", strings.Join(detail, ","))
 }
 
 func init() {
 	// Add global flags
 	flags := Cmd.Flags()
 	arguments.AddFlags(flags)
+	arguments.AddOutputFlag(flags)
+	arguments.AddFilterFlags(flags)
+	arguments.AddAccountFlags(flags)
+	arguments.AddCostFlags(flags)
 	Cmd.Flags().BoolVar(&startTime, "start-time", false, "Time stamp when the snapshot was initiated")
 	Cmd.Flags().BoolVar(&snapshotId, "snapshot-id", false, "The snapshot ID")
 	Cmd.Flags().BoolVar(&tags, "tags", false, "Tags")
 }
 
-func parseTags(tags []*ec2.Tag) []string {
+func parseTags(tags map[string]string) []string {
 	var _tags []string
-	for k, v := range _tags {
-		_tags = append(_tags, fmt.Sprintf("%v: %s", k, v))
+	for k, v := range tags {
+		_tags = append(_tags, fmt.Sprintf("%s: %s", k, v))
 	}
+	sort.Strings(_tags)
 	return _tags
 }
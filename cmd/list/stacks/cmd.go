@@ -0,0 +1,81 @@
+/*
+Copyright © 2022 James Harrington <james@harrington.net.au>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package stacks
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jharrington22/aws-resource/cmd/resources"
+	"github.com/jharrington22/aws-resource/pkg/arguments"
+	logging "github.com/jharrington22/aws-resource/pkg/logging"
+	rprtr "github.com/jharrington22/aws-resource/pkg/reporter"
+	"github.com/jharrington22/aws-resource/pkg/resource"
+	"github.com/spf13/cobra"
+)
+
+// Cmd represents the stacks command
+var Cmd = &cobra.Command{
+	Use:   "stacks",
+	Short: "List CloudFormation stacks",
+	Long: `List CloudFormation stacks for all or a specific region, with their
+status and whether termination protection is enabled
+
+aws-resource list stacks`,
+	RunE: resources.AcrossAccounts(run),
+}
+
+func run(cmd *cobra.Command, args []string) (err error) {
+	reporter := rprtr.CreateReporterOrExit()
+	logging := logging.CreateLoggerOrExit(reporter)
+
+	clients := resources.Clients(cmd, logging)
+	provider, err := resource.New("stacks", clients)
+	if err != nil {
+		return reporter.Errorf("%s", err)
+	}
+
+	_, err = resources.List(cmd.Context(), reporter, clients, provider, resources.ListOptions{
+		Detail: detail,
+	})
+	return
+}
+
+func detail(r *resource.Resource) string {
+	line := fmt.Sprintf("Stack %s in %s, %s", r.Name, r.Region, r.State)
+	if parent := r.Properties["parent"]; parent != "" {
+		// Parent stacks are known by their ID, whose path holds their name
+		parts := strings.Split(parent, "/")
+		if len(parts) > 1 {
+			parent = parts[1]
+		}
+		line += ", nested in " + parent
+	}
+	if r.Properties["termination-protection"] == "true" {
+		line += ", termination protection enabled"
+	}
+	return line
+}
+
+func init() {
+	// Add global flags
+	flags := Cmd.Flags()
+	arguments.AddFlags(flags)
+	arguments.AddOutputFlag(flags)
+	arguments.AddFilterFlags(flags)
+	arguments.AddAccountFlags(flags)
+	arguments.AddCostFlags(flags)
+}
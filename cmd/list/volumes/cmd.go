@@ -16,11 +16,11 @@ limitations under the License.
 package volumes
 
 import (
-	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/jharrington22/aws-resource/cmd/resources"
 	"github.com/jharrington22/aws-resource/pkg/arguments"
-	"github.com/jharrington22/aws-resource/pkg/aws"
 	logging "github.com/jharrington22/aws-resource/pkg/logging"
 	rprtr "github.com/jharrington22/aws-resource/pkg/reporter"
+	"github.com/jharrington22/aws-resource/pkg/resource"
 	"github.com/spf13/cobra"
 )
 
@@ -31,79 +31,31 @@ var Cmd = &cobra.Command{
 	Long: `List EBS volumes for all or a specific region
 
 aws-resource list volumes`,
-	RunE: run,
+	RunE: resources.AcrossAccounts(run),
 }
 
 func run(cmd *cobra.Command, args []string) (err error) {
 	reporter := rprtr.CreateReporterOrExit()
 	logging := logging.CreateLoggerOrExit(reporter)
 
-	reporter.Infof("Listing ebs volumes")
-
-	awsClient, err := aws.NewClient().
-		Logger(logging).
-		Profile(arguments.Profile).
-		RoleArn(arguments.RoleArn).
-		Region(arguments.Region).
-		Build()
-
+	clients := resources.Clients(cmd, logging)
+	provider, err := resource.New("volumes", clients)
 	if err != nil {
-		return reporter.Errorf("Unable to build AWS client")
+		return reporter.Errorf("%s", err)
 	}
 
-	regions, err := awsClient.DescribeRegions(&ec2.DescribeRegionsInput{})
-	if err != nil {
-		return reporter.Errorf("Failed to describe regions")
-	}
-
-	var availableVolumes []*ec2.Volume
-	for _, region := range regions.Regions {
-
-		regionName := *region.RegionName
-
-		awsClient, err := aws.NewClient().
-			Logger(logging).
-			Profile(arguments.Profile).
-			RoleArn(arguments.RoleArn).
-			Region(regionName).
-			Build()
-
-		if err != nil {
-			return reporter.Errorf("Unable to build AWS client in %s", regionName)
-		}
-
-		input := &ec2.DescribeVolumesInput{}
-
-		result, err := awsClient.DescribeVolumes(input)
-		if err != nil {
-			return reporter.Errorf("Unable to describe volumes %s", err)
-		}
-
-		var volumes []*ec2.Volume
-		var attachedVolumes []*ec2.Volume
-		var unattachedVolumes []*ec2.Volume
-		for _, volume := range result.Volumes {
-			volumes = append(volumes, volume)
-			availableVolumes = append(availableVolumes, volume)
-			if len(volume.Attachments) > 0 {
-				attachedVolumes = append(attachedVolumes, volume)
-			} else {
-				unattachedVolumes = append(unattachedVolumes, volume)
+	_, err = resources.List(cmd.Context(), reporter, clients, provider, resources.ListOptions{
+		Summary: func(result resource.RegionResult) {
+			var attached int
+			for _, r := range result.Resources {
+				if r.Properties["attached"] == "true" {
+					attached++
+				}
 			}
-		}
-		if len(volumes) > 0 {
-			reporter.Infof("Found %d volumes in %s", len(volumes), regionName)
-		}
-		if len(volumes) > 0 {
-			reporter.Infof("%d attached volumes in %s", len(attachedVolumes), regionName)
-		}
-		if len(volumes) > 0 {
-			reporter.Infof("%d unattached volumes in %s", len(unattachedVolumes), regionName)
-		}
-	}
-	if len(availableVolumes) == 0 {
-		reporter.Infof("No volumes found")
-	}
+			reporter.Infof("%d attached volumes in %s", attached, result.Region)
+			reporter.Infof("%d unattached volumes in %s", len(result.Resources)-attached, result.Region)
+		},
+	})
 	return
 
 }
@@ -112,4 +64,8 @@ func init() {
 	// Add global flags
 	flags := Cmd.Flags()
 	arguments.AddFlags(flags)
+	arguments.AddOutputFlag(flags)
+	arguments.AddFilterFlags(flags)
+	arguments.AddAccountFlags(flags)
+	arguments.AddCostFlags(flags)
 }
@@ -0,0 +1,193 @@
+/*
+Copyright © 2022 James Harrington <james@harrington.net.au>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package resources
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/service/organizations"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/jharrington22/aws-resource/pkg/arguments"
+	"github.com/jharrington22/aws-resource/pkg/aws"
+	logging "github.com/jharrington22/aws-resource/pkg/logging"
+	rprtr "github.com/jharrington22/aws-resource/pkg/reporter"
+	"github.com/spf13/cobra"
+)
+
+var accountIDPattern = regexp.MustCompile(`^[0-9]{12}$`)
+
+// Account is an account a command runs in when given --all-accounts or
+// --accounts.
+type Account struct {
+	ID   string
+	Name string
+
+	// Target reaches the account, assuming --account-role in member
+	// accounts.
+	Target aws.Target
+
+	// Found is the number of resources found in the account.
+	Found int
+}
+
+// current is the account the command is running in, nil when the command
+// runs in a single account.
+var current *Account
+
+// MultiAccount reports whether the command runs in several accounts.
+func MultiAccount() bool {
+	return arguments.AllAccounts || len(arguments.Accounts) > 0
+}
+
+// AcrossAccounts wraps the RunE function of a list or delete command so that
+// it runs once in each account selected by --all-accounts or --accounts,
+// reporting a summary once every account is done. The resources listed in
+// every account are written as a single record set. Without the flags, or
+// when already running in an account, run is called directly.
+func AcrossAccounts(run func(cmd *cobra.Command, args []string) error) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		if !MultiAccount() || current != nil {
+			return run(cmd, args)
+		}
+
+		reporter := rprtr.CreateReporterOrExit()
+		logging := logging.CreateLoggerOrExit(reporter)
+
+		if arguments.RoleArn != "" {
+			return reporter.Errorf("--role-arn can't be used with --all-accounts or --accounts, use --profile to select the management account")
+		}
+		if Planning() {
+			return reporter.Errorf("--plan-out can't be used with --all-accounts or --accounts as a plan only covers one account")
+		}
+
+		accounts, err := Accounts(reporter, Clients(cmd, logging))
+		if err != nil {
+			return err
+		}
+		if len(accounts) == 0 {
+			reporter.Infof("No accounts selected")
+			return nil
+		}
+
+		StartBatch()
+		var failed int
+		failures := map[*Account]error{}
+		for _, account := range accounts {
+			reporter.Infof("Account %s (%s)", account.ID, account.Name)
+			current = account
+			err := run(cmd, args)
+			current = nil
+			if err != nil {
+				failed++
+				failures[account] = err
+			}
+		}
+		err = FlushBatch(reporter)
+		if err != nil {
+			return err
+		}
+
+		var total int
+		reporter.Infof("Summary of %d accounts", len(accounts))
+		for _, account := range accounts {
+			if err, ok := failures[account]; ok {
+				reporter.Warnf("  %s (%s): failed, %s", account.ID, account.Name, err)
+				continue
+			}
+			total += account.Found
+			reporter.Infof("  %s (%s): %d resources", account.ID, account.Name, account.Found)
+		}
+		reporter.Infof("Found %d resources in %d accounts", total, len(accounts)-failed)
+
+		if failed > 0 {
+			return reporter.Errorf("Failed in %d of %d accounts", failed, len(accounts))
+		}
+		return nil
+	}
+}
+
+// Accounts returns the accounts selected by --all-accounts or --accounts,
+// without those given to --exclude-accounts. Clients connect to the
+// management account, whose role is assumed in the member accounts.
+func Accounts(reporter *rprtr.Object, clients aws.ClientFunc) ([]*Account, error) {
+	client, err := clients(arguments.Region)
+	if err != nil {
+		return nil, reporter.Errorf("Unable to build AWS client: %s", err)
+	}
+	identity, err := client.GetCallerIdentity(&sts.GetCallerIdentityInput{})
+	if err != nil {
+		return nil, reporter.Errorf("Unable to get caller identity: %s", err)
+	}
+
+	// Roles are in the partition of the caller, e.g. aws or aws-us-gov
+	partition := "aws"
+	if parts := strings.SplitN(*identity.Arn, ":", 3); len(parts) == 3 {
+		partition = parts[1]
+	}
+
+	excluded := map[string]bool{}
+	for _, id := range arguments.ExcludeAccounts {
+		if !accountIDPattern.MatchString(id) {
+			return nil, reporter.Errorf("Invalid account ID %q given to --exclude-accounts", id)
+		}
+		excluded[id] = true
+	}
+
+	var accounts []*Account
+	seen := map[string]bool{}
+	add := func(id, name string) {
+		if excluded[id] || seen[id] {
+			return
+		}
+		seen[id] = true
+		account := &Account{
+			ID:     id,
+			Name:   name,
+			Target: aws.Target{Profile: arguments.Profile},
+		}
+		// The management account is reached without assuming a role
+		if id != *identity.Account {
+			account.Target.RoleArn = fmt.Sprintf("arn:%s:iam::%s:role/%s", partition, id, arguments.AccountRole)
+		}
+		accounts = append(accounts, account)
+	}
+
+	if arguments.AllAccounts {
+		err = client.ListAccountsPages(&organizations.ListAccountsInput{},
+			func(output *organizations.ListAccountsOutput, lastPage bool) bool {
+				for _, a := range output.Accounts {
+					if a.Status != nil && *a.Status != organizations.AccountStatusActive {
+						reporter.Infof("Skipping %s account %s (%s)", strings.ToLower(*a.Status), *a.Id, *a.Name)
+						continue
+					}
+					add(*a.Id, *a.Name)
+				}
+				return true
+			})
+		if err != nil {
+			return nil, reporter.Errorf("Unable to list the accounts of the organization: %s", err)
+		}
+	}
+	for _, id := range arguments.Accounts {
+		if !accountIDPattern.MatchString(id) {
+			return nil, reporter.Errorf("Invalid account ID %q given to --accounts", id)
+		}
+		add(id, id)
+	}
+	return accounts, nil
+}
@@ -0,0 +1,72 @@
+/*
+Copyright © 2022 James Harrington <james@harrington.net.au>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package resources
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jharrington22/aws-resource/pkg/arguments"
+	"github.com/jharrington22/aws-resource/pkg/audit"
+	rprtr "github.com/jharrington22/aws-resource/pkg/reporter"
+	"github.com/jharrington22/aws-resource/pkg/resource"
+)
+
+// AuditPath returns the path of the audit log given by --audit-log, or of
+// the one in the home directory.
+func AuditPath() (string, error) {
+	if arguments.AuditLog != "" {
+		return arguments.AuditLog, nil
+	}
+	return audit.DefaultPath()
+}
+
+// NewAudit makes every provider record its attempts to delete a resource to
+// the audit log. When the log can't be found deletes are refused while
+// other commands still run.
+func NewAudit(reporter *rprtr.Object) error {
+	path, err := AuditPath()
+	if err != nil {
+		resource.SetRecorder(unavailable{fmt.Errorf("no audit log, use --audit-log: %s", err)})
+		return nil
+	}
+	resource.SetRecorder(audit.NewLog(path))
+	return nil
+}
+
+// unavailable fails to record any deletion.
+type unavailable struct {
+	err error
+}
+
+func (u unavailable) Check() error {
+	return u.err
+}
+
+func (u unavailable) Record(d resource.Deletion) error {
+	return u.err
+}
+
+// Unrecorded reports a deletion that succeeded but couldn't be recorded in
+// the audit log as a warning, and returns err for any other error.
+func Unrecorded(reporter *rprtr.Object, err error) error {
+	var unrecorded *resource.UnrecordedError
+	if !errors.As(err, &unrecorded) {
+		return err
+	}
+	reporter.Warnf("%s", unrecorded)
+	return nil
+}
@@ -0,0 +1,401 @@
+/*
+Copyright © 2022 James Harrington <james@harrington.net.au>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package resources
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jharrington22/aws-resource/pkg/arguments"
+	"github.com/jharrington22/aws-resource/pkg/aws"
+	logging "github.com/jharrington22/aws-resource/pkg/logging"
+	rprtr "github.com/jharrington22/aws-resource/pkg/reporter"
+	"github.com/jharrington22/aws-resource/pkg/resource"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// ListOptions customises how List reports the resources it finds.
+type ListOptions struct {
+	// Detail returns an extra line printed for each resource, an empty
+	// string prints nothing.
+	Detail func(r *resource.Resource) string
+
+	// Summary is called once the resources found in a region are reported.
+	Summary func(result resource.RegionResult)
+}
+
+// Target returns the target selected by the global flags, or the target of
+// the account the command is running in when running in several accounts.
+func Target() aws.Target {
+	if current != nil {
+		return current.Target
+	}
+	return aws.Target{
+		Profile:    arguments.Profile,
+		RoleArn:    arguments.RoleArn,
+		ExternalID: arguments.ExternalID,
+	}
+}
+
+// Factory returns the client factory carried by the context the root
+// command was executed with, falling back to one building real AWS clients.
+// The root context is used as cobra only sets the context of sub commands
+// the first time they're executed.
+func Factory(cmd *cobra.Command, logger *logrus.Logger) aws.Factory {
+	if factory := aws.FactoryFromContext(cmd.Root().Context()); factory != nil {
+		return factory
+	}
+	return aws.NewFactory(logger)
+}
+
+// Clients returns a function building clients for the target selected by
+// the global flags, using the factory of the command.
+func Clients(cmd *cobra.Command, logger *logrus.Logger) aws.ClientFunc {
+	return aws.Clients(Factory(cmd, logger), Target())
+}
+
+// HasCommand reports whether parent already has a sub command called name.
+func HasCommand(parent *cobra.Command, name string) bool {
+	for _, c := range parent.Commands() {
+		if c.Name() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// NewListCmd creates a list command for a registered resource type.
+func NewListCmd(typ string) *cobra.Command {
+	p, _ := resource.New(typ, nil)
+	cmd := &cobra.Command{
+		Use:   typ,
+		Short: fmt.Sprintf("List %s", p.Describe()),
+		Long: fmt.Sprintf(`List %s for all or a specific region
+
+aws-resource list %s`, p.Describe(), typ),
+		RunE: AcrossAccounts(func(cmd *cobra.Command, args []string) error {
+			reporter := rprtr.CreateReporterOrExit()
+			logging := logging.CreateLoggerOrExit(reporter)
+
+			clients := Clients(cmd, logging)
+			p, err := resource.New(typ, clients)
+			if err != nil {
+				return reporter.Errorf("%s", err)
+			}
+			_, err = List(cmd.Context(), reporter, clients, p, ListOptions{})
+			return err
+		}),
+	}
+	arguments.AddFlags(cmd.Flags())
+	arguments.AddOutputFlag(cmd.Flags())
+	arguments.AddFilterFlags(cmd.Flags())
+	arguments.AddCostFlags(cmd.Flags())
+	arguments.AddAccountFlags(cmd.Flags())
+	return cmd
+}
+
+// NewDeleteCmd creates a delete command for a registered resource type that
+// deletes every resource of that type in all regions.
+func NewDeleteCmd(typ string) *cobra.Command {
+	var dryRun, allRegions bool
+
+	p, _ := resource.New(typ, nil)
+	cmd := &cobra.Command{
+		Use:   typ,
+		Short: fmt.Sprintf("Delete %s", p.Describe()),
+		Long: fmt.Sprintf(`Delete %s for all or a specific region
+
+aws-resource delete %s
+aws-resource delete %s --all-regions`, p.Describe(), typ, typ),
+		RunE: AcrossAccounts(func(cmd *cobra.Command, args []string) error {
+			reporter := rprtr.CreateReporterOrExit()
+			logging := logging.CreateLoggerOrExit(reporter)
+
+			clients := Clients(cmd, logging)
+			p, err := resource.New(typ, clients)
+			if err != nil {
+				return reporter.Errorf("%s", err)
+			}
+
+			protection, err := NewProtection(reporter)
+			if err != nil {
+				return err
+			}
+
+			var results []resource.RegionResult
+			if allRegions {
+				reporter.Infof("Deleting %s in all regions", p.Describe())
+				results, err = Find(cmd.Context(), reporter, clients, p)
+			} else {
+				reporter.Infof("Deleting %s in %s", p.Describe(), arguments.Region)
+				results, err = FindIn(cmd.Context(), reporter, p, []string{arguments.Region})
+			}
+			if err != nil {
+				return err
+			}
+			results = protection.FilterResults(reporter, results)
+			if Planning() {
+				return SavePlan(reporter, clients, Flatten(results))
+			}
+			if !dryRun {
+				err = Confirm(reporter, clients, Flatten(results))
+				if err != nil {
+					return err
+				}
+			}
+			return Delete(cmd.Context(), reporter, clients, p, results, dryRun)
+		}),
+	}
+	arguments.AddFlags(cmd.Flags())
+	arguments.AddFilterFlags(cmd.Flags())
+	arguments.AddAccountFlags(cmd.Flags())
+	arguments.AddProtectFlags(cmd.Flags())
+	arguments.AddStackFlag(cmd.Flags())
+	arguments.AddPlanFlag(cmd.Flags())
+	arguments.AddConfirmFlag(cmd.Flags())
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Validate if delete would be successful")
+	cmd.Flags().BoolVar(&allRegions, "all-regions", false, fmt.Sprintf("Delete %s in all regions", p.Describe()))
+	return cmd
+}
+
+// Find returns the resources of the provider matching the filter flags in
+// every region enabled in the account without reporting them. Regions that
+// fail are reported while the results of the other regions are still
+// returned, along with the error.
+func Find(ctx context.Context, reporter *rprtr.Object, clients aws.ClientFunc, p resource.Provider) ([]resource.RegionResult, error) {
+	if _, err := NewFilter(reporter); err != nil {
+		return nil, err
+	}
+	regions, err := Regions(reporter, clients)
+	if err != nil {
+		return nil, err
+	}
+	return FindIn(ctx, reporter, p, regions)
+}
+
+// Regions returns the regions given by --regions, or every region enabled in
+// the account.
+func Regions(reporter *rprtr.Object, clients aws.ClientFunc) ([]string, error) {
+	if len(arguments.Regions) > 0 {
+		regions := append([]string{}, arguments.Regions...)
+		sort.Strings(regions)
+		return regions, nil
+	}
+	regions, err := resource.Regions(clients, arguments.Region)
+	if err != nil {
+		return nil, reporter.Errorf("%s", err)
+	}
+	return regions, nil
+}
+
+// FindIn returns the resources of the provider matching the filter flags in
+// the given regions, reporting any region that fails.
+func FindIn(ctx context.Context, reporter *rprtr.Object, p resource.Provider, regions []string) ([]resource.RegionResult, error) {
+	filter, err := NewFilter(reporter)
+	if err != nil {
+		return nil, err
+	}
+	results, err := Scan(ctx, reporter, p, regions)
+	results = filter.Apply(results)
+	if current != nil {
+		for _, result := range results {
+			current.Found += len(result.Resources)
+		}
+	}
+	return results, err
+}
+
+// Scan returns every resource of the provider in the given regions, ignoring
+// the filter flags, reporting any region that fails. Resources are stamped
+// with the account the command is running in when running in several
+// accounts.
+func Scan(ctx context.Context, reporter *rprtr.Object, p resource.Provider, regions []string) ([]resource.RegionResult, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	results, err := resource.Scan(ctx, p, regions, arguments.Concurrency)
+	if current != nil {
+		for _, result := range results {
+			for _, r := range result.Resources {
+				r.Account = current.ID
+			}
+		}
+	}
+	if scanErr, ok := err.(*resource.ScanError); ok {
+		for _, regionErr := range scanErr.Errors {
+			_ = reporter.Errorf("Unable to list %s in %s: %s", p.Describe(), regionErr.Region, regionErr.Err)
+		}
+	} else if err != nil {
+		return results, reporter.Errorf("Unable to list %s: %s", p.Describe(), err)
+	}
+	return results, err
+}
+
+// List reports the resources of the provider found in every region enabled
+// in the account.
+func List(ctx context.Context, reporter *rprtr.Object, clients aws.ClientFunc, p resource.Provider, opts ListOptions) ([]resource.RegionResult, error) {
+	if _, err := OutputFormat(reporter); err != nil {
+		return nil, err
+	}
+	prices, err := Prices(reporter)
+	if err != nil {
+		return nil, err
+	}
+
+	reporter.Infof("Listing %s", p.Describe())
+
+	// Regions that failed have already been reported, list what was found
+	// in the others before returning the error
+	results, err := Find(ctx, reporter, clients, p)
+	if results == nil && err != nil {
+		return results, err
+	}
+
+	var found bool
+	for _, result := range results {
+		if len(result.Resources) == 0 {
+			continue
+		}
+		found = true
+		if p.Global() {
+			reporter.Infof("Found %d %s", len(result.Resources), p.Describe())
+		} else {
+			reporter.Infof("Found %d %s in %s", len(result.Resources), p.Describe(), result.Region)
+		}
+		if prices != nil {
+			what := p.Describe()
+			if !p.Global() {
+				what = fmt.Sprintf("%s in %s", p.Describe(), result.Region)
+			}
+			ReportCost(reporter, prices, what, result.Resources)
+		}
+		reportStacks(reporter, result.Resources)
+		if opts.Detail != nil {
+			for _, r := range result.Resources {
+				if detail := opts.Detail(r); detail != "" {
+					if stack := resource.Stack(r); stack != "" {
+						detail += fmt.Sprintf(" (CloudFormation stack %s)", stack)
+					}
+					reporter.Infof("%s", detail)
+				}
+			}
+		}
+		if opts.Summary != nil {
+			opts.Summary(result)
+		}
+	}
+	if !found && err == nil {
+		reporter.Infof("No %s found", p.Describe())
+	}
+
+	if emitErr := emit(reporter, results); emitErr != nil {
+		return results, emitErr
+	}
+	return results, err
+}
+
+// reportStacks reports how many of the resources belong to CloudFormation
+// stacks, which only deleting the stack deletes without drift.
+func reportStacks(reporter *rprtr.Object, resources []*resource.Resource) {
+	var stacks []string
+	owned := 0
+	seen := map[string]bool{}
+	for _, r := range resources {
+		stack := resource.Stack(r)
+		if stack == "" {
+			continue
+		}
+		owned++
+		if !seen[stack] {
+			seen[stack] = true
+			stacks = append(stacks, stack)
+		}
+	}
+	if owned > 0 {
+		reporter.Infof("%d of them belong to CloudFormation stacks %s", owned, strings.Join(stacks, ", "))
+	}
+}
+
+// Delete deletes every resource in results, reporting each deletion and
+// skipping the resources the provider refuses to delete as they're
+// protected. Resources of another type, such as the CloudFormation stacks
+// deleted instead of the resources they own, are deleted by their own
+// provider.
+func Delete(ctx context.Context, reporter *rprtr.Object, clients aws.ClientFunc, p resource.Provider, results []resource.RegionResult, dryRun bool) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if !dryRun {
+		reporter.Warnf("Dry run %t will delete resources", dryRun)
+	}
+
+	providers := map[string]resource.Provider{p.Type(): p}
+	var found bool
+	for _, result := range results {
+		if len(result.Resources) == 0 {
+			continue
+		}
+		found = true
+		var types []string
+		counts := map[string]int{}
+		for _, r := range result.Resources {
+			if _, ok := providers[r.Type]; !ok {
+				provider, err := resource.New(r.Type, clients)
+				if err != nil {
+					return reporter.Errorf("%s", err)
+				}
+				providers[r.Type] = provider
+			}
+			if counts[r.Type] == 0 {
+				types = append(types, r.Type)
+			}
+			counts[r.Type]++
+		}
+		for _, typ := range types {
+			reporter.Infof("Deleting %d %s in %s", counts[typ], providers[typ].Describe(), result.Region)
+		}
+
+		for _, r := range result.Resources {
+			provider := providers[r.Type]
+			id := r.ID
+			if r.Type != p.Type() {
+				id = fmt.Sprintf("%s %s", r.Type, r.ID)
+			}
+			err := provider.Delete(ctx, r, dryRun)
+			if Skipped(reporter, err) {
+				continue
+			}
+			err = Unrecorded(reporter, err)
+			if err != nil {
+				return reporter.Errorf("Unable to delete %s: %s", id, err)
+			}
+			if dryRun {
+				reporter.Infof("Deletion of %s would have succeeded", id)
+			} else {
+				reporter.Infof("Deleted %s", id)
+			}
+		}
+	}
+	if !found {
+		reporter.Infof("No %s found", p.Describe())
+	}
+	return nil
+}
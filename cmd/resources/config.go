@@ -0,0 +1,81 @@
+/*
+Copyright © 2022 James Harrington <james@harrington.net.au>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package resources
+
+import (
+	"os"
+
+	"github.com/jharrington22/aws-resource/pkg/arguments"
+	"github.com/jharrington22/aws-resource/pkg/config"
+	rprtr "github.com/jharrington22/aws-resource/pkg/reporter"
+	"github.com/spf13/cobra"
+)
+
+// Configure applies the settings of the target selected by --target, or the
+// default target of the configuration file, to the flags of the command
+// that weren't given on the command line. AWS_RESOURCE_* environment
+// variables override both. The file given by --config must exist while
+// $HOME/.aws-resource.yaml is optional. The protection of resources and the
+// audit log are then set up, for every command so that no delete can skip
+// them.
+func Configure(cmd *cobra.Command) error {
+	reporter := rprtr.CreateReporterOrExit()
+
+	// The file and target are looked up before anything else, so their own
+	// environment variables are applied first
+	path := arguments.ConfigFile
+	if value, ok := os.LookupEnv(config.EnvName("config")); ok {
+		path = value
+	}
+	name := arguments.TargetName
+	if value, ok := os.LookupEnv(config.EnvName("target")); ok {
+		name = value
+	}
+
+	optional := path == ""
+	if optional {
+		var err error
+		path, err = config.DefaultPath()
+		if err != nil {
+			// Without a home directory there's no default file
+			path = ""
+		}
+	}
+
+	file := &config.File{}
+	if path != "" {
+		var err error
+		file, err = config.Load(path, optional)
+		if err != nil {
+			return reporter.Errorf("%s", err)
+		}
+	}
+	target, err := file.Lookup(name)
+	if err != nil {
+		return reporter.Errorf("%s", err)
+	}
+
+	err = config.Apply(cmd.Flags(), target, os.LookupEnv)
+	if err != nil {
+		return reporter.Errorf("%s", err)
+	}
+
+	_, err = NewProtection(reporter)
+	if err != nil {
+		return err
+	}
+	return NewAudit(reporter)
+}
@@ -0,0 +1,81 @@
+/*
+Copyright © 2022 James Harrington <james@harrington.net.au>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package resources
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jharrington22/aws-resource/pkg/arguments"
+	"github.com/jharrington22/aws-resource/pkg/aws"
+	rprtr "github.com/jharrington22/aws-resource/pkg/reporter"
+	"github.com/jharrington22/aws-resource/pkg/resource"
+)
+
+// Confirm summarises the resources that are about to be deleted in each
+// region and asks the operator to type the account ID before going ahead.
+// It returns nil straight away with --yes, and refuses to delete when the
+// standard input isn't a terminal.
+func Confirm(reporter *rprtr.Object, clients aws.ClientFunc, resources []*resource.Resource) error {
+	if len(resources) == 0 {
+		return nil
+	}
+
+	accountID, err := AccountID(reporter, clients)
+	if err != nil {
+		return err
+	}
+
+	var regions []string
+	byRegion := map[string][]*resource.Resource{}
+	for _, r := range resources {
+		if _, ok := byRegion[r.Region]; !ok {
+			regions = append(regions, r.Region)
+		}
+		byRegion[r.Region] = append(byRegion[r.Region], r)
+	}
+
+	reporter.Warnf("The following %d resources will be deleted from account %s", len(resources), accountID)
+	for _, region := range regions {
+		reporter.Warnf("%s:", region)
+		for _, r := range byRegion[region] {
+			if r.Name != "" && r.Name != r.ID {
+				reporter.Warnf("  %s %s (%s)", r.Type, r.ID, r.Name)
+			} else {
+				reporter.Warnf("  %s %s", r.Type, r.ID)
+			}
+		}
+	}
+
+	if arguments.Yes {
+		return nil
+	}
+	if !reporter.IsInputTerminal() {
+		return reporter.Errorf("Refusing to delete without confirmation, use --yes when not running interactively")
+	}
+
+	fmt.Fprintf(os.Stderr, "Type the account ID %s to confirm: ", accountID)
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return reporter.Errorf("Unable to read confirmation: %s", err)
+	}
+	if strings.TrimSpace(answer) != accountID {
+		return reporter.Errorf("Confirmation doesn't match account ID %s, nothing was deleted", accountID)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+/*
+Copyright © 2022 James Harrington <james@harrington.net.au>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package resources
+
+import (
+	"github.com/jharrington22/aws-resource/pkg/arguments"
+	"github.com/jharrington22/aws-resource/pkg/pricing"
+	rprtr "github.com/jharrington22/aws-resource/pkg/reporter"
+	"github.com/jharrington22/aws-resource/pkg/resource"
+)
+
+// Prices returns the price table used to estimate costs, the one given by
+// --price-table or the built in one. It returns nil when costs aren't
+// requested with --cost.
+func Prices(reporter *rprtr.Object) (*pricing.Table, error) {
+	if !arguments.Cost && arguments.PriceTable == "" {
+		return nil, nil
+	}
+	if arguments.PriceTable == "" {
+		return pricing.Default(), nil
+	}
+	prices, err := pricing.Load(arguments.PriceTable)
+	if err != nil {
+		return nil, reporter.Errorf("%s", err)
+	}
+	return prices, nil
+}
+
+// ReportCost reports the estimated cost of the resources, described by what,
+// warning about the resources without a known price.
+func ReportCost(reporter *rprtr.Object, prices *pricing.Table, what string, resources []*resource.Resource) {
+	var total pricing.Cost
+	var unknown int
+	for _, r := range resources {
+		cost, ok := prices.Estimate(r)
+		if !ok {
+			unknown++
+			continue
+		}
+		total = total.Add(cost)
+	}
+
+	reporter.Infof("Estimated cost of %s: %.4f %s/hour, %.2f %s/month",
+		what, total.Hourly, prices.Currency, total.Monthly(), prices.Currency)
+	if unknown > 0 {
+		reporter.Warnf("%d resources have no known price and aren't included in the estimate", unknown)
+	}
+}
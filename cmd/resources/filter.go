@@ -0,0 +1,108 @@
+/*
+Copyright © 2022 James Harrington <james@harrington.net.au>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package resources
+
+import (
+	"time"
+
+	"github.com/jharrington22/aws-resource/pkg/arguments"
+	"github.com/jharrington22/aws-resource/pkg/duration"
+	rprtr "github.com/jharrington22/aws-resource/pkg/reporter"
+	"github.com/jharrington22/aws-resource/pkg/resource"
+	"github.com/jharrington22/aws-resource/pkg/selector"
+)
+
+// Filter selects resources by their tags and age.
+type Filter struct {
+	Selector selector.Selector
+
+	// OlderThan and NewerThan select resources created before or after
+	// the given time, a zero time doesn't filter.
+	OlderThan time.Time
+	NewerThan time.Time
+}
+
+// NewFilter validates the --selector, --older-than and --newer-than flags
+// and returns the filter they describe, with ages relative to now.
+func NewFilter(reporter *rprtr.Object) (*Filter, error) {
+	sel, err := selector.Parse(arguments.Selector)
+	if err != nil {
+		return nil, reporter.Errorf("%s", err)
+	}
+	filter := &Filter{Selector: sel}
+
+	now := time.Now()
+	if arguments.OlderThan != "" {
+		age, err := duration.Parse(arguments.OlderThan)
+		if err != nil {
+			return nil, reporter.Errorf("Invalid --older-than: %s", err)
+		}
+		filter.OlderThan = now.Add(-age)
+	}
+	if arguments.NewerThan != "" {
+		age, err := duration.Parse(arguments.NewerThan)
+		if err != nil {
+			return nil, reporter.Errorf("Invalid --newer-than: %s", err)
+		}
+		filter.NewerThan = now.Add(-age)
+	}
+	if !filter.OlderThan.IsZero() && !filter.NewerThan.IsZero() && !filter.NewerThan.Before(filter.OlderThan) {
+		return nil, reporter.Errorf("--newer-than %s must be longer than --older-than %s, no resource can match both",
+			arguments.NewerThan, arguments.OlderThan)
+	}
+	return filter, nil
+}
+
+// Empty reports whether the filter matches every resource.
+func (f *Filter) Empty() bool {
+	return f.Selector.Empty() && f.OlderThan.IsZero() && f.NewerThan.IsZero()
+}
+
+// Matches reports whether the resource is selected by the filter. When an
+// age is given resources without a creation time, such as hosted zones,
+// never match.
+func (f *Filter) Matches(r *resource.Resource) bool {
+	if !f.Selector.Matches(r.Tags) {
+		return false
+	}
+	if !f.OlderThan.IsZero() && (r.CreatedAt.IsZero() || !r.CreatedAt.Before(f.OlderThan)) {
+		return false
+	}
+	if !f.NewerThan.IsZero() && (r.CreatedAt.IsZero() || !r.CreatedAt.After(f.NewerThan)) {
+		return false
+	}
+	return true
+}
+
+// Apply returns the results keeping only the resources matching the filter.
+func (f *Filter) Apply(results []resource.RegionResult) []resource.RegionResult {
+	if f.Empty() {
+		return results
+	}
+
+	selected := make([]resource.RegionResult, 0, len(results))
+	for _, result := range results {
+		var resources []*resource.Resource
+		for _, r := range result.Resources {
+			if f.Matches(r) {
+				resources = append(resources, r)
+			}
+		}
+		result.Resources = resources
+		selected = append(selected, result)
+	}
+	return selected
+}
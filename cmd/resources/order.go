@@ -0,0 +1,102 @@
+/*
+Copyright © 2022 James Harrington <james@harrington.net.au>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package resources
+
+import (
+	"context"
+
+	"github.com/jharrington22/aws-resource/pkg/aws"
+	"github.com/jharrington22/aws-resource/pkg/graph"
+	rprtr "github.com/jharrington22/aws-resource/pkg/reporter"
+	"github.com/jharrington22/aws-resource/pkg/resource"
+)
+
+// Order returns the resources in the order they must be deleted, resources
+// being deleted before the resources they use.
+func Order(reporter *rprtr.Object, resources []*resource.Resource) ([]*resource.Resource, error) {
+	ordered, err := graph.New(resources).Order()
+	if err != nil {
+		return nil, reporter.Errorf("Unable to order deletions: %s", err)
+	}
+	return ordered, nil
+}
+
+// DeleteInOrder deletes resources of any type in the order given by their
+// relations. Resources used by a resource that wasn't deleted, because it's
+// protected, are skipped. A dry run only validates the deletion of resources
+// that aren't used by another one, as the others would still be in use.
+func DeleteInOrder(ctx context.Context, reporter *rprtr.Object, clients aws.ClientFunc, resources []*resource.Resource, dryRun bool) error {
+	g := graph.New(resources)
+	ordered, err := g.Order()
+	if err != nil {
+		return reporter.Errorf("Unable to order deletions: %s", err)
+	}
+
+	providers := map[string]resource.Provider{}
+	deleted := map[string]bool{}
+	for _, r := range ordered {
+		users := g.Before(r)
+		var kept *resource.Resource
+		for _, user := range users {
+			if !deleted[graph.Key(user)] {
+				kept = user
+				break
+			}
+		}
+		if kept != nil {
+			reporter.Infof("Skipping %s %s in %s, used by %s %s which wasn't deleted", r.Type, r.ID, r.Region, kept.Type, kept.ID)
+			continue
+		}
+		if dryRun && len(users) > 0 {
+			reporter.Infof("Deletion of %s %s in %s would follow the deletion of %s %s", r.Type, r.ID, r.Region, users[0].Type, users[0].ID)
+			deleted[graph.Key(r)] = true
+			continue
+		}
+
+		provider, ok := providers[r.Type]
+		if !ok {
+			provider, err = resource.New(r.Type, clients)
+			if err != nil {
+				return reporter.Errorf("%s", err)
+			}
+			providers[r.Type] = provider
+		}
+
+		err = provider.Delete(ctx, r, dryRun)
+		if Skipped(reporter, err) {
+			continue
+		}
+		err = Unrecorded(reporter, err)
+		if err != nil {
+			return reporter.Errorf("Unable to delete %s %s in %s: %s", r.Type, r.ID, r.Region, err)
+		}
+		deleted[graph.Key(r)] = true
+		if dryRun {
+			reporter.Infof("Deletion of %s %s in %s would have succeeded", r.Type, r.ID, r.Region)
+			continue
+		}
+		reporter.Infof("Deleted %s %s in %s", r.Type, r.ID, r.Region)
+
+		// The resources it used are only released once the deletion
+		// completes, which the waiters bound
+		if len(g.Uses(r)) > 0 {
+			if err := resource.WaitDeleted(ctx, provider, r); err != nil {
+				return reporter.Errorf("Unable to wait for %s %s in %s to be deleted: %s", r.Type, r.ID, r.Region, err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+/*
+Copyright © 2022 James Harrington <james@harrington.net.au>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package resources
+
+import (
+	"os"
+
+	"github.com/jharrington22/aws-resource/pkg/arguments"
+	"github.com/jharrington22/aws-resource/pkg/output"
+	rprtr "github.com/jharrington22/aws-resource/pkg/reporter"
+	"github.com/jharrington22/aws-resource/pkg/resource"
+)
+
+// batch collects the resources listed while a batch is open so they can be
+// written as a single record set. Batches can be nested, as when list all
+// runs in several accounts, in which case the outermost one is written.
+var (
+	batch      *[]*resource.Resource
+	batchDepth int
+)
+
+// OutputFormat validates the --output flag.
+func OutputFormat(reporter *rprtr.Object) (output.Format, error) {
+	format, err := output.ParseFormat(arguments.Output)
+	if err != nil {
+		return format, reporter.Errorf("%s", err)
+	}
+	return format, nil
+}
+
+// StartBatch collects the resources of every following List call until
+// FlushBatch is called, used by list all to write a single record set.
+func StartBatch() {
+	batchDepth++
+	if batch == nil {
+		batch = &[]*resource.Resource{}
+	}
+}
+
+// FlushBatch writes the resources collected since StartBatch, once the
+// outermost batch is flushed.
+func FlushBatch(reporter *rprtr.Object) error {
+	if batch == nil {
+		return nil
+	}
+	batchDepth--
+	if batchDepth > 0 {
+		return nil
+	}
+	resources := *batch
+	batch = nil
+
+	prices, err := Prices(reporter)
+	if err != nil {
+		return err
+	}
+	if prices != nil {
+		ReportCost(reporter, prices, "all resources", resources)
+	}
+	return write(reporter, resources)
+}
+
+// emit writes the listed resources to stdout in the requested format, or
+// adds them to the open batch.
+func emit(reporter *rprtr.Object, results []resource.RegionResult) error {
+	resources := Flatten(results)
+
+	if batch != nil {
+		*batch = append(*batch, resources...)
+		return nil
+	}
+	return write(reporter, resources)
+}
+
+func write(reporter *rprtr.Object, resources []*resource.Resource) error {
+	format, err := OutputFormat(reporter)
+	if err != nil || format == output.None {
+		return err
+	}
+	prices, err := Prices(reporter)
+	if err != nil {
+		return err
+	}
+
+	err = output.WriteResources(os.Stdout, format, resources, prices)
+	if err != nil {
+		return reporter.Errorf("Unable to write output: %s", err)
+	}
+	return nil
+}
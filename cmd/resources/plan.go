@@ -0,0 +1,75 @@
+/*
+Copyright © 2022 James Harrington <james@harrington.net.au>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package resources
+
+import (
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/jharrington22/aws-resource/pkg/arguments"
+	"github.com/jharrington22/aws-resource/pkg/aws"
+	"github.com/jharrington22/aws-resource/pkg/plan"
+	rprtr "github.com/jharrington22/aws-resource/pkg/reporter"
+	"github.com/jharrington22/aws-resource/pkg/resource"
+)
+
+// AccountID returns the ID of the account the clients are connected to.
+func AccountID(reporter *rprtr.Object, clients aws.ClientFunc) (string, error) {
+	client, err := clients(arguments.Region)
+	if err != nil {
+		return "", reporter.Errorf("Unable to build AWS client: %s", err)
+	}
+
+	identity, err := client.GetCallerIdentity(&sts.GetCallerIdentityInput{})
+	if err != nil {
+		return "", reporter.Errorf("Unable to get caller identity: %s", err)
+	}
+	return *identity.Account, nil
+}
+
+// Planning reports whether the delete command should save a plan instead of
+// deleting resources.
+func Planning() bool {
+	return arguments.PlanOut != ""
+}
+
+// SavePlan saves the deletion of the resources, in order, to the file given
+// by --plan-out.
+func SavePlan(reporter *rprtr.Object, clients aws.ClientFunc, resources []*resource.Resource) error {
+	accountID, err := AccountID(reporter, clients)
+	if err != nil {
+		return err
+	}
+
+	p := plan.New(accountID)
+	for _, r := range resources {
+		p.Delete(r)
+	}
+
+	err = p.Save(arguments.PlanOut)
+	if err != nil {
+		return reporter.Errorf("Unable to save plan: %s", err)
+	}
+	reporter.Infof("Saved plan deleting %d resources in account %s to %s", len(p.Actions), accountID, arguments.PlanOut)
+	return nil
+}
+
+// Flatten returns the resources of every region result.
+func Flatten(results []resource.RegionResult) []*resource.Resource {
+	var resources []*resource.Resource
+	for _, result := range results {
+		resources = append(resources, result.Resources...)
+	}
+	return resources
+}
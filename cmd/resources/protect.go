@@ -0,0 +1,205 @@
+/*
+Copyright © 2022 James Harrington <james@harrington.net.au>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package resources
+
+import (
+	"errors"
+
+	"github.com/jharrington22/aws-resource/pkg/arguments"
+	"github.com/jharrington22/aws-resource/pkg/graph"
+	"github.com/jharrington22/aws-resource/pkg/protect"
+	rprtr "github.com/jharrington22/aws-resource/pkg/reporter"
+	"github.com/jharrington22/aws-resource/pkg/resource"
+)
+
+// Protection matches the resources delete commands must never delete.
+type Protection struct {
+	*protect.Policy
+}
+
+// NewProtection returns the protection configured by the --protected-tags
+// and --deny-list flags, and makes every provider refuse to delete the
+// resources it protects. The deny-list given by --deny-list must exist
+// while $HOME/.aws-resource-deny-list is optional.
+func NewProtection(reporter *rprtr.Object) (Protection, error) {
+	policy, err := protect.New(arguments.ProtectedTags)
+	if err != nil {
+		return Protection{}, reporter.Errorf("%s", err)
+	}
+
+	path, optional := arguments.DenyList, arguments.DenyList == ""
+	if optional {
+		path, err = protect.DefaultDenyListPath()
+	}
+	if err == nil {
+		policy.Deny, err = protect.LoadDenyList(path, optional)
+		if err != nil {
+			return Protection{}, reporter.Errorf("%s", err)
+		}
+	}
+
+	protection := Protection{policy}
+	resource.SetGuard(protection)
+	return protection, nil
+}
+
+// Protected returns why the resource is protected, if it is. Resources that
+// belong to a CloudFormation stack are protected, as deleting them would
+// make the stack drift, and only ever deleted by deleting their stack.
+func (p Protection) Protected(r *resource.Resource) (string, bool) {
+	if reason, ok := p.Policy.Protected(r); ok {
+		return reason, true
+	}
+	if stack := resource.Stack(r); stack != "" {
+		return "CloudFormation stack " + stack, true
+	}
+	return "", false
+}
+
+// Match returns why the resource is protected, if it is.
+func (p Protection) Match(r *resource.Resource) (string, bool) {
+	return p.Protected(r)
+}
+
+// Filter returns the resources that aren't protected, reporting the ones
+// skipped. With --delete-stack the resources that belong to a
+// CloudFormation stack are replaced by their stack, unless the stack owns
+// a resource protected otherwise.
+func (p Protection) Filter(reporter *rprtr.Object, resources []*resource.Resource) []*resource.Resource {
+	unprotected, owned := p.filter(reporter, resources)
+	suggestStacks(reporter, owned)
+	return unprotected
+}
+
+// FilterResults returns the results without the protected resources,
+// reporting the ones skipped.
+func (p Protection) FilterResults(reporter *rprtr.Object, results []resource.RegionResult) []resource.RegionResult {
+	filtered := make([]resource.RegionResult, 0, len(results))
+	skipped := 0
+	for _, result := range results {
+		var owned int
+		result.Resources, owned = p.filter(reporter, result.Resources)
+		skipped += owned
+		filtered = append(filtered, result)
+	}
+	suggestStacks(reporter, skipped)
+	return filtered
+}
+
+// filter returns the resources that aren't protected along with the stacks
+// replacing the resources that belong to one, and the number of resources
+// skipped as they belong to a stack.
+func (p Protection) filter(reporter *rprtr.Object, resources []*resource.Resource) ([]*resource.Resource, int) {
+	var unprotected, stacks []*resource.Resource
+	owned := map[string][]*resource.Resource{}
+	blocked := map[string]*resource.Resource{}
+	skipped := 0
+	for _, r := range resources {
+		stack := resource.StackOf(r)
+		if reason, ok := p.Policy.Protected(r); ok {
+			reporter.Infof("Skipping %s %s in %s, protected by %s", r.Type, r.ID, r.Region, reason)
+			if stack != nil {
+				blocked[graph.Key(stack)] = r
+			}
+			continue
+		}
+		if stack == nil {
+			unprotected = append(unprotected, r)
+			continue
+		}
+		if !arguments.DeleteStack {
+			reporter.Infof("Skipping %s %s in %s, protected by CloudFormation stack %s", r.Type, r.ID, r.Region, stack.Name)
+			skipped++
+			continue
+		}
+		key := graph.Key(stack)
+		if _, ok := owned[key]; !ok {
+			stacks = append(stacks, stack)
+		}
+		owned[key] = append(owned[key], r)
+	}
+
+	for _, stack := range stacks {
+		key := graph.Key(stack)
+		if r, ok := blocked[key]; ok {
+			reporter.Infof("Skipping CloudFormation stack %s in %s, it owns %s %s which is protected", stack.Name, stack.Region, r.Type, r.ID)
+			continue
+		}
+		if reason, ok := p.Protected(stack); ok {
+			reporter.Infof("Skipping CloudFormation stack %s in %s, protected by %s", stack.Name, stack.Region, reason)
+			continue
+		}
+		for _, r := range owned[key] {
+			reporter.Infof("Deleting CloudFormation stack %s in %s instead of %s %s", stack.Name, stack.Region, r.Type, r.ID)
+		}
+		unprotected = append(unprotected, stack)
+	}
+	return unprotected, skipped
+}
+
+// suggestStacks reports how to delete the resources skipped as they belong
+// to a CloudFormation stack.
+func suggestStacks(reporter *rprtr.Object, skipped int) {
+	if skipped > 0 {
+		reporter.Infof("Use --delete-stack to delete the CloudFormation stacks of the %d resources skipped instead", skipped)
+	}
+}
+
+// FilterUsed returns the resources that aren't protected, nor used by a
+// protected resource, reporting the ones skipped.
+func (p Protection) FilterUsed(reporter *rprtr.Object, resources []*resource.Resource) ([]*resource.Resource, error) {
+	g := graph.New(resources)
+	ordered, err := g.Order()
+	if err != nil {
+		return nil, reporter.Errorf("Unable to order deletions: %s", err)
+	}
+
+	skipped := map[string]bool{}
+	for _, r := range ordered {
+		if reason, ok := p.Match(r); ok {
+			reporter.Infof("Skipping %s %s in %s, protected by %s", r.Type, r.ID, r.Region, reason)
+			skipped[graph.Key(r)] = true
+			continue
+		}
+		for _, user := range g.Before(r) {
+			if skipped[graph.Key(user)] {
+				reporter.Infof("Skipping %s %s in %s, used by %s %s which is skipped", r.Type, r.ID, r.Region, user.Type, user.ID)
+				skipped[graph.Key(r)] = true
+				break
+			}
+		}
+	}
+
+	var kept []*resource.Resource
+	for _, r := range resources {
+		if !skipped[graph.Key(r)] {
+			kept = append(kept, r)
+		}
+	}
+	return kept, nil
+}
+
+// Skipped reports whether err was returned by a provider refusing to delete
+// a protected resource, in which case the resource is reported as skipped.
+func Skipped(reporter *rprtr.Object, err error) bool {
+	var protected *resource.ProtectedError
+	if !errors.As(err, &protected) {
+		return false
+	}
+	r := protected.Resource
+	reporter.Infof("Skipping %s %s in %s, protected by %s", r.Type, r.ID, r.Region, protected.Reason)
+	return true
+}
@@ -16,12 +16,20 @@ limitations under the License.
 package cmd
 
 import (
+	"context"
 	"os"
 
+	"github.com/jharrington22/aws-resource/cmd/apply"
+	"github.com/jharrington22/aws-resource/cmd/audit"
 	"github.com/jharrington22/aws-resource/cmd/del"
+	"github.com/jharrington22/aws-resource/cmd/inventory"
 	"github.com/jharrington22/aws-resource/cmd/list"
+	"github.com/jharrington22/aws-resource/cmd/resources"
 	"github.com/jharrington22/aws-resource/cmd/whoami"
 	"github.com/jharrington22/aws-resource/pkg/arguments"
+	"github.com/jharrington22/aws-resource/pkg/aws"
+	logging "github.com/jharrington22/aws-resource/pkg/logging"
+	rprtr "github.com/jharrington22/aws-resource/pkg/reporter"
 	"github.com/spf13/cobra"
 )
 
@@ -31,6 +39,12 @@ var RootCmd = &cobra.Command{
 	Short: "A tool to find resources in an AWS account",
 	Long:  `This tool should list resources in AWS accounts that have a per hour cost.`,
 
+	// Settings of the configuration file and environment are applied to
+	// the flags of every command before it runs
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return resources.Configure(cmd)
+	},
+
 	// Uncomment the following line if your bare application
 	// has an action associated with it:
 	// Run: func(cmd *cobra.Command, args []string) { },
@@ -39,14 +53,23 @@ var RootCmd = &cobra.Command{
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
-	err := RootCmd.Execute()
+	reporter := rprtr.CreateReporterOrExit()
+	logger := logging.CreateLoggerOrExit(reporter)
+
+	// Every command shares the same factory so that list all reuses the
+	// sessions of each target
+	ctx := aws.WithFactory(context.Background(), aws.NewFactory(logger))
+	err := RootCmd.ExecuteContext(ctx)
 	if err != nil {
 		os.Exit(1)
 	}
 }
 
 func init() {
+	RootCmd.AddCommand(apply.Cmd)
+	RootCmd.AddCommand(audit.AuditCmd)
 	RootCmd.AddCommand(del.DelCmd)
+	RootCmd.AddCommand(inventory.InventoryCmd)
 	RootCmd.AddCommand(list.ListCmd)
 	RootCmd.AddCommand(whoami.WhoAmICmd)
 	// Here you will define your flags and configuration settings.
@@ -54,8 +77,8 @@ func init() {
 	// will be global for your application.
 	flags := RootCmd.PersistentFlags()
 	arguments.AddFlags(flags)
-
-	// rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.aws-resource.yaml)")
+	arguments.AddConfigFlags(flags)
+	arguments.AddAuditFlag(flags)
 
 	// Cobra also supports local flags, which will only run
 	// when this action is called directly.
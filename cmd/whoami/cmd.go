@@ -16,11 +16,9 @@ limitations under the License.
 package whoami
 
 import (
-	"os"
-
 	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/jharrington22/aws-resource/cmd/resources"
 	"github.com/jharrington22/aws-resource/pkg/arguments"
-	"github.com/jharrington22/aws-resource/pkg/aws"
 	logging "github.com/jharrington22/aws-resource/pkg/logging"
 	rprtr "github.com/jharrington22/aws-resource/pkg/reporter"
 	"github.com/spf13/cobra"
@@ -38,22 +36,14 @@ func run(cmd *cobra.Command, args []string) (err error) {
 	reporter := rprtr.CreateReporterOrExit()
 	logging := logging.CreateLoggerOrExit(reporter)
 
-	awsClient, err := aws.NewClient().
-		Logger(logging).
-		Profile(arguments.Profile).
-		RoleArn(arguments.RoleArn).
-		Region(arguments.Region).
-		Build()
-
+	awsClient, err := resources.Clients(cmd, logging)(arguments.Region)
 	if err != nil {
-		_ = reporter.Errorf("Unable to build AWS client")
-		os.Exit(1)
+		return reporter.Errorf("Unable to build AWS client: %s", err)
 	}
 
 	identity, err := awsClient.GetCallerIdentity(&sts.GetCallerIdentityInput{})
 	if err != nil {
-		_ = reporter.Errorf("Error %s", err)
-		os.Exit(1)
+		return reporter.Errorf("Error %s", err)
 	}
 
 	reporter.Infof("AWS Account: %s", *identity.Account)
@@ -7,4 +7,5 @@ require (
 	github.com/sirupsen/logrus v1.6.0
 	github.com/spf13/cobra v1.3.0
 	github.com/spf13/pflag v1.0.5
+	sigs.k8s.io/yaml v1.3.0
 )
@@ -5,13 +5,108 @@ import (
 )
 
 var (
-	Region  string
-	Profile string
-	RoleArn string
+	Region      string
+	Regions     []string
+	Profile     string
+	RoleArn     string
+	ExternalID  string
+	Concurrency int
+	Output      string
+	PlanOut     string
+	Yes         bool
+	Selector    string
+	OlderThan   string
+	NewerThan   string
+	Cost        bool
+	PriceTable  string
+
+	AllAccounts     bool
+	Accounts        []string
+	ExcludeAccounts []string
+	AccountRole     string
+
+	ProtectedTags []string
+	DenyList      string
+	DeleteStack   bool
+
+	ConfigFile string
+	TargetName string
+
+	AuditLog string
 )
 
 func AddFlags(fs *pflag.FlagSet) {
 	fs.StringVarP(&Region, "region", "r", "us-east-1", "AWS Region")
 	fs.StringVarP(&Profile, "profile", "p", "", "AWS Profile")
 	fs.StringVarP(&RoleArn, "role-arn", "a", "", "AWS IAM Role ARN")
+	fs.StringVar(&ExternalID, "external-id", "", "External ID required to assume the role given by --role-arn")
+	fs.StringSliceVar(&Regions, "regions", nil, "Only look for resources in the given regions instead of every enabled region")
+	fs.IntVar(&Concurrency, "concurrency", 8, "Number of regions to query at the same time")
+}
+
+// AddOutputFlag adds the flag selecting the machine readable output format
+// of list commands.
+func AddOutputFlag(fs *pflag.FlagSet) {
+	fs.StringVar(&Output, "output", "", "Write the resources found to stdout as json, yaml, csv or table")
+}
+
+// AddPlanFlag adds the flag saving the actions of a delete command to a plan
+// file instead of running them.
+func AddPlanFlag(fs *pflag.FlagSet) {
+	fs.StringVar(&PlanOut, "plan-out", "", "Save the resources that would be deleted to a plan file, use 'aws-resource apply' to delete them")
+}
+
+// AddConfirmFlag adds the flag skipping the confirmation prompt of
+// destructive commands.
+func AddConfirmFlag(fs *pflag.FlagSet) {
+	fs.BoolVarP(&Yes, "yes", "y", false, "Delete without asking to confirm the account ID")
+}
+
+// AddFilterFlags adds the flags restricting list and delete commands to the
+// resources matching a tag selector expression or created within an age.
+func AddFilterFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&Selector, "selector", "", "Only select resources whose tags match, e.g. 'env=dev,owner!=prod,!keep,cluster in (a,b)'")
+	fs.StringVar(&OlderThan, "older-than", "", "Only select resources created longer ago than the age, e.g. 30d or 2h")
+	fs.StringVar(&NewerThan, "newer-than", "", "Only select resources created more recently than the age, e.g. 30d or 2h")
+}
+
+// AddCostFlags adds the flags estimating the cost of listed resources.
+func AddCostFlags(fs *pflag.FlagSet) {
+	fs.BoolVar(&Cost, "cost", false, "Estimate the hourly and monthly cost of the resources found")
+	fs.StringVar(&PriceTable, "price-table", "", "Estimate costs with the prices of a yaml or json file instead of the built in prices")
+}
+
+// AddAccountFlags adds the flags running a command in several accounts of an
+// organization.
+func AddAccountFlags(fs *pflag.FlagSet) {
+	fs.BoolVar(&AllAccounts, "all-accounts", false, "Run in every active account of the organization")
+	fs.StringSliceVar(&Accounts, "accounts", nil, "Run in the given account IDs")
+	fs.StringSliceVar(&ExcludeAccounts, "exclude-accounts", nil, "Skip the given account IDs when running in several accounts")
+	fs.StringVar(&AccountRole, "account-role", "OrganizationAccountAccessRole", "Name of the role assumed in each account when running in several accounts")
+}
+
+// AddProtectFlags adds the flags listing the tags, IDs and names of resources
+// delete commands must never delete.
+func AddProtectFlags(fs *pflag.FlagSet) {
+	fs.StringSliceVar(&ProtectedTags, "protected-tags", nil, "Never delete resources with one of the tags, given as key or key=value, in addition to aws-resource/protect=true")
+	fs.StringVar(&DenyList, "deny-list", "", "File of resource IDs and name patterns never to delete (default is $HOME/.aws-resource-deny-list)")
+}
+
+// AddStackFlag adds the flag deleting the CloudFormation stacks resources
+// belong to instead of refusing to delete the resources.
+func AddStackFlag(fs *pflag.FlagSet) {
+	fs.BoolVar(&DeleteStack, "delete-stack", false, "Delete the CloudFormation stack owning resources that belong to one instead of skipping them")
+}
+
+// AddConfigFlags adds the flags selecting the configuration file and the
+// target whose settings are used.
+func AddConfigFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&ConfigFile, "config", "", "Configuration file (default is $HOME/.aws-resource.yaml)")
+	fs.StringVar(&TargetName, "target", "", "Name of the target in the configuration file to use")
+}
+
+// AddAuditFlag adds the flag selecting the file every attempt to delete a
+// resource is recorded to.
+func AddAuditFlag(fs *pflag.FlagSet) {
+	fs.StringVar(&AuditLog, "audit-log", "", "File every attempt to delete a resource is appended to (default is $HOME/.aws-resource-audit.log)")
 }
@@ -0,0 +1,200 @@
+// Package audit appends every attempt to delete a resource to a local
+// JSON-lines file, and reads the entries back.
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/jharrington22/aws-resource/pkg/resource"
+)
+
+// DefaultFile is the name of the audit log kept in the home directory.
+const DefaultFile = ".aws-resource-audit.log"
+
+// Results of an attempt.
+const (
+	Succeeded = "succeeded"
+	Failed    = "failed"
+	Protected = "protected"
+)
+
+// Entry is a line of the audit log.
+type Entry struct {
+	Time      time.Time         `json:"time"`
+	Caller    string            `json:"caller,omitempty"`
+	Account   string            `json:"account,omitempty"`
+	Region    string            `json:"region"`
+	Type      string            `json:"type"`
+	ID        string            `json:"id"`
+	Name      string            `json:"name,omitempty"`
+	Tags      map[string]string `json:"tags,omitempty"`
+	DryRun    bool              `json:"dryRun"`
+	Result    string            `json:"result"`
+	ErrorCode string            `json:"errorCode,omitempty"`
+	Error     string            `json:"error,omitempty"`
+}
+
+// NewEntry returns the entry recording the deletion.
+func NewEntry(d resource.Deletion) Entry {
+	r := d.Resource
+	e := Entry{
+		Time:    time.Now().UTC(),
+		Caller:  d.Caller,
+		Account: d.Account,
+		Region:  r.Region,
+		Type:    r.Type,
+		ID:      r.ID,
+		Name:    r.Name,
+		Tags:    r.Tags,
+		DryRun:  d.DryRun,
+		Result:  Succeeded,
+	}
+	if r.Account != "" {
+		e.Account = r.Account
+	}
+
+	var protected *resource.ProtectedError
+	var aerr awserr.Error
+	switch {
+	case d.Err == nil:
+	case errors.As(d.Err, &protected):
+		e.Result = Protected
+		e.Error = protected.Reason
+	case errors.As(d.Err, &aerr):
+		e.Result = Failed
+		e.ErrorCode = aerr.Code()
+		e.Error = aerr.Message()
+	default:
+		e.Result = Failed
+		e.Error = d.Err.Error()
+	}
+	return e
+}
+
+// DefaultPath returns the path of the audit log in the home directory.
+func DefaultPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, DefaultFile), nil
+}
+
+// Log appends entries to an audit log file. It implements
+// resource.Recorder.
+type Log struct {
+	Path string
+
+	lock sync.Mutex
+}
+
+// NewLog returns the log written to path.
+func NewLog(path string) *Log {
+	return &Log{Path: path}
+}
+
+// Check returns why the log can't be appended to, if it can't.
+func (l *Log) Check() error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	f, err := os.OpenFile(l.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// Record appends the entry recording the deletion to the log.
+func (l *Log) Record(d resource.Deletion) error {
+	return l.Append(NewEntry(d))
+}
+
+// Append appends the entry to the log, creating the file if needed. The
+// file is only ever opened for appending.
+func (l *Log) Append(e Entry) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	f, err := os.OpenFile(l.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Read returns the entries of the audit log at path, oldest first. A missing
+// file holds no entries.
+func Read(path string) ([]Entry, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for n := 1; scanner.Scan(); n++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("invalid entry at line %d of %s: %s", n, path, err)
+		}
+		entries = append(entries, e)
+	}
+	return entries, scanner.Err()
+}
+
+// Query selects entries of the audit log. Zero fields match every entry.
+type Query struct {
+	Since   time.Time
+	Until   time.Time
+	Account string
+	Type    string
+}
+
+// Matches reports whether the entry is selected by the query.
+func (q Query) Matches(e Entry) bool {
+	if !q.Since.IsZero() && e.Time.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && !e.Time.Before(q.Until) {
+		return false
+	}
+	if q.Account != "" && e.Account != q.Account {
+		return false
+	}
+	return q.Type == "" || e.Type == q.Type
+}
+
+// Filter returns the entries selected by the query.
+func (q Query) Filter(entries []Entry) []Entry {
+	var selected []Entry
+	for _, e := range entries {
+		if q.Matches(e) {
+			selected = append(selected, e)
+		}
+	}
+	return selected
+}
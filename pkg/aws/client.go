@@ -2,6 +2,8 @@ package aws
 
 import (
 	"fmt"
+	"os"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -9,17 +11,28 @@ import (
 	"github.com/aws/aws-sdk-go/aws/client"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/aws/aws-sdk-go/service/cloudformation/cloudformationiface"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/aws/aws-sdk-go/service/eks"
+	"github.com/aws/aws-sdk-go/service/eks/eksiface"
 	"github.com/aws/aws-sdk-go/service/elb"
 	"github.com/aws/aws-sdk-go/service/elb/elbiface"
 	"github.com/aws/aws-sdk-go/service/elbv2"
 	"github.com/aws/aws-sdk-go/service/elbv2/elbv2iface"
 	"github.com/aws/aws-sdk-go/service/iam"
 	"github.com/aws/aws-sdk-go/service/iam/iamiface"
+	"github.com/aws/aws-sdk-go/service/organizations"
+	"github.com/aws/aws-sdk-go/service/organizations/organizationsiface"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/aws/aws-sdk-go/service/rds/rdsiface"
 	"github.com/aws/aws-sdk-go/service/route53"
 	"github.com/aws/aws-sdk-go/service/route53/route53iface"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
 	"github.com/aws/aws-sdk-go/service/sts"
 	"github.com/aws/aws-sdk-go/service/sts/stsiface"
 	"github.com/sirupsen/logrus"
@@ -30,27 +43,82 @@ var (
 )
 
 type Client interface {
+	CreateSnapshot(input *ec2.CreateSnapshotInput) (*ec2.Snapshot, error)
 	DeregisterImage(input *ec2.DeregisterImageInput) (*ec2.DeregisterImageOutput, error)
 	DescribeInstances(input *ec2.DescribeInstancesInput) (*ec2.DescribeInstancesOutput, error)
 	DescribeInstancesPages(input *ec2.DescribeInstancesInput, fn func(*ec2.DescribeInstancesOutput, bool) bool) error
 	DescribeImages(input *ec2.DescribeImagesInput) (*ec2.DescribeImagesOutput, error)
 	DescribeLoadBalancers(input *elb.DescribeLoadBalancersInput) (*elb.DescribeLoadBalancersOutput, error)
+	DescribeLoadBalancerTags(input *elb.DescribeTagsInput) (*elb.DescribeTagsOutput, error)
 	DescribeV2LoadBalancers(input *elbv2.DescribeLoadBalancersInput) (*elbv2.DescribeLoadBalancersOutput, error)
+	DescribeV2LoadBalancerTags(input *elbv2.DescribeTagsInput) (*elbv2.DescribeTagsOutput, error)
+	DescribeV2LoadBalancerAttributes(input *elbv2.DescribeLoadBalancerAttributesInput) (*elbv2.DescribeLoadBalancerAttributesOutput, error)
+	DescribeTargetGroups(input *elbv2.DescribeTargetGroupsInput) (*elbv2.DescribeTargetGroupsOutput, error)
+	DeleteLoadBalancer(input *elb.DeleteLoadBalancerInput) (*elb.DeleteLoadBalancerOutput, error)
+	DeleteV2LoadBalancer(input *elbv2.DeleteLoadBalancerInput) (*elbv2.DeleteLoadBalancerOutput, error)
+	DeleteTargetGroup(input *elbv2.DeleteTargetGroupInput) (*elbv2.DeleteTargetGroupOutput, error)
 	DescribeRegions(input *ec2.DescribeRegionsInput) (*ec2.DescribeRegionsOutput, error)
 	DescribeSnapshots(input *ec2.DescribeSnapshotsInput) (*ec2.DescribeSnapshotsOutput, error)
 	DescribeSnapshotsPages(input *ec2.DescribeSnapshotsInput, fn func(*ec2.DescribeSnapshotsOutput, bool) bool) error
 	DeleteSnapshot(input *ec2.DeleteSnapshotInput) (*ec2.DeleteSnapshotOutput, error)
 	DescribeVolumes(input *ec2.DescribeVolumesInput) (*ec2.DescribeVolumesOutput, error)
+	DeleteVolume(input *ec2.DeleteVolumeInput) (*ec2.DeleteVolumeOutput, error)
+	DescribeNatGateways(input *ec2.DescribeNatGatewaysInput) (*ec2.DescribeNatGatewaysOutput, error)
+	DeleteNatGateway(input *ec2.DeleteNatGatewayInput) (*ec2.DeleteNatGatewayOutput, error)
+	DescribeAddresses(input *ec2.DescribeAddressesInput) (*ec2.DescribeAddressesOutput, error)
+	ReleaseAddress(input *ec2.ReleaseAddressInput) (*ec2.ReleaseAddressOutput, error)
+	DescribeDBInstances(input *rds.DescribeDBInstancesInput) (*rds.DescribeDBInstancesOutput, error)
+	DescribeDBClusters(input *rds.DescribeDBClustersInput) (*rds.DescribeDBClustersOutput, error)
+	ModifyDBInstance(input *rds.ModifyDBInstanceInput) (*rds.ModifyDBInstanceOutput, error)
+	ModifyDBCluster(input *rds.ModifyDBClusterInput) (*rds.ModifyDBClusterOutput, error)
+	DeleteDBInstance(input *rds.DeleteDBInstanceInput) (*rds.DeleteDBInstanceOutput, error)
+	DeleteDBCluster(input *rds.DeleteDBClusterInput) (*rds.DeleteDBClusterOutput, error)
 	GetCallerIdentity(input *sts.GetCallerIdentityInput) (*sts.GetCallerIdentityOutput, error)
+	ListAccountsPages(input *organizations.ListAccountsInput, fn func(*organizations.ListAccountsOutput, bool) bool) error
 	ListHostedZonesByName(input *route53.ListHostedZonesByNameInput) (*route53.ListHostedZonesByNameOutput, error)
+	GetHostedZone(input *route53.GetHostedZoneInput) (*route53.GetHostedZoneOutput, error)
+	ListHostedZoneTags(input *route53.ListTagsForResourcesInput) (*route53.ListTagsForResourcesOutput, error)
+	ListResourceRecordSets(input *route53.ListResourceRecordSetsInput) (*route53.ListResourceRecordSetsOutput, error)
+	ChangeResourceRecordSets(input *route53.ChangeResourceRecordSetsInput) (*route53.ChangeResourceRecordSetsOutput, error)
+	DeleteHostedZone(input *route53.DeleteHostedZoneInput) (*route53.DeleteHostedZoneOutput, error)
+	ListBuckets(input *s3.ListBucketsInput) (*s3.ListBucketsOutput, error)
+	GetBucketLocation(input *s3.GetBucketLocationInput) (*s3.GetBucketLocationOutput, error)
+	GetBucketVersioning(input *s3.GetBucketVersioningInput) (*s3.GetBucketVersioningOutput, error)
+	GetBucketTagging(input *s3.GetBucketTaggingInput) (*s3.GetBucketTaggingOutput, error)
+	ListObjectsV2(input *s3.ListObjectsV2Input) (*s3.ListObjectsV2Output, error)
+	ListObjectVersions(input *s3.ListObjectVersionsInput) (*s3.ListObjectVersionsOutput, error)
+	DeleteObjects(input *s3.DeleteObjectsInput) (*s3.DeleteObjectsOutput, error)
+	DeleteBucket(input *s3.DeleteBucketInput) (*s3.DeleteBucketOutput, error)
+	ListClusters(input *eks.ListClustersInput) (*eks.ListClustersOutput, error)
+	DescribeCluster(input *eks.DescribeClusterInput) (*eks.DescribeClusterOutput, error)
+	ListNodegroups(input *eks.ListNodegroupsInput) (*eks.ListNodegroupsOutput, error)
+	DescribeNodegroup(input *eks.DescribeNodegroupInput) (*eks.DescribeNodegroupOutput, error)
+	ListFargateProfiles(input *eks.ListFargateProfilesInput) (*eks.ListFargateProfilesOutput, error)
+	DescribeFargateProfile(input *eks.DescribeFargateProfileInput) (*eks.DescribeFargateProfileOutput, error)
+	DeleteCluster(input *eks.DeleteClusterInput) (*eks.DeleteClusterOutput, error)
+	DeleteNodegroup(input *eks.DeleteNodegroupInput) (*eks.DeleteNodegroupOutput, error)
+	DeleteFargateProfile(input *eks.DeleteFargateProfileInput) (*eks.DeleteFargateProfileOutput, error)
+	WaitUntilClusterDeletedWithContext(ctx aws.Context, input *eks.DescribeClusterInput, opts ...request.WaiterOption) error
+	WaitUntilNodegroupDeletedWithContext(ctx aws.Context, input *eks.DescribeNodegroupInput, opts ...request.WaiterOption) error
+	WaitUntilFargateProfileDeletedWithContext(ctx aws.Context, input *eks.DescribeFargateProfileInput, opts ...request.WaiterOption) error
+	DescribeStacks(input *cloudformation.DescribeStacksInput) (*cloudformation.DescribeStacksOutput, error)
+	DeleteStack(input *cloudformation.DeleteStackInput) (*cloudformation.DeleteStackOutput, error)
+	WaitUntilStackDeleteCompleteWithContext(ctx aws.Context, input *cloudformation.DescribeStacksInput, opts ...request.WaiterOption) error
 	TerminateInstances(input *ec2.TerminateInstancesInput) (*ec2.TerminateInstancesOutput, error)
+	WaitUntilInstanceTerminatedWithContext(ctx aws.Context, input *ec2.DescribeInstancesInput, opts ...request.WaiterOption) error
+	WaitUntilV2LoadBalancersDeletedWithContext(ctx aws.Context, input *elbv2.DescribeLoadBalancersInput, opts ...request.WaiterOption) error
+	WaitUntilSnapshotCompletedWithContext(ctx aws.Context, input *ec2.DescribeSnapshotsInput, opts ...request.WaiterOption) error
 }
 
+// ClientFunc returns a client targeting the given region.
+type ClientFunc func(region string) (Client, error)
+
 type ClientBuilder struct {
 	logger      *logrus.Logger
 	region      *string
 	profile     *string
 	roleArn     *string
+	externalID  *string
 	credentials *credentials.Value
 }
 
@@ -78,6 +146,45 @@ func (b *ClientBuilder) RoleArn(value string) *ClientBuilder {
 	return b
 }
 
+// ExternalID sets the external ID passed when assuming the role.
+func (b *ClientBuilder) ExternalID(value string) *ClientBuilder {
+	b.externalID = aws.String(value)
+	return b
+}
+
+// Regional returns a ClientFunc that builds clients sharing the builder's
+// logger, profile and role but targeting the requested region. The AWS
+// session and any assumed role credentials are created once and reused by
+// every client, which are cached per region. The returned function is safe
+// for concurrent use.
+func (b *ClientBuilder) Regional() ClientFunc {
+	var (
+		once    sync.Once
+		lock    sync.Mutex
+		sess    *session.Session
+		creds   *credentials.Credentials
+		err     error
+		clients = map[string]Client{}
+	)
+	return func(region string) (Client, error) {
+		once.Do(func() {
+			sess, creds, err = b.buildSession()
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		lock.Lock()
+		defer lock.Unlock()
+		if client, ok := clients[region]; ok {
+			return client, nil
+		}
+		client := b.newClient(sess, creds, region)
+		clients[region] = client
+		return client, nil
+	}
+}
+
 // Create AWS session with a specific set of credentials
 func (b *ClientBuilder) BuildSessionWithOptionsCredentials(value *credentials.Value) (*session.Session, error) {
 	return session.NewSessionWithOptions(session.Options{
@@ -115,16 +222,26 @@ func (b *ClientBuilder) BuildSessionWithProfileOptions() (*session.Session, erro
 }
 
 func (b *ClientBuilder) Build() (Client, error) {
+	sess, creds, err := b.buildSession()
+	if err != nil {
+		return nil, err
+	}
+	return b.newClient(sess, creds, *b.region), nil
+}
+
+// buildSession creates the AWS session along with the credentials of the
+// role to assume, which are nil when no role is set.
+func (b *ClientBuilder) buildSession() (*session.Session, *credentials.Credentials, error) {
 	var err error
 
 	if b.logger == nil {
-		return nil, fmt.Errorf("Logger is required")
+		return nil, nil, fmt.Errorf("Logger is required")
 	}
 
 	var sess *session.Session
 
 	if b.region == nil || *b.region == "" {
-		fmt.Printf("No region set using %s\n", defaultAWSRegion)
+		fmt.Fprintf(os.Stderr, "No region set using %s\n", defaultAWSRegion)
 		b.region = aws.String(defaultAWSRegion)
 	}
 
@@ -139,43 +256,56 @@ func (b *ClientBuilder) Build() (Client, error) {
 		}
 	}
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	if b.roleArn != nil {
-		if *b.roleArn != "" {
-			assumeRoleCreds := stscreds.NewCredentials(sess, *b.roleArn)
-			return &awsClient{
-				logger:        b.logger,
-				ec2Client:     ec2.New(sess, &aws.Config{Credentials: assumeRoleCreds}),
-				elbClient:     elb.New(sess, &aws.Config{Credentials: assumeRoleCreds}),
-				elbV2Client:   elbv2.New(sess, &aws.Config{Credentials: assumeRoleCreds}),
-				iamClient:     iam.New(sess, &aws.Config{Credentials: assumeRoleCreds}),
-				route53Client: route53.New(sess, &aws.Config{Credentials: assumeRoleCreds}),
-				stsClient:     sts.New(sess, &aws.Config{Credentials: assumeRoleCreds}),
-			}, nil
-		}
+	if b.roleArn != nil && *b.roleArn != "" {
+		return sess, stscreds.NewCredentials(sess, *b.roleArn, func(p *stscreds.AssumeRoleProvider) {
+			if b.externalID != nil && *b.externalID != "" {
+				p.ExternalID = b.externalID
+			}
+		}), nil
+	}
+
+	return sess, nil, nil
+}
+
+// newClient creates the service clients for region from the session.
+func (b *ClientBuilder) newClient(sess *session.Session, creds *credentials.Credentials, region string) Client {
+	config := &aws.Config{Region: aws.String(region)}
+	if creds != nil {
+		config.Credentials = creds
 	}
 
 	return &awsClient{
-		logger:        b.logger,
-		ec2Client:     ec2.New(sess),
-		elbClient:     elb.New(sess),
-		elbV2Client:   elbv2.New(sess),
-		iamClient:     iam.New(sess),
-		route53Client: route53.New(sess),
-		stsClient:     sts.New(sess),
-	}, nil
+		logger:               b.logger,
+		cloudFormationClient: cloudformation.New(sess, config),
+		ec2Client:            ec2.New(sess, config),
+		eksClient:            eks.New(sess, config),
+		elbClient:            elb.New(sess, config),
+		elbV2Client:          elbv2.New(sess, config),
+		iamClient:            iam.New(sess, config),
+		organizationsClient:  organizations.New(sess, config),
+		rdsClient:            rds.New(sess, config),
+		route53Client:        route53.New(sess, config),
+		s3Client:             s3.New(sess, config),
+		stsClient:            sts.New(sess, config),
+	}
 }
 
 type awsClient struct {
-	logger        *logrus.Logger
-	ec2Client     ec2iface.EC2API
-	elbClient     elbiface.ELBAPI
-	elbV2Client   elbv2iface.ELBV2API
-	iamClient     iamiface.IAMAPI
-	route53Client route53iface.Route53API
-	stsClient     stsiface.STSAPI
+	logger               *logrus.Logger
+	cloudFormationClient cloudformationiface.CloudFormationAPI
+	ec2Client            ec2iface.EC2API
+	eksClient            eksiface.EKSAPI
+	elbClient            elbiface.ELBAPI
+	elbV2Client          elbv2iface.ELBV2API
+	iamClient            iamiface.IAMAPI
+	organizationsClient  organizationsiface.OrganizationsAPI
+	rdsClient            rdsiface.RDSAPI
+	route53Client        route53iface.Route53API
+	s3Client             s3iface.S3API
+	stsClient            stsiface.STSAPI
 }
 
 func (c *awsClient) DescribeInstances(input *ec2.DescribeInstancesInput) (*ec2.DescribeInstancesOutput, error) {
@@ -246,6 +376,125 @@ func (c *awsClient) DescribeV2LoadBalancers(input *elbv2.DescribeLoadBalancersIn
 
 }
 
+func (c *awsClient) DescribeLoadBalancerTags(input *elb.DescribeTagsInput) (*elb.DescribeTagsOutput, error) {
+
+	result, err := c.elbClient.DescribeTags(input)
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok {
+			switch aerr.Code() {
+			default:
+				return nil, aerr
+			}
+		}
+		return nil, fmt.Errorf("describe load balancer tags failed, %s", err)
+	}
+
+	return result, nil
+
+}
+
+func (c *awsClient) DescribeV2LoadBalancerTags(input *elbv2.DescribeTagsInput) (*elbv2.DescribeTagsOutput, error) {
+
+	result, err := c.elbV2Client.DescribeTags(input)
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok {
+			switch aerr.Code() {
+			default:
+				return nil, aerr
+			}
+		}
+		return nil, fmt.Errorf("describe v2 load balancer tags failed, %s", err)
+	}
+
+	return result, nil
+
+}
+
+func (c *awsClient) DeleteLoadBalancer(input *elb.DeleteLoadBalancerInput) (*elb.DeleteLoadBalancerOutput, error) {
+
+	result, err := c.elbClient.DeleteLoadBalancer(input)
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok {
+			switch aerr.Code() {
+			default:
+				return nil, aerr
+			}
+		}
+		return nil, fmt.Errorf("delete load balancer failed, %s", err)
+	}
+
+	return result, nil
+
+}
+
+func (c *awsClient) DeleteV2LoadBalancer(input *elbv2.DeleteLoadBalancerInput) (*elbv2.DeleteLoadBalancerOutput, error) {
+
+	result, err := c.elbV2Client.DeleteLoadBalancer(input)
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok {
+			switch aerr.Code() {
+			default:
+				return nil, aerr
+			}
+		}
+		return nil, fmt.Errorf("delete v2 load balancer failed, %s", err)
+	}
+
+	return result, nil
+
+}
+
+func (c *awsClient) DescribeV2LoadBalancerAttributes(input *elbv2.DescribeLoadBalancerAttributesInput) (*elbv2.DescribeLoadBalancerAttributesOutput, error) {
+
+	result, err := c.elbV2Client.DescribeLoadBalancerAttributes(input)
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok {
+			switch aerr.Code() {
+			default:
+				return nil, aerr
+			}
+		}
+		return nil, fmt.Errorf("describe v2 load balancer attributes failed, %s", err)
+	}
+
+	return result, nil
+
+}
+
+func (c *awsClient) DescribeTargetGroups(input *elbv2.DescribeTargetGroupsInput) (*elbv2.DescribeTargetGroupsOutput, error) {
+
+	result, err := c.elbV2Client.DescribeTargetGroups(input)
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok {
+			switch aerr.Code() {
+			default:
+				return nil, aerr
+			}
+		}
+		return nil, fmt.Errorf("describe target groups failed, %s", err)
+	}
+
+	return result, nil
+
+}
+
+func (c *awsClient) DeleteTargetGroup(input *elbv2.DeleteTargetGroupInput) (*elbv2.DeleteTargetGroupOutput, error) {
+
+	result, err := c.elbV2Client.DeleteTargetGroup(input)
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok {
+			switch aerr.Code() {
+			default:
+				return nil, aerr
+			}
+		}
+		return nil, fmt.Errorf("delete target group failed, %s", err)
+	}
+
+	return result, nil
+
+}
+
 func (c *awsClient) DescribeRegions(input *ec2.DescribeRegionsInput) (*ec2.DescribeRegionsOutput, error) {
 	result, err := c.ec2Client.DescribeRegions(input)
 	if err != nil {
@@ -350,6 +599,149 @@ func (c *awsClient) DescribeVolumes(input *ec2.DescribeVolumesInput) (*ec2.Descr
 	return result, nil
 }
 
+func (c *awsClient) DeleteVolume(input *ec2.DeleteVolumeInput) (*ec2.DeleteVolumeOutput, error) {
+	result, err := c.ec2Client.DeleteVolume(input)
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok {
+			switch aerr.Code() {
+			default:
+				return nil, aerr
+			}
+		}
+		return nil, fmt.Errorf("delete volume failed, %s", err)
+	}
+
+	return result, nil
+}
+
+func (c *awsClient) CreateSnapshot(input *ec2.CreateSnapshotInput) (*ec2.Snapshot, error) {
+	result, err := c.ec2Client.CreateSnapshot(input)
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok {
+			switch aerr.Code() {
+			default:
+				return nil, aerr
+			}
+		}
+		return nil, fmt.Errorf("create snapshot failed, %s", err)
+	}
+
+	return result, nil
+}
+
+func (c *awsClient) WaitUntilSnapshotCompletedWithContext(ctx aws.Context, input *ec2.DescribeSnapshotsInput, opts ...request.WaiterOption) error {
+	err := c.ec2Client.WaitUntilSnapshotCompletedWithContext(ctx, input, opts...)
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok {
+			switch aerr.Code() {
+			default:
+				return aerr
+			}
+		}
+		return fmt.Errorf("wait for snapshot failed, %s", err)
+	}
+
+	return nil
+}
+
+func (c *awsClient) WaitUntilInstanceTerminatedWithContext(ctx aws.Context, input *ec2.DescribeInstancesInput, opts ...request.WaiterOption) error {
+	err := c.ec2Client.WaitUntilInstanceTerminatedWithContext(ctx, input, opts...)
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok {
+			switch aerr.Code() {
+			default:
+				return aerr
+			}
+		}
+		return fmt.Errorf("wait for instance termination failed, %s", err)
+	}
+
+	return nil
+}
+
+func (c *awsClient) WaitUntilV2LoadBalancersDeletedWithContext(ctx aws.Context, input *elbv2.DescribeLoadBalancersInput, opts ...request.WaiterOption) error {
+	err := c.elbV2Client.WaitUntilLoadBalancersDeletedWithContext(ctx, input, opts...)
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok {
+			switch aerr.Code() {
+			default:
+				return aerr
+			}
+		}
+		return fmt.Errorf("wait for load balancer deletion failed, %s", err)
+	}
+
+	return nil
+}
+
+func (c *awsClient) DescribeNatGateways(input *ec2.DescribeNatGatewaysInput) (*ec2.DescribeNatGatewaysOutput, error) {
+
+	result, err := c.ec2Client.DescribeNatGateways(input)
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok {
+			switch aerr.Code() {
+			default:
+				return nil, aerr
+			}
+		}
+		return nil, fmt.Errorf("describe nat gateways failed, %s", err)
+	}
+
+	return result, nil
+
+}
+
+func (c *awsClient) DeleteNatGateway(input *ec2.DeleteNatGatewayInput) (*ec2.DeleteNatGatewayOutput, error) {
+
+	result, err := c.ec2Client.DeleteNatGateway(input)
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok {
+			switch aerr.Code() {
+			default:
+				return nil, aerr
+			}
+		}
+		return nil, fmt.Errorf("delete nat gateway failed, %s", err)
+	}
+
+	return result, nil
+
+}
+
+func (c *awsClient) DescribeAddresses(input *ec2.DescribeAddressesInput) (*ec2.DescribeAddressesOutput, error) {
+
+	result, err := c.ec2Client.DescribeAddresses(input)
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok {
+			switch aerr.Code() {
+			default:
+				return nil, aerr
+			}
+		}
+		return nil, fmt.Errorf("describe addresses failed, %s", err)
+	}
+
+	return result, nil
+
+}
+
+func (c *awsClient) ReleaseAddress(input *ec2.ReleaseAddressInput) (*ec2.ReleaseAddressOutput, error) {
+
+	result, err := c.ec2Client.ReleaseAddress(input)
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok {
+			switch aerr.Code() {
+			default:
+				return nil, aerr
+			}
+		}
+		return nil, fmt.Errorf("release address failed, %s", err)
+	}
+
+	return result, nil
+
+}
+
 func (c *awsClient) GetCallerIdentity(input *sts.GetCallerIdentityInput) (*sts.GetCallerIdentityOutput, error) {
 	result, err := c.stsClient.GetCallerIdentity(input)
 	if err != nil {
@@ -365,9 +757,23 @@ func (c *awsClient) GetCallerIdentity(input *sts.GetCallerIdentityInput) (*sts.G
 	return result, nil
 }
 
-func (c *awsClient) ListHostedZonesByName(input *route53.ListHostedZonesByNameInput) (*route53.ListHostedZonesByNameOutput, error) {
+func (c *awsClient) ListAccountsPages(input *organizations.ListAccountsInput, fn func(*organizations.ListAccountsOutput, bool) bool) error {
+	err := c.organizationsClient.ListAccountsPages(input, fn)
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok {
+			switch aerr.Code() {
+			default:
+				return aerr
+			}
+		}
+		return fmt.Errorf("list accounts failed, %s", err)
+	}
+	return nil
+}
 
-	result, err := c.route53Client.ListHostedZonesByName(input)
+func (c *awsClient) GetHostedZone(input *route53.GetHostedZoneInput) (*route53.GetHostedZoneOutput, error) {
+
+	result, err := c.route53Client.GetHostedZone(input)
 	if err != nil {
 		if aerr, ok := err.(awserr.Error); ok {
 			switch aerr.Code() {
@@ -375,13 +781,583 @@ func (c *awsClient) ListHostedZonesByName(input *route53.ListHostedZonesByNameIn
 				return nil, aerr
 			}
 		}
-		return nil, fmt.Errorf("describe hosted zones failed, %s", err)
+		return nil, fmt.Errorf("get hosted zone failed, %s", err)
 	}
 
 	return result, nil
 
 }
 
+func (c *awsClient) ListResourceRecordSets(input *route53.ListResourceRecordSetsInput) (*route53.ListResourceRecordSetsOutput, error) {
+
+	result, err := c.route53Client.ListResourceRecordSets(input)
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok {
+			switch aerr.Code() {
+			default:
+				return nil, aerr
+			}
+		}
+		return nil, fmt.Errorf("list resource record sets failed, %s", err)
+	}
+
+	return result, nil
+
+}
+
+func (c *awsClient) ListHostedZoneTags(input *route53.ListTagsForResourcesInput) (*route53.ListTagsForResourcesOutput, error) {
+
+	result, err := c.route53Client.ListTagsForResources(input)
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok {
+			switch aerr.Code() {
+			default:
+				return nil, aerr
+			}
+		}
+		return nil, fmt.Errorf("list hosted zone tags failed, %s", err)
+	}
+
+	return result, nil
+
+}
+
+func (c *awsClient) ChangeResourceRecordSets(input *route53.ChangeResourceRecordSetsInput) (*route53.ChangeResourceRecordSetsOutput, error) {
+
+	result, err := c.route53Client.ChangeResourceRecordSets(input)
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok {
+			switch aerr.Code() {
+			default:
+				return nil, aerr
+			}
+		}
+		return nil, fmt.Errorf("change resource record sets failed, %s", err)
+	}
+
+	return result, nil
+
+}
+
+func (c *awsClient) DeleteHostedZone(input *route53.DeleteHostedZoneInput) (*route53.DeleteHostedZoneOutput, error) {
+
+	result, err := c.route53Client.DeleteHostedZone(input)
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok {
+			switch aerr.Code() {
+			default:
+				return nil, aerr
+			}
+		}
+		return nil, fmt.Errorf("delete hosted zone failed, %s", err)
+	}
+
+	return result, nil
+
+}
+
+func (c *awsClient) ListHostedZonesByName(input *route53.ListHostedZonesByNameInput) (*route53.ListHostedZonesByNameOutput, error) {
+
+	result, err := c.route53Client.ListHostedZonesByName(input)
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok {
+			switch aerr.Code() {
+			default:
+				return nil, aerr
+			}
+		}
+		return nil, fmt.Errorf("describe hosted zones failed, %s", err)
+	}
+
+	return result, nil
+
+}
+
+func (c *awsClient) DescribeDBInstances(input *rds.DescribeDBInstancesInput) (*rds.DescribeDBInstancesOutput, error) {
+
+	result, err := c.rdsClient.DescribeDBInstances(input)
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok {
+			switch aerr.Code() {
+			default:
+				return nil, aerr
+			}
+		}
+		return nil, fmt.Errorf("describe db instances failed, %s", err)
+	}
+
+	return result, nil
+
+}
+
+func (c *awsClient) DescribeDBClusters(input *rds.DescribeDBClustersInput) (*rds.DescribeDBClustersOutput, error) {
+
+	result, err := c.rdsClient.DescribeDBClusters(input)
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok {
+			switch aerr.Code() {
+			default:
+				return nil, aerr
+			}
+		}
+		return nil, fmt.Errorf("describe db clusters failed, %s", err)
+	}
+
+	return result, nil
+
+}
+
+func (c *awsClient) ModifyDBInstance(input *rds.ModifyDBInstanceInput) (*rds.ModifyDBInstanceOutput, error) {
+
+	result, err := c.rdsClient.ModifyDBInstance(input)
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok {
+			switch aerr.Code() {
+			default:
+				return nil, aerr
+			}
+		}
+		return nil, fmt.Errorf("modify db instance failed, %s", err)
+	}
+
+	return result, nil
+
+}
+
+func (c *awsClient) ModifyDBCluster(input *rds.ModifyDBClusterInput) (*rds.ModifyDBClusterOutput, error) {
+
+	result, err := c.rdsClient.ModifyDBCluster(input)
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok {
+			switch aerr.Code() {
+			default:
+				return nil, aerr
+			}
+		}
+		return nil, fmt.Errorf("modify db cluster failed, %s", err)
+	}
+
+	return result, nil
+
+}
+
+func (c *awsClient) DeleteDBInstance(input *rds.DeleteDBInstanceInput) (*rds.DeleteDBInstanceOutput, error) {
+
+	result, err := c.rdsClient.DeleteDBInstance(input)
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok {
+			switch aerr.Code() {
+			default:
+				return nil, aerr
+			}
+		}
+		return nil, fmt.Errorf("delete db instance failed, %s", err)
+	}
+
+	return result, nil
+
+}
+
+func (c *awsClient) DeleteDBCluster(input *rds.DeleteDBClusterInput) (*rds.DeleteDBClusterOutput, error) {
+
+	result, err := c.rdsClient.DeleteDBCluster(input)
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok {
+			switch aerr.Code() {
+			default:
+				return nil, aerr
+			}
+		}
+		return nil, fmt.Errorf("delete db cluster failed, %s", err)
+	}
+
+	return result, nil
+
+}
+
+func (c *awsClient) ListBuckets(input *s3.ListBucketsInput) (*s3.ListBucketsOutput, error) {
+
+	result, err := c.s3Client.ListBuckets(input)
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok {
+			switch aerr.Code() {
+			default:
+				return nil, aerr
+			}
+		}
+		return nil, fmt.Errorf("list buckets failed, %s", err)
+	}
+
+	return result, nil
+
+}
+
+func (c *awsClient) GetBucketLocation(input *s3.GetBucketLocationInput) (*s3.GetBucketLocationOutput, error) {
+
+	result, err := c.s3Client.GetBucketLocation(input)
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok {
+			switch aerr.Code() {
+			default:
+				return nil, aerr
+			}
+		}
+		return nil, fmt.Errorf("get bucket location failed, %s", err)
+	}
+
+	return result, nil
+
+}
+
+func (c *awsClient) GetBucketVersioning(input *s3.GetBucketVersioningInput) (*s3.GetBucketVersioningOutput, error) {
+
+	result, err := c.s3Client.GetBucketVersioning(input)
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok {
+			switch aerr.Code() {
+			default:
+				return nil, aerr
+			}
+		}
+		return nil, fmt.Errorf("get bucket versioning failed, %s", err)
+	}
+
+	return result, nil
+
+}
+
+func (c *awsClient) GetBucketTagging(input *s3.GetBucketTaggingInput) (*s3.GetBucketTaggingOutput, error) {
+
+	result, err := c.s3Client.GetBucketTagging(input)
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok {
+			switch aerr.Code() {
+			default:
+				return nil, aerr
+			}
+		}
+		return nil, fmt.Errorf("get bucket tagging failed, %s", err)
+	}
+
+	return result, nil
+
+}
+
+func (c *awsClient) ListObjectsV2(input *s3.ListObjectsV2Input) (*s3.ListObjectsV2Output, error) {
+
+	result, err := c.s3Client.ListObjectsV2(input)
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok {
+			switch aerr.Code() {
+			default:
+				return nil, aerr
+			}
+		}
+		return nil, fmt.Errorf("list objects failed, %s", err)
+	}
+
+	return result, nil
+
+}
+
+func (c *awsClient) ListObjectVersions(input *s3.ListObjectVersionsInput) (*s3.ListObjectVersionsOutput, error) {
+
+	result, err := c.s3Client.ListObjectVersions(input)
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok {
+			switch aerr.Code() {
+			default:
+				return nil, aerr
+			}
+		}
+		return nil, fmt.Errorf("list object versions failed, %s", err)
+	}
+
+	return result, nil
+
+}
+
+func (c *awsClient) DeleteObjects(input *s3.DeleteObjectsInput) (*s3.DeleteObjectsOutput, error) {
+
+	result, err := c.s3Client.DeleteObjects(input)
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok {
+			switch aerr.Code() {
+			default:
+				return nil, aerr
+			}
+		}
+		return nil, fmt.Errorf("delete objects failed, %s", err)
+	}
+
+	return result, nil
+
+}
+
+func (c *awsClient) DeleteBucket(input *s3.DeleteBucketInput) (*s3.DeleteBucketOutput, error) {
+
+	result, err := c.s3Client.DeleteBucket(input)
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok {
+			switch aerr.Code() {
+			default:
+				return nil, aerr
+			}
+		}
+		return nil, fmt.Errorf("delete bucket failed, %s", err)
+	}
+
+	return result, nil
+
+}
+
+func (c *awsClient) ListClusters(input *eks.ListClustersInput) (*eks.ListClustersOutput, error) {
+
+	result, err := c.eksClient.ListClusters(input)
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok {
+			switch aerr.Code() {
+			default:
+				return nil, aerr
+			}
+		}
+		return nil, fmt.Errorf("list clusters failed, %s", err)
+	}
+
+	return result, nil
+
+}
+
+func (c *awsClient) DescribeCluster(input *eks.DescribeClusterInput) (*eks.DescribeClusterOutput, error) {
+
+	result, err := c.eksClient.DescribeCluster(input)
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok {
+			switch aerr.Code() {
+			default:
+				return nil, aerr
+			}
+		}
+		return nil, fmt.Errorf("describe cluster failed, %s", err)
+	}
+
+	return result, nil
+
+}
+
+func (c *awsClient) ListNodegroups(input *eks.ListNodegroupsInput) (*eks.ListNodegroupsOutput, error) {
+
+	result, err := c.eksClient.ListNodegroups(input)
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok {
+			switch aerr.Code() {
+			default:
+				return nil, aerr
+			}
+		}
+		return nil, fmt.Errorf("list nodegroups failed, %s", err)
+	}
+
+	return result, nil
+
+}
+
+func (c *awsClient) DescribeNodegroup(input *eks.DescribeNodegroupInput) (*eks.DescribeNodegroupOutput, error) {
+
+	result, err := c.eksClient.DescribeNodegroup(input)
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok {
+			switch aerr.Code() {
+			default:
+				return nil, aerr
+			}
+		}
+		return nil, fmt.Errorf("describe nodegroup failed, %s", err)
+	}
+
+	return result, nil
+
+}
+
+func (c *awsClient) ListFargateProfiles(input *eks.ListFargateProfilesInput) (*eks.ListFargateProfilesOutput, error) {
+
+	result, err := c.eksClient.ListFargateProfiles(input)
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok {
+			switch aerr.Code() {
+			default:
+				return nil, aerr
+			}
+		}
+		return nil, fmt.Errorf("list fargate profiles failed, %s", err)
+	}
+
+	return result, nil
+
+}
+
+func (c *awsClient) DescribeFargateProfile(input *eks.DescribeFargateProfileInput) (*eks.DescribeFargateProfileOutput, error) {
+
+	result, err := c.eksClient.DescribeFargateProfile(input)
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok {
+			switch aerr.Code() {
+			default:
+				return nil, aerr
+			}
+		}
+		return nil, fmt.Errorf("describe fargate profile failed, %s", err)
+	}
+
+	return result, nil
+
+}
+
+func (c *awsClient) DeleteCluster(input *eks.DeleteClusterInput) (*eks.DeleteClusterOutput, error) {
+
+	result, err := c.eksClient.DeleteCluster(input)
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok {
+			switch aerr.Code() {
+			default:
+				return nil, aerr
+			}
+		}
+		return nil, fmt.Errorf("delete cluster failed, %s", err)
+	}
+
+	return result, nil
+
+}
+
+func (c *awsClient) DeleteNodegroup(input *eks.DeleteNodegroupInput) (*eks.DeleteNodegroupOutput, error) {
+
+	result, err := c.eksClient.DeleteNodegroup(input)
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok {
+			switch aerr.Code() {
+			default:
+				return nil, aerr
+			}
+		}
+		return nil, fmt.Errorf("delete nodegroup failed, %s", err)
+	}
+
+	return result, nil
+
+}
+
+func (c *awsClient) DeleteFargateProfile(input *eks.DeleteFargateProfileInput) (*eks.DeleteFargateProfileOutput, error) {
+
+	result, err := c.eksClient.DeleteFargateProfile(input)
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok {
+			switch aerr.Code() {
+			default:
+				return nil, aerr
+			}
+		}
+		return nil, fmt.Errorf("delete fargate profile failed, %s", err)
+	}
+
+	return result, nil
+
+}
+
+func (c *awsClient) WaitUntilClusterDeletedWithContext(ctx aws.Context, input *eks.DescribeClusterInput, opts ...request.WaiterOption) error {
+	err := c.eksClient.WaitUntilClusterDeletedWithContext(ctx, input, opts...)
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok {
+			switch aerr.Code() {
+			default:
+				return aerr
+			}
+		}
+		return fmt.Errorf("wait for cluster deletion failed, %s", err)
+	}
+
+	return nil
+}
+
+func (c *awsClient) WaitUntilNodegroupDeletedWithContext(ctx aws.Context, input *eks.DescribeNodegroupInput, opts ...request.WaiterOption) error {
+	err := c.eksClient.WaitUntilNodegroupDeletedWithContext(ctx, input, opts...)
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok {
+			switch aerr.Code() {
+			default:
+				return aerr
+			}
+		}
+		return fmt.Errorf("wait for nodegroup deletion failed, %s", err)
+	}
+
+	return nil
+}
+
+func (c *awsClient) WaitUntilFargateProfileDeletedWithContext(ctx aws.Context, input *eks.DescribeFargateProfileInput, opts ...request.WaiterOption) error {
+	err := c.eksClient.WaitUntilFargateProfileDeletedWithContext(ctx, input, opts...)
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok {
+			switch aerr.Code() {
+			default:
+				return aerr
+			}
+		}
+		return fmt.Errorf("wait for fargate profile deletion failed, %s", err)
+	}
+
+	return nil
+}
+
+func (c *awsClient) DescribeStacks(input *cloudformation.DescribeStacksInput) (*cloudformation.DescribeStacksOutput, error) {
+
+	result, err := c.cloudFormationClient.DescribeStacks(input)
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok {
+			switch aerr.Code() {
+			default:
+				return nil, aerr
+			}
+		}
+		return nil, fmt.Errorf("describe stacks failed, %s", err)
+	}
+
+	return result, nil
+
+}
+
+func (c *awsClient) DeleteStack(input *cloudformation.DeleteStackInput) (*cloudformation.DeleteStackOutput, error) {
+
+	result, err := c.cloudFormationClient.DeleteStack(input)
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok {
+			switch aerr.Code() {
+			default:
+				return nil, aerr
+			}
+		}
+		return nil, fmt.Errorf("delete stack failed, %s", err)
+	}
+
+	return result, nil
+
+}
+
+func (c *awsClient) WaitUntilStackDeleteCompleteWithContext(ctx aws.Context, input *cloudformation.DescribeStacksInput, opts ...request.WaiterOption) error {
+	err := c.cloudFormationClient.WaitUntilStackDeleteCompleteWithContext(ctx, input, opts...)
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok {
+			switch aerr.Code() {
+			default:
+				return aerr
+			}
+		}
+		return fmt.Errorf("wait for stack deletion failed, %s", err)
+	}
+
+	return nil
+}
+
 func (c *awsClient) TerminateInstances(input *ec2.TerminateInstancesInput) (*ec2.TerminateInstancesOutput, error) {
 
 	result, err := c.ec2Client.TerminateInstances(input)
@@ -0,0 +1,86 @@
+package aws
+
+import (
+	"context"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Target identifies the credentials used to reach an AWS account.
+type Target struct {
+	// Profile is the name of the profile in the local AWS configuration,
+	// the default credential chain is used when empty.
+	Profile string
+
+	// RoleArn is the IAM role assumed before any operation, if set.
+	RoleArn string
+
+	// ExternalID is passed when assuming RoleArn, if set.
+	ExternalID string
+}
+
+// Factory creates the clients used by commands. Commands get their factory
+// from the context they're executed with so that callers can provide fakes
+// or share sessions between commands.
+type Factory interface {
+	// Client returns a client for the target in the given region.
+	Client(target Target, region string) (Client, error)
+}
+
+// Clients binds a factory to a target, returning a function that only needs
+// the region.
+func Clients(factory Factory, target Target) ClientFunc {
+	return func(region string) (Client, error) {
+		return factory.Client(target, region)
+	}
+}
+
+// NewFactory returns the factory building real AWS clients. Clients of the
+// same target share a session, see ClientBuilder.Regional.
+func NewFactory(logger *logrus.Logger) Factory {
+	return &factory{
+		logger:  logger,
+		targets: map[Target]ClientFunc{},
+	}
+}
+
+type factory struct {
+	logger  *logrus.Logger
+	lock    sync.Mutex
+	targets map[Target]ClientFunc
+}
+
+func (f *factory) Client(target Target, region string) (Client, error) {
+	f.lock.Lock()
+	clients, ok := f.targets[target]
+	if !ok {
+		clients = NewClient().
+			Logger(f.logger).
+			Profile(target.Profile).
+			RoleArn(target.RoleArn).
+			ExternalID(target.ExternalID).
+			Region(region).
+			Regional()
+		f.targets[target] = clients
+	}
+	f.lock.Unlock()
+
+	return clients(region)
+}
+
+type factoryKey struct{}
+
+// WithFactory returns a copy of ctx carrying the factory.
+func WithFactory(ctx context.Context, factory Factory) context.Context {
+	return context.WithValue(ctx, factoryKey{}, factory)
+}
+
+// FactoryFromContext returns the factory carried by ctx, or nil.
+func FactoryFromContext(ctx context.Context) Factory {
+	if ctx == nil {
+		return nil
+	}
+	factory, _ := ctx.Value(factoryKey{}).(Factory)
+	return factory
+}
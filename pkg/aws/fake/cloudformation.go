@@ -0,0 +1,173 @@
+package fake
+
+import (
+	"fmt"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// stackNameTag is set by CloudFormation on the resources of a stack.
+const stackNameTag = "aws:cloudformation:stack-name"
+
+// AddStack adds a stack in the CREATE_COMPLETE state to the region,
+// assigning a name and ID when they're not set. Resources are added to the
+// stack by tagging them with StackTags.
+func (b *Backend) AddStack(regionName string, stack *cloudformation.Stack) *cloudformation.Stack {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if stack.StackName == nil {
+		stack.StackName = str(b.id("stack"))
+	}
+	if stack.StackId == nil {
+		stack.StackId = str(fmt.Sprintf("arn:aws:cloudformation:%s:%s:stack/%s/%s", regionName, b.accountID, *stack.StackName, b.id("id")))
+	}
+	if stack.StackStatus == nil {
+		stack.StackStatus = str(cloudformation.StackStatusCreateComplete)
+	}
+	if stack.CreationTime == nil {
+		stack.CreationTime = timePtr(time.Now())
+	}
+	r := b.mustRegion(regionName)
+	r.stacks = append(r.stacks, stack)
+	return stack
+}
+
+// StackTags returns the tags CloudFormation sets on the EC2 resources of
+// the stack.
+func StackTags(stack *cloudformation.Stack) []*ec2.Tag {
+	return []*ec2.Tag{
+		{Key: str(stackNameTag), Value: stack.StackName},
+		{Key: str("aws:cloudformation:stack-id"), Value: stack.StackId},
+	}
+}
+
+// Stacks returns the stacks in the region.
+func (b *Backend) Stacks(regionName string) []*cloudformation.Stack {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return append([]*cloudformation.Stack{}, b.mustRegion(regionName).stacks...)
+}
+
+func stack(r *region, name string) (int, *cloudformation.Stack) {
+	for n, s := range r.stacks {
+		if *s.StackName == name || *s.StackId == name {
+			return n, s
+		}
+	}
+	return -1, nil
+}
+
+func stackNotFound(name string) error {
+	return awserr.New("ValidationError", fmt.Sprintf("Stack with id %s does not exist", name), nil)
+}
+
+func (c *Client) DescribeStacks(input *cloudformation.DescribeStacksInput) (*cloudformation.DescribeStacksOutput, error) {
+	c.backend.lock.Lock()
+	defer c.backend.lock.Unlock()
+	r, err := c.backend.region(c.region)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.StackName != nil {
+		_, s := stack(r, *input.StackName)
+		if s == nil {
+			return nil, stackNotFound(*input.StackName)
+		}
+		return &cloudformation.DescribeStacksOutput{Stacks: []*cloudformation.Stack{s}}, nil
+	}
+
+	start, end, next, err := c.backend.page(len(r.stacks), input.NextToken, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &cloudformation.DescribeStacksOutput{
+		Stacks:    append([]*cloudformation.Stack{}, r.stacks[start:end]...),
+		NextToken: next,
+	}, nil
+}
+
+// DeleteStack marks the stack as being deleted, failing when termination
+// protection is enabled. The stack and its resources are deleted once
+// waited for. Deleting a stack that doesn't exist succeeds.
+func (c *Client) DeleteStack(input *cloudformation.DeleteStackInput) (*cloudformation.DeleteStackOutput, error) {
+	c.backend.lock.Lock()
+	defer c.backend.lock.Unlock()
+	r, err := c.backend.region(c.region)
+	if err != nil {
+		return nil, err
+	}
+
+	_, s := stack(r, strValue(input.StackName))
+	if s == nil {
+		return &cloudformation.DeleteStackOutput{}, nil
+	}
+	if awssdk.BoolValue(s.EnableTerminationProtection) {
+		return nil, awserr.New("ValidationError",
+			fmt.Sprintf("Stack [%s] cannot be deleted while TerminationProtection is enabled", *s.StackName), nil)
+	}
+	s.StackStatus = str(cloudformation.StackStatusDeleteInProgress)
+	return &cloudformation.DeleteStackOutput{}, nil
+}
+
+// WaitUntilStackDeleteCompleteWithContext deletes the stack being deleted,
+// terminating its instances and deleting its volumes and classic load
+// balancers.
+func (c *Client) WaitUntilStackDeleteCompleteWithContext(ctx awssdk.Context, input *cloudformation.DescribeStacksInput, opts ...request.WaiterOption) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	c.backend.lock.Lock()
+	defer c.backend.lock.Unlock()
+	r, err := c.backend.region(c.region)
+	if err != nil {
+		return err
+	}
+
+	n, s := stack(r, strValue(input.StackName))
+	if s == nil {
+		return nil
+	}
+	if strValue(s.StackStatus) != cloudformation.StackStatusDeleteInProgress {
+		return awserr.New(request.WaiterResourceNotReadyErrorCode, fmt.Sprintf("stack %s is not being deleted", *s.StackName), nil)
+	}
+	r.stacks = append(r.stacks[:n], r.stacks[n+1:]...)
+
+	owned := func(tags []*ec2.Tag) bool {
+		for _, t := range tags {
+			if strValue(t.Key) == stackNameTag && strValue(t.Value) == *s.StackName {
+				return true
+			}
+		}
+		return false
+	}
+	for _, i := range r.instances {
+		if owned(i.Tags) {
+			i.State = &ec2.InstanceState{Name: str(ec2.InstanceStateNameTerminated)}
+		}
+	}
+	var volumes []*ec2.Volume
+	for _, v := range r.volumes {
+		if !owned(v.Tags) {
+			volumes = append(volumes, v)
+		}
+	}
+	r.volumes = volumes
+	for n := 0; n < len(r.loadBalancers); n++ {
+		name := *r.loadBalancers[n].LoadBalancerName
+		for _, t := range r.loadBalancerTags[name] {
+			if strValue(t.Key) == stackNameTag && strValue(t.Value) == *s.StackName {
+				r.loadBalancers = append(r.loadBalancers[:n], r.loadBalancers[n+1:]...)
+				delete(r.loadBalancerTags, name)
+				n--
+				break
+			}
+		}
+	}
+	return nil
+}
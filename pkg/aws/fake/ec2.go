@@ -0,0 +1,545 @@
+package fake
+
+import (
+	"fmt"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// AddInstance adds an instance to the region, assigning an ID and a running
+// state when they're not set.
+func (b *Backend) AddInstance(regionName string, instance *ec2.Instance) *ec2.Instance {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if instance.InstanceId == nil {
+		instance.InstanceId = str(b.id("i"))
+	}
+	if instance.State == nil {
+		instance.State = &ec2.InstanceState{Name: str(ec2.InstanceStateNameRunning)}
+	}
+	if instance.LaunchTime == nil {
+		instance.LaunchTime = timePtr(time.Now())
+	}
+	r := b.mustRegion(regionName)
+	r.instances = append(r.instances, instance)
+	return instance
+}
+
+// AddVolume adds a volume to the region, assigning an ID when it's not set.
+func (b *Backend) AddVolume(regionName string, volume *ec2.Volume) *ec2.Volume {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if volume.VolumeId == nil {
+		volume.VolumeId = str(b.id("vol"))
+	}
+	if volume.State == nil {
+		state := ec2.VolumeStateAvailable
+		if len(volume.Attachments) > 0 {
+			state = ec2.VolumeStateInUse
+		}
+		volume.State = str(state)
+	}
+	if volume.CreateTime == nil {
+		volume.CreateTime = timePtr(time.Now())
+	}
+	r := b.mustRegion(regionName)
+	r.volumes = append(r.volumes, volume)
+	return volume
+}
+
+// AddSnapshot adds a snapshot owned by the account to the region, assigning
+// an ID when it's not set.
+func (b *Backend) AddSnapshot(regionName string, snapshot *ec2.Snapshot) *ec2.Snapshot {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if snapshot.SnapshotId == nil {
+		snapshot.SnapshotId = str(b.id("snap"))
+	}
+	if snapshot.OwnerId == nil {
+		snapshot.OwnerId = str(b.accountID)
+	}
+	if snapshot.State == nil {
+		snapshot.State = str(ec2.SnapshotStateCompleted)
+	}
+	if snapshot.StartTime == nil {
+		snapshot.StartTime = timePtr(time.Now())
+	}
+	r := b.mustRegion(regionName)
+	r.snapshots = append(r.snapshots, snapshot)
+	return snapshot
+}
+
+// AddImage adds an AMI owned by the account to the region, assigning an ID
+// when it's not set. Snapshots referenced by the image's block device
+// mappings are in use until the image is deregistered.
+func (b *Backend) AddImage(regionName string, image *ec2.Image) *ec2.Image {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if image.ImageId == nil {
+		image.ImageId = str(b.id("ami"))
+	}
+	if image.OwnerId == nil {
+		image.OwnerId = str(b.accountID)
+	}
+	if image.State == nil {
+		image.State = str(ec2.ImageStateAvailable)
+	}
+	if image.CreationDate == nil {
+		image.CreationDate = str(time.Now().UTC().Format("2006-01-02T15:04:05.000Z"))
+	}
+	r := b.mustRegion(regionName)
+	r.images = append(r.images, image)
+	return image
+}
+
+// Instances returns the instances in the region, including terminated ones.
+func (b *Backend) Instances(regionName string) []*ec2.Instance {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return append([]*ec2.Instance{}, b.mustRegion(regionName).instances...)
+}
+
+// Volumes returns the volumes in the region.
+func (b *Backend) Volumes(regionName string) []*ec2.Volume {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return append([]*ec2.Volume{}, b.mustRegion(regionName).volumes...)
+}
+
+// Snapshots returns the snapshots in the region.
+func (b *Backend) Snapshots(regionName string) []*ec2.Snapshot {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return append([]*ec2.Snapshot{}, b.mustRegion(regionName).snapshots...)
+}
+
+// Images returns the AMIs in the region.
+func (b *Backend) Images(regionName string) []*ec2.Image {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return append([]*ec2.Image{}, b.mustRegion(regionName).images...)
+}
+
+func (b *Backend) mustRegion(name string) *region {
+	r, ok := b.regions[name]
+	if !ok {
+		panic(fmt.Sprintf("fake: region %s is not enabled", name))
+	}
+	return r
+}
+
+func (c *Client) DescribeRegions(input *ec2.DescribeRegionsInput) (*ec2.DescribeRegionsOutput, error) {
+	c.backend.lock.Lock()
+	defer c.backend.lock.Unlock()
+	if _, err := c.backend.region(c.region); err != nil {
+		return nil, err
+	}
+
+	output := &ec2.DescribeRegionsOutput{}
+	for _, name := range c.backend.regionNames() {
+		output.Regions = append(output.Regions, &ec2.Region{
+			RegionName: str(name),
+			Endpoint:   str(fmt.Sprintf("ec2.%s.amazonaws.com", name)),
+		})
+	}
+	return output, nil
+}
+
+func (c *Client) DescribeInstances(input *ec2.DescribeInstancesInput) (*ec2.DescribeInstancesOutput, error) {
+	c.backend.lock.Lock()
+	defer c.backend.lock.Unlock()
+	r, err := c.backend.region(c.region)
+	if err != nil {
+		return nil, err
+	}
+
+	var instances []*ec2.Instance
+	for _, i := range r.instances {
+		if len(input.InstanceIds) == 0 || contains(input.InstanceIds, i.InstanceId) {
+			instances = append(instances, i)
+		}
+	}
+
+	start, end, next, err := c.backend.page(len(instances), input.NextToken, input.MaxResults)
+	if err != nil {
+		return nil, err
+	}
+	output := &ec2.DescribeInstancesOutput{NextToken: next}
+	for _, i := range instances[start:end] {
+		output.Reservations = append(output.Reservations, &ec2.Reservation{
+			OwnerId:   str(c.backend.accountID),
+			Instances: []*ec2.Instance{i},
+		})
+	}
+	return output, nil
+}
+
+func (c *Client) DescribeInstancesPages(input *ec2.DescribeInstancesInput, fn func(*ec2.DescribeInstancesOutput, bool) bool) error {
+	page := *input
+	for {
+		output, err := c.DescribeInstances(&page)
+		if err != nil {
+			return err
+		}
+		if !fn(output, output.NextToken == nil) || output.NextToken == nil {
+			return nil
+		}
+		page.NextToken = output.NextToken
+	}
+}
+
+func (c *Client) TerminateInstances(input *ec2.TerminateInstancesInput) (*ec2.TerminateInstancesOutput, error) {
+	c.backend.lock.Lock()
+	defer c.backend.lock.Unlock()
+	r, err := c.backend.region(c.region)
+	if err != nil {
+		return nil, err
+	}
+
+	var found []*ec2.Instance
+	for _, id := range input.InstanceIds {
+		var instance *ec2.Instance
+		for _, i := range r.instances {
+			if *i.InstanceId == *id {
+				instance = i
+			}
+		}
+		if instance == nil {
+			return nil, awserr.New("InvalidInstanceID.NotFound", fmt.Sprintf("The instance ID '%s' does not exist", *id), nil)
+		}
+		found = append(found, instance)
+	}
+	if err := dryRun(input.DryRun); err != nil {
+		return nil, err
+	}
+
+	output := &ec2.TerminateInstancesOutput{}
+	for _, i := range found {
+		previous := *i.State
+		i.State = &ec2.InstanceState{Name: str(ec2.InstanceStateNameTerminated)}
+		output.TerminatingInstances = append(output.TerminatingInstances, &ec2.InstanceStateChange{
+			InstanceId:    i.InstanceId,
+			PreviousState: &previous,
+			CurrentState:  i.State,
+		})
+	}
+	return output, nil
+}
+
+// WaitUntilInstanceTerminatedWithContext detaches the volumes of the
+// terminated instances, which AWS only does once they're terminated, so that
+// deleting them beforehand fails.
+func (c *Client) WaitUntilInstanceTerminatedWithContext(ctx awssdk.Context, input *ec2.DescribeInstancesInput, opts ...request.WaiterOption) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	c.backend.lock.Lock()
+	defer c.backend.lock.Unlock()
+	r, err := c.backend.region(c.region)
+	if err != nil {
+		return err
+	}
+
+	for _, i := range r.instances {
+		if !contains(input.InstanceIds, i.InstanceId) {
+			continue
+		}
+		if *i.State.Name != ec2.InstanceStateNameTerminated {
+			return awserr.New(request.WaiterResourceNotReadyErrorCode, fmt.Sprintf("instance %s is %s", *i.InstanceId, *i.State.Name), nil)
+		}
+		for _, v := range r.volumes {
+			for _, a := range v.Attachments {
+				if strValue(a.InstanceId) == *i.InstanceId {
+					v.Attachments = nil
+					v.State = str(ec2.VolumeStateAvailable)
+					break
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (c *Client) DescribeVolumes(input *ec2.DescribeVolumesInput) (*ec2.DescribeVolumesOutput, error) {
+	c.backend.lock.Lock()
+	defer c.backend.lock.Unlock()
+	r, err := c.backend.region(c.region)
+	if err != nil {
+		return nil, err
+	}
+
+	var volumes []*ec2.Volume
+	for _, v := range r.volumes {
+		if len(input.VolumeIds) == 0 || contains(input.VolumeIds, v.VolumeId) {
+			volumes = append(volumes, v)
+		}
+	}
+	if len(input.VolumeIds) > 0 && len(volumes) < len(input.VolumeIds) {
+		return nil, awserr.New("InvalidVolume.NotFound", "The volume does not exist", nil)
+	}
+
+	start, end, next, err := c.backend.page(len(volumes), input.NextToken, input.MaxResults)
+	if err != nil {
+		return nil, err
+	}
+	return &ec2.DescribeVolumesOutput{
+		Volumes:   append([]*ec2.Volume{}, volumes[start:end]...),
+		NextToken: next,
+	}, nil
+}
+
+func (c *Client) DeleteVolume(input *ec2.DeleteVolumeInput) (*ec2.DeleteVolumeOutput, error) {
+	c.backend.lock.Lock()
+	defer c.backend.lock.Unlock()
+	r, err := c.backend.region(c.region)
+	if err != nil {
+		return nil, err
+	}
+
+	index := -1
+	for n, v := range r.volumes {
+		if *v.VolumeId == *input.VolumeId {
+			index = n
+		}
+	}
+	if index < 0 {
+		return nil, awserr.New("InvalidVolume.NotFound", fmt.Sprintf("The volume '%s' does not exist.", *input.VolumeId), nil)
+	}
+	if v := r.volumes[index]; len(v.Attachments) > 0 {
+		return nil, awserr.New("VolumeInUse",
+			fmt.Sprintf("Volume %s is currently attached to %s", *input.VolumeId, strValue(v.Attachments[0].InstanceId)), nil)
+	}
+	if err := dryRun(input.DryRun); err != nil {
+		return nil, err
+	}
+
+	r.volumes = append(r.volumes[:index], r.volumes[index+1:]...)
+	return &ec2.DeleteVolumeOutput{}, nil
+}
+
+// CreateSnapshot adds a pending snapshot of the volume, completed once it's
+// waited for.
+func (c *Client) CreateSnapshot(input *ec2.CreateSnapshotInput) (*ec2.Snapshot, error) {
+	c.backend.lock.Lock()
+	defer c.backend.lock.Unlock()
+	r, err := c.backend.region(c.region)
+	if err != nil {
+		return nil, err
+	}
+
+	var volume *ec2.Volume
+	for _, v := range r.volumes {
+		if *v.VolumeId == *input.VolumeId {
+			volume = v
+		}
+	}
+	if volume == nil {
+		return nil, awserr.New("InvalidVolume.NotFound", fmt.Sprintf("The volume '%s' does not exist.", *input.VolumeId), nil)
+	}
+	if err := dryRun(input.DryRun); err != nil {
+		return nil, err
+	}
+
+	snapshot := &ec2.Snapshot{
+		SnapshotId:  str(c.backend.id("snap")),
+		OwnerId:     str(c.backend.accountID),
+		VolumeId:    volume.VolumeId,
+		VolumeSize:  volume.Size,
+		Description: input.Description,
+		State:       str(ec2.SnapshotStatePending),
+		StartTime:   timePtr(time.Now()),
+	}
+	for _, spec := range input.TagSpecifications {
+		if strValue(spec.ResourceType) == ec2.ResourceTypeSnapshot {
+			snapshot.Tags = append(snapshot.Tags, spec.Tags...)
+		}
+	}
+	r.snapshots = append(r.snapshots, snapshot)
+	return snapshot, nil
+}
+
+func (c *Client) WaitUntilSnapshotCompletedWithContext(ctx awssdk.Context, input *ec2.DescribeSnapshotsInput, opts ...request.WaiterOption) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	c.backend.lock.Lock()
+	defer c.backend.lock.Unlock()
+	r, err := c.backend.region(c.region)
+	if err != nil {
+		return err
+	}
+
+	found := 0
+	for _, s := range r.snapshots {
+		if contains(input.SnapshotIds, s.SnapshotId) {
+			s.State = str(ec2.SnapshotStateCompleted)
+			found++
+		}
+	}
+	if found < len(input.SnapshotIds) {
+		return awserr.New("InvalidSnapshot.NotFound", "The snapshot does not exist", nil)
+	}
+	return nil
+}
+
+func (c *Client) DescribeSnapshots(input *ec2.DescribeSnapshotsInput) (*ec2.DescribeSnapshotsOutput, error) {
+	c.backend.lock.Lock()
+	defer c.backend.lock.Unlock()
+	r, err := c.backend.region(c.region)
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshots []*ec2.Snapshot
+	for _, s := range r.snapshots {
+		if len(input.SnapshotIds) > 0 && !contains(input.SnapshotIds, s.SnapshotId) {
+			continue
+		}
+		if len(input.OwnerIds) > 0 && !c.ownedBy(input.OwnerIds, s.OwnerId) {
+			continue
+		}
+		snapshots = append(snapshots, s)
+	}
+	if len(input.SnapshotIds) > 0 && len(snapshots) < len(input.SnapshotIds) {
+		return nil, awserr.New("InvalidSnapshot.NotFound", "The snapshot does not exist", nil)
+	}
+
+	start, end, next, err := c.backend.page(len(snapshots), input.NextToken, input.MaxResults)
+	if err != nil {
+		return nil, err
+	}
+	return &ec2.DescribeSnapshotsOutput{
+		Snapshots: append([]*ec2.Snapshot{}, snapshots[start:end]...),
+		NextToken: next,
+	}, nil
+}
+
+func (c *Client) DescribeSnapshotsPages(input *ec2.DescribeSnapshotsInput, fn func(*ec2.DescribeSnapshotsOutput, bool) bool) error {
+	page := *input
+	for {
+		output, err := c.DescribeSnapshots(&page)
+		if err != nil {
+			return err
+		}
+		if !fn(output, output.NextToken == nil) || output.NextToken == nil {
+			return nil
+		}
+		page.NextToken = output.NextToken
+	}
+}
+
+func (c *Client) DeleteSnapshot(input *ec2.DeleteSnapshotInput) (*ec2.DeleteSnapshotOutput, error) {
+	c.backend.lock.Lock()
+	defer c.backend.lock.Unlock()
+	r, err := c.backend.region(c.region)
+	if err != nil {
+		return nil, err
+	}
+
+	index := -1
+	for n, s := range r.snapshots {
+		if *s.SnapshotId == *input.SnapshotId {
+			index = n
+		}
+	}
+	if index < 0 {
+		return nil, awserr.New("InvalidSnapshot.NotFound", fmt.Sprintf("The snapshot '%s' does not exist.", *input.SnapshotId), nil)
+	}
+	for _, image := range r.images {
+		for _, bdm := range image.BlockDeviceMappings {
+			if bdm.Ebs != nil && bdm.Ebs.SnapshotId != nil && *bdm.Ebs.SnapshotId == *input.SnapshotId {
+				return nil, awserr.New("InvalidSnapshot.InUse",
+					fmt.Sprintf("The snapshot %s is currently in use by %s", *input.SnapshotId, *image.ImageId), nil)
+			}
+		}
+	}
+	if err := dryRun(input.DryRun); err != nil {
+		return nil, err
+	}
+
+	r.snapshots = append(r.snapshots[:index], r.snapshots[index+1:]...)
+	return &ec2.DeleteSnapshotOutput{}, nil
+}
+
+func (c *Client) DescribeImages(input *ec2.DescribeImagesInput) (*ec2.DescribeImagesOutput, error) {
+	c.backend.lock.Lock()
+	defer c.backend.lock.Unlock()
+	r, err := c.backend.region(c.region)
+	if err != nil {
+		return nil, err
+	}
+
+	output := &ec2.DescribeImagesOutput{}
+	for _, i := range r.images {
+		if len(input.ImageIds) > 0 && !contains(input.ImageIds, i.ImageId) {
+			continue
+		}
+		if len(input.Owners) > 0 && !c.ownedBy(input.Owners, i.OwnerId) {
+			continue
+		}
+		output.Images = append(output.Images, i)
+	}
+	return output, nil
+}
+
+func (c *Client) DeregisterImage(input *ec2.DeregisterImageInput) (*ec2.DeregisterImageOutput, error) {
+	c.backend.lock.Lock()
+	defer c.backend.lock.Unlock()
+	r, err := c.backend.region(c.region)
+	if err != nil {
+		return nil, err
+	}
+
+	index := -1
+	for n, i := range r.images {
+		if *i.ImageId == *input.ImageId {
+			index = n
+		}
+	}
+	if index < 0 {
+		return nil, awserr.New("InvalidAMIID.NotFound", fmt.Sprintf("The image id '[%s]' does not exist", *input.ImageId), nil)
+	}
+	if err := dryRun(input.DryRun); err != nil {
+		return nil, err
+	}
+
+	r.images = append(r.images[:index], r.images[index+1:]...)
+	return &ec2.DeregisterImageOutput{}, nil
+}
+
+// ownedBy reports whether owner matches one of the owners, where "self" is
+// the backend's account.
+func (c *Client) ownedBy(owners []*string, owner *string) bool {
+	for _, o := range owners {
+		if o == nil || owner == nil {
+			continue
+		}
+		if *o == *owner || (*o == "self" && *owner == c.backend.accountID) {
+			return true
+		}
+	}
+	return false
+}
+
+func str(s string) *string {
+	return &s
+}
+
+func strValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
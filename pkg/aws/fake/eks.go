@@ -0,0 +1,391 @@
+package fake
+
+import (
+	"fmt"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/eks"
+)
+
+// AddEKSCluster adds an active EKS cluster to the region, assigning a name
+// and ARN when they're not set.
+func (b *Backend) AddEKSCluster(regionName string, cluster *eks.Cluster) *eks.Cluster {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if cluster.Name == nil {
+		cluster.Name = str(b.id("cluster"))
+	}
+	if cluster.Arn == nil {
+		cluster.Arn = str(fmt.Sprintf("arn:aws:eks:%s:%s:cluster/%s", regionName, b.accountID, *cluster.Name))
+	}
+	if cluster.Status == nil {
+		cluster.Status = str(eks.ClusterStatusActive)
+	}
+	if cluster.CreatedAt == nil {
+		cluster.CreatedAt = timePtr(time.Now())
+	}
+	r := b.mustRegion(regionName)
+	r.eksClusters = append(r.eksClusters, cluster)
+	return cluster
+}
+
+// AddNodegroup adds an active nodegroup to the region, assigning a name and
+// ARN when they're not set. Its ClusterName must be set to a cluster of the
+// region.
+func (b *Backend) AddNodegroup(regionName string, nodegroup *eks.Nodegroup) *eks.Nodegroup {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	r := b.mustRegion(regionName)
+	if eksCluster(r, strValue(nodegroup.ClusterName)) == nil {
+		panic(fmt.Sprintf("EKS cluster %s not found in %s", strValue(nodegroup.ClusterName), regionName))
+	}
+	if nodegroup.NodegroupName == nil {
+		nodegroup.NodegroupName = str(b.id("nodegroup"))
+	}
+	if nodegroup.NodegroupArn == nil {
+		nodegroup.NodegroupArn = str(fmt.Sprintf("arn:aws:eks:%s:%s:nodegroup/%s/%s/%s",
+			regionName, b.accountID, *nodegroup.ClusterName, *nodegroup.NodegroupName, b.id("id")))
+	}
+	if nodegroup.Status == nil {
+		nodegroup.Status = str(eks.NodegroupStatusActive)
+	}
+	if nodegroup.CreatedAt == nil {
+		nodegroup.CreatedAt = timePtr(time.Now())
+	}
+	r.nodegroups = append(r.nodegroups, nodegroup)
+	return nodegroup
+}
+
+// AddFargateProfile adds an active Fargate profile to the region, assigning
+// a name and ARN when they're not set. Its ClusterName must be set to a
+// cluster of the region.
+func (b *Backend) AddFargateProfile(regionName string, profile *eks.FargateProfile) *eks.FargateProfile {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	r := b.mustRegion(regionName)
+	if eksCluster(r, strValue(profile.ClusterName)) == nil {
+		panic(fmt.Sprintf("EKS cluster %s not found in %s", strValue(profile.ClusterName), regionName))
+	}
+	if profile.FargateProfileName == nil {
+		profile.FargateProfileName = str(b.id("profile"))
+	}
+	if profile.FargateProfileArn == nil {
+		profile.FargateProfileArn = str(fmt.Sprintf("arn:aws:eks:%s:%s:fargateprofile/%s/%s/%s",
+			regionName, b.accountID, *profile.ClusterName, *profile.FargateProfileName, b.id("id")))
+	}
+	if profile.Status == nil {
+		profile.Status = str(eks.FargateProfileStatusActive)
+	}
+	if profile.CreatedAt == nil {
+		profile.CreatedAt = timePtr(time.Now())
+	}
+	r.fargateProfiles = append(r.fargateProfiles, profile)
+	return profile
+}
+
+// EKSClusters returns the EKS clusters in the region.
+func (b *Backend) EKSClusters(regionName string) []*eks.Cluster {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return append([]*eks.Cluster{}, b.mustRegion(regionName).eksClusters...)
+}
+
+// Nodegroups returns the nodegroups of every EKS cluster in the region.
+func (b *Backend) Nodegroups(regionName string) []*eks.Nodegroup {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return append([]*eks.Nodegroup{}, b.mustRegion(regionName).nodegroups...)
+}
+
+// FargateProfiles returns the Fargate profiles of every EKS cluster in the
+// region.
+func (b *Backend) FargateProfiles(regionName string) []*eks.FargateProfile {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return append([]*eks.FargateProfile{}, b.mustRegion(regionName).fargateProfiles...)
+}
+
+func eksCluster(r *region, name string) *eks.Cluster {
+	for _, c := range r.eksClusters {
+		if *c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+func nodegroup(r *region, cluster, name string) (int, *eks.Nodegroup) {
+	for n, ng := range r.nodegroups {
+		if *ng.ClusterName == cluster && *ng.NodegroupName == name {
+			return n, ng
+		}
+	}
+	return -1, nil
+}
+
+func fargateProfile(r *region, cluster, name string) (int, *eks.FargateProfile) {
+	for n, f := range r.fargateProfiles {
+		if *f.ClusterName == cluster && *f.FargateProfileName == name {
+			return n, f
+		}
+	}
+	return -1, nil
+}
+
+func eksNotFound(format string, args ...interface{}) error {
+	return awserr.New(eks.ErrCodeResourceNotFoundException, fmt.Sprintf(format, args...), nil)
+}
+
+func eksInUse(format string, args ...interface{}) error {
+	return awserr.New(eks.ErrCodeResourceInUseException, fmt.Sprintf(format, args...), nil)
+}
+
+// clusterNamed returns the region of the client along with the named
+// cluster.
+func (c *Client) clusterNamed(name *string) (*region, *eks.Cluster, error) {
+	r, err := c.backend.region(c.region)
+	if err != nil {
+		return nil, nil, err
+	}
+	cluster := eksCluster(r, strValue(name))
+	if cluster == nil {
+		return nil, nil, eksNotFound("No cluster found for name: %s.", strValue(name))
+	}
+	return r, cluster, nil
+}
+
+func (c *Client) ListClusters(input *eks.ListClustersInput) (*eks.ListClustersOutput, error) {
+	c.backend.lock.Lock()
+	defer c.backend.lock.Unlock()
+	r, err := c.backend.region(c.region)
+	if err != nil {
+		return nil, err
+	}
+
+	start, end, next, err := c.backend.page(len(r.eksClusters), input.NextToken, input.MaxResults)
+	if err != nil {
+		return nil, err
+	}
+	output := &eks.ListClustersOutput{NextToken: next}
+	for _, cluster := range r.eksClusters[start:end] {
+		output.Clusters = append(output.Clusters, cluster.Name)
+	}
+	return output, nil
+}
+
+func (c *Client) DescribeCluster(input *eks.DescribeClusterInput) (*eks.DescribeClusterOutput, error) {
+	c.backend.lock.Lock()
+	defer c.backend.lock.Unlock()
+	_, cluster, err := c.clusterNamed(input.Name)
+	if err != nil {
+		return nil, err
+	}
+	return &eks.DescribeClusterOutput{Cluster: cluster}, nil
+}
+
+func (c *Client) ListNodegroups(input *eks.ListNodegroupsInput) (*eks.ListNodegroupsOutput, error) {
+	c.backend.lock.Lock()
+	defer c.backend.lock.Unlock()
+	r, cluster, err := c.clusterNamed(input.ClusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []*string
+	for _, ng := range r.nodegroups {
+		if *ng.ClusterName == *cluster.Name {
+			names = append(names, ng.NodegroupName)
+		}
+	}
+	start, end, next, err := c.backend.page(len(names), input.NextToken, input.MaxResults)
+	if err != nil {
+		return nil, err
+	}
+	return &eks.ListNodegroupsOutput{Nodegroups: names[start:end], NextToken: next}, nil
+}
+
+func (c *Client) DescribeNodegroup(input *eks.DescribeNodegroupInput) (*eks.DescribeNodegroupOutput, error) {
+	c.backend.lock.Lock()
+	defer c.backend.lock.Unlock()
+	r, cluster, err := c.clusterNamed(input.ClusterName)
+	if err != nil {
+		return nil, err
+	}
+	_, ng := nodegroup(r, *cluster.Name, strValue(input.NodegroupName))
+	if ng == nil {
+		return nil, eksNotFound("No node group found for name: %s.", strValue(input.NodegroupName))
+	}
+	return &eks.DescribeNodegroupOutput{Nodegroup: ng}, nil
+}
+
+func (c *Client) ListFargateProfiles(input *eks.ListFargateProfilesInput) (*eks.ListFargateProfilesOutput, error) {
+	c.backend.lock.Lock()
+	defer c.backend.lock.Unlock()
+	r, cluster, err := c.clusterNamed(input.ClusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []*string
+	for _, f := range r.fargateProfiles {
+		if *f.ClusterName == *cluster.Name {
+			names = append(names, f.FargateProfileName)
+		}
+	}
+	start, end, next, err := c.backend.page(len(names), input.NextToken, input.MaxResults)
+	if err != nil {
+		return nil, err
+	}
+	return &eks.ListFargateProfilesOutput{FargateProfileNames: names[start:end], NextToken: next}, nil
+}
+
+func (c *Client) DescribeFargateProfile(input *eks.DescribeFargateProfileInput) (*eks.DescribeFargateProfileOutput, error) {
+	c.backend.lock.Lock()
+	defer c.backend.lock.Unlock()
+	r, cluster, err := c.clusterNamed(input.ClusterName)
+	if err != nil {
+		return nil, err
+	}
+	_, f := fargateProfile(r, *cluster.Name, strValue(input.FargateProfileName))
+	if f == nil {
+		return nil, eksNotFound("No Fargate Profile found with name: %s.", strValue(input.FargateProfileName))
+	}
+	return &eks.DescribeFargateProfileOutput{FargateProfile: f}, nil
+}
+
+// DeleteCluster marks the cluster as deleting, failing while it has
+// nodegroups or Fargate profiles. It's removed once waited for.
+func (c *Client) DeleteCluster(input *eks.DeleteClusterInput) (*eks.DeleteClusterOutput, error) {
+	c.backend.lock.Lock()
+	defer c.backend.lock.Unlock()
+	r, cluster, err := c.clusterNamed(input.Name)
+	if err != nil {
+		return nil, err
+	}
+	for _, ng := range r.nodegroups {
+		if *ng.ClusterName == *cluster.Name {
+			return nil, eksInUse("Cluster has nodegroups attached")
+		}
+	}
+	for _, f := range r.fargateProfiles {
+		if *f.ClusterName == *cluster.Name {
+			return nil, eksInUse("Cluster has Fargate profiles attached")
+		}
+	}
+	cluster.Status = str(eks.ClusterStatusDeleting)
+	return &eks.DeleteClusterOutput{Cluster: cluster}, nil
+}
+
+// DeleteNodegroup marks the nodegroup as deleting. It's removed once waited
+// for.
+func (c *Client) DeleteNodegroup(input *eks.DeleteNodegroupInput) (*eks.DeleteNodegroupOutput, error) {
+	c.backend.lock.Lock()
+	defer c.backend.lock.Unlock()
+	r, cluster, err := c.clusterNamed(input.ClusterName)
+	if err != nil {
+		return nil, err
+	}
+	_, ng := nodegroup(r, *cluster.Name, strValue(input.NodegroupName))
+	if ng == nil {
+		return nil, eksNotFound("No node group found for name: %s.", strValue(input.NodegroupName))
+	}
+	ng.Status = str(eks.NodegroupStatusDeleting)
+	return &eks.DeleteNodegroupOutput{Nodegroup: ng}, nil
+}
+
+// DeleteFargateProfile marks the profile as deleting, failing while another
+// profile of the cluster is being deleted. It's removed once waited for.
+func (c *Client) DeleteFargateProfile(input *eks.DeleteFargateProfileInput) (*eks.DeleteFargateProfileOutput, error) {
+	c.backend.lock.Lock()
+	defer c.backend.lock.Unlock()
+	r, cluster, err := c.clusterNamed(input.ClusterName)
+	if err != nil {
+		return nil, err
+	}
+	_, f := fargateProfile(r, *cluster.Name, strValue(input.FargateProfileName))
+	if f == nil {
+		return nil, eksNotFound("No Fargate Profile found with name: %s.", strValue(input.FargateProfileName))
+	}
+	for _, other := range r.fargateProfiles {
+		if *other.ClusterName == *cluster.Name && other != f && strValue(other.Status) == eks.FargateProfileStatusDeleting {
+			return nil, eksInUse("Cannot delete Fargate Profile %s because cluster %s currently has Fargate profile %s in status DELETING",
+				*f.FargateProfileName, *cluster.Name, *other.FargateProfileName)
+		}
+	}
+	f.Status = str(eks.FargateProfileStatusDeleting)
+	return &eks.DeleteFargateProfileOutput{FargateProfile: f}, nil
+}
+
+// waitError is returned by waiters of resources that aren't being deleted,
+// which would never be.
+func waitError(kind, name string) error {
+	return awserr.New(request.WaiterResourceNotReadyErrorCode, fmt.Sprintf("%s %s is not being deleted", kind, name), nil)
+}
+
+func (c *Client) WaitUntilClusterDeletedWithContext(ctx awssdk.Context, input *eks.DescribeClusterInput, opts ...request.WaiterOption) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	c.backend.lock.Lock()
+	defer c.backend.lock.Unlock()
+	r, err := c.backend.region(c.region)
+	if err != nil {
+		return err
+	}
+	for n, cluster := range r.eksClusters {
+		if *cluster.Name != strValue(input.Name) {
+			continue
+		}
+		if strValue(cluster.Status) != eks.ClusterStatusDeleting {
+			return waitError("cluster", *cluster.Name)
+		}
+		r.eksClusters = append(r.eksClusters[:n], r.eksClusters[n+1:]...)
+		return nil
+	}
+	return nil
+}
+
+func (c *Client) WaitUntilNodegroupDeletedWithContext(ctx awssdk.Context, input *eks.DescribeNodegroupInput, opts ...request.WaiterOption) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	c.backend.lock.Lock()
+	defer c.backend.lock.Unlock()
+	r, err := c.backend.region(c.region)
+	if err != nil {
+		return err
+	}
+	n, ng := nodegroup(r, strValue(input.ClusterName), strValue(input.NodegroupName))
+	if ng == nil {
+		return nil
+	}
+	if strValue(ng.Status) != eks.NodegroupStatusDeleting {
+		return waitError("nodegroup", *ng.NodegroupName)
+	}
+	r.nodegroups = append(r.nodegroups[:n], r.nodegroups[n+1:]...)
+	return nil
+}
+
+func (c *Client) WaitUntilFargateProfileDeletedWithContext(ctx awssdk.Context, input *eks.DescribeFargateProfileInput, opts ...request.WaiterOption) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	c.backend.lock.Lock()
+	defer c.backend.lock.Unlock()
+	r, err := c.backend.region(c.region)
+	if err != nil {
+		return err
+	}
+	n, f := fargateProfile(r, strValue(input.ClusterName), strValue(input.FargateProfileName))
+	if f == nil {
+		return nil
+	}
+	if strValue(f.Status) != eks.FargateProfileStatusDeleting {
+		return waitError("Fargate profile", *f.FargateProfileName)
+	}
+	r.fargateProfiles = append(r.fargateProfiles[:n], r.fargateProfiles[n+1:]...)
+	return nil
+}
@@ -0,0 +1,383 @@
+package fake
+
+import (
+	"fmt"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+)
+
+// AddLoadBalancer adds a classic load balancer with the given tags to the
+// region.
+func (b *Backend) AddLoadBalancer(regionName string, lb *elb.LoadBalancerDescription, tags ...*elb.Tag) *elb.LoadBalancerDescription {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if lb.LoadBalancerName == nil {
+		lb.LoadBalancerName = str(b.id("elb"))
+	}
+	if lb.CreatedTime == nil {
+		lb.CreatedTime = timePtr(time.Now())
+	}
+	if lb.DNSName == nil {
+		lb.DNSName = str(fmt.Sprintf("%s.%s.elb.amazonaws.com", *lb.LoadBalancerName, regionName))
+	}
+	r := b.mustRegion(regionName)
+	r.loadBalancers = append(r.loadBalancers, lb)
+	r.loadBalancerTags[*lb.LoadBalancerName] = tags
+	return lb
+}
+
+// AddV2LoadBalancer adds an application or network load balancer with the
+// given tags to the region, assigning an ARN when it's not set.
+func (b *Backend) AddV2LoadBalancer(regionName string, lb *elbv2.LoadBalancer, tags ...*elbv2.Tag) *elbv2.LoadBalancer {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if lb.LoadBalancerName == nil {
+		lb.LoadBalancerName = str(b.id("lb"))
+	}
+	if lb.Type == nil {
+		lb.Type = str(elbv2.LoadBalancerTypeEnumApplication)
+	}
+	if lb.LoadBalancerArn == nil {
+		lb.LoadBalancerArn = str(fmt.Sprintf("arn:aws:elasticloadbalancing:%s:%s:loadbalancer/app/%s/%s",
+			regionName, b.accountID, *lb.LoadBalancerName, b.id("id")))
+	}
+	if lb.State == nil {
+		lb.State = &elbv2.LoadBalancerState{Code: str(elbv2.LoadBalancerStateEnumActive)}
+	}
+	if lb.CreatedTime == nil {
+		lb.CreatedTime = timePtr(time.Now())
+	}
+	r := b.mustRegion(regionName)
+	r.v2 = append(r.v2, lb)
+	r.v2Tags[*lb.LoadBalancerArn] = tags
+	r.v2Attributes[*lb.LoadBalancerArn] = map[string]string{
+		"deletion_protection.enabled": "false",
+	}
+	return lb
+}
+
+// SetV2LoadBalancerAttribute sets an attribute of a v2 load balancer, such
+// as deletion_protection.enabled.
+func (b *Backend) SetV2LoadBalancerAttribute(regionName, arn, key, value string) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.mustRegion(regionName).v2Attributes[arn][key] = value
+}
+
+// AddTargetGroup adds a target group with the given tags to the region,
+// assigning an ARN when it's not set. The target group is used by the load
+// balancers listed in its LoadBalancerArns.
+func (b *Backend) AddTargetGroup(regionName string, tg *elbv2.TargetGroup, tags ...*elbv2.Tag) *elbv2.TargetGroup {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if tg.TargetGroupName == nil {
+		tg.TargetGroupName = str(b.id("tg"))
+	}
+	if tg.TargetGroupArn == nil {
+		tg.TargetGroupArn = str(fmt.Sprintf("arn:aws:elasticloadbalancing:%s:%s:targetgroup/%s/%s",
+			regionName, b.accountID, *tg.TargetGroupName, b.id("id")))
+	}
+	r := b.mustRegion(regionName)
+	r.targetGroups = append(r.targetGroups, tg)
+	r.v2Tags[*tg.TargetGroupArn] = tags
+	return tg
+}
+
+// TargetGroups returns the target groups in the region.
+func (b *Backend) TargetGroups(regionName string) []*elbv2.TargetGroup {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return append([]*elbv2.TargetGroup{}, b.mustRegion(regionName).targetGroups...)
+}
+
+// LoadBalancers returns the classic load balancers in the region.
+func (b *Backend) LoadBalancers(regionName string) []*elb.LoadBalancerDescription {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return append([]*elb.LoadBalancerDescription{}, b.mustRegion(regionName).loadBalancers...)
+}
+
+// V2LoadBalancers returns the v2 load balancers in the region.
+func (b *Backend) V2LoadBalancers(regionName string) []*elbv2.LoadBalancer {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return append([]*elbv2.LoadBalancer{}, b.mustRegion(regionName).v2...)
+}
+
+func (c *Client) DescribeLoadBalancers(input *elb.DescribeLoadBalancersInput) (*elb.DescribeLoadBalancersOutput, error) {
+	c.backend.lock.Lock()
+	defer c.backend.lock.Unlock()
+	r, err := c.backend.region(c.region)
+	if err != nil {
+		return nil, err
+	}
+
+	var lbs []*elb.LoadBalancerDescription
+	for _, lb := range r.loadBalancers {
+		if len(input.LoadBalancerNames) == 0 || contains(input.LoadBalancerNames, lb.LoadBalancerName) {
+			lbs = append(lbs, lb)
+		}
+	}
+	if len(input.LoadBalancerNames) > 0 && len(lbs) < len(input.LoadBalancerNames) {
+		return nil, awserr.New(elb.ErrCodeAccessPointNotFoundException, "Cannot find Load Balancer", nil)
+	}
+
+	start, end, next, err := c.backend.page(len(lbs), input.Marker, input.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	return &elb.DescribeLoadBalancersOutput{
+		LoadBalancerDescriptions: append([]*elb.LoadBalancerDescription{}, lbs[start:end]...),
+		NextMarker:               next,
+	}, nil
+}
+
+func (c *Client) DescribeLoadBalancerTags(input *elb.DescribeTagsInput) (*elb.DescribeTagsOutput, error) {
+	c.backend.lock.Lock()
+	defer c.backend.lock.Unlock()
+	r, err := c.backend.region(c.region)
+	if err != nil {
+		return nil, err
+	}
+	if len(input.LoadBalancerNames) > 20 {
+		return nil, awserr.New("ValidationError", "Member must have length less than or equal to 20", nil)
+	}
+
+	output := &elb.DescribeTagsOutput{}
+	for _, name := range input.LoadBalancerNames {
+		tags, ok := r.loadBalancerTags[strValue(name)]
+		if !ok {
+			return nil, awserr.New(elb.ErrCodeAccessPointNotFoundException,
+				fmt.Sprintf("There is no ACTIVE Load Balancer named '%s'", strValue(name)), nil)
+		}
+		output.TagDescriptions = append(output.TagDescriptions, &elb.TagDescription{
+			LoadBalancerName: name,
+			Tags:             append([]*elb.Tag{}, tags...),
+		})
+	}
+	return output, nil
+}
+
+func (c *Client) DescribeV2LoadBalancerTags(input *elbv2.DescribeTagsInput) (*elbv2.DescribeTagsOutput, error) {
+	c.backend.lock.Lock()
+	defer c.backend.lock.Unlock()
+	r, err := c.backend.region(c.region)
+	if err != nil {
+		return nil, err
+	}
+	if len(input.ResourceArns) > 20 {
+		return nil, awserr.New("ValidationError", "Member must have length less than or equal to 20", nil)
+	}
+
+	output := &elbv2.DescribeTagsOutput{}
+	for _, arn := range input.ResourceArns {
+		tags, ok := r.v2Tags[strValue(arn)]
+		if !ok {
+			return nil, awserr.New(elbv2.ErrCodeLoadBalancerNotFoundException,
+				fmt.Sprintf("Load balancer '%s' not found", strValue(arn)), nil)
+		}
+		output.TagDescriptions = append(output.TagDescriptions, &elbv2.TagDescription{
+			ResourceArn: arn,
+			Tags:        append([]*elbv2.Tag{}, tags...),
+		})
+	}
+	return output, nil
+}
+
+func (c *Client) DescribeV2LoadBalancers(input *elbv2.DescribeLoadBalancersInput) (*elbv2.DescribeLoadBalancersOutput, error) {
+	c.backend.lock.Lock()
+	defer c.backend.lock.Unlock()
+	r, err := c.backend.region(c.region)
+	if err != nil {
+		return nil, err
+	}
+
+	var lbs []*elbv2.LoadBalancer
+	for _, lb := range r.v2 {
+		if len(input.LoadBalancerArns) > 0 && !contains(input.LoadBalancerArns, lb.LoadBalancerArn) {
+			continue
+		}
+		if len(input.Names) > 0 && !contains(input.Names, lb.LoadBalancerName) {
+			continue
+		}
+		lbs = append(lbs, lb)
+	}
+	if len(input.LoadBalancerArns) > 0 && len(lbs) < len(input.LoadBalancerArns) {
+		return nil, awserr.New(elbv2.ErrCodeLoadBalancerNotFoundException, "One or more load balancers not found", nil)
+	}
+
+	start, end, next, err := c.backend.page(len(lbs), input.Marker, input.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	return &elbv2.DescribeLoadBalancersOutput{
+		LoadBalancers: append([]*elbv2.LoadBalancer{}, lbs[start:end]...),
+		NextMarker:    next,
+	}, nil
+}
+
+func (c *Client) DescribeV2LoadBalancerAttributes(input *elbv2.DescribeLoadBalancerAttributesInput) (*elbv2.DescribeLoadBalancerAttributesOutput, error) {
+	c.backend.lock.Lock()
+	defer c.backend.lock.Unlock()
+	r, err := c.backend.region(c.region)
+	if err != nil {
+		return nil, err
+	}
+
+	attributes, ok := r.v2Attributes[strValue(input.LoadBalancerArn)]
+	if !ok {
+		return nil, awserr.New(elbv2.ErrCodeLoadBalancerNotFoundException,
+			fmt.Sprintf("Load balancer '%s' not found", strValue(input.LoadBalancerArn)), nil)
+	}
+	output := &elbv2.DescribeLoadBalancerAttributesOutput{}
+	for k, v := range attributes {
+		output.Attributes = append(output.Attributes, &elbv2.LoadBalancerAttribute{Key: str(k), Value: str(v)})
+	}
+	return output, nil
+}
+
+func (c *Client) DeleteLoadBalancer(input *elb.DeleteLoadBalancerInput) (*elb.DeleteLoadBalancerOutput, error) {
+	c.backend.lock.Lock()
+	defer c.backend.lock.Unlock()
+	r, err := c.backend.region(c.region)
+	if err != nil {
+		return nil, err
+	}
+
+	// Deleting a classic load balancer that doesn't exist succeeds
+	for n, lb := range r.loadBalancers {
+		if *lb.LoadBalancerName == strValue(input.LoadBalancerName) {
+			r.loadBalancers = append(r.loadBalancers[:n], r.loadBalancers[n+1:]...)
+			delete(r.loadBalancerTags, *lb.LoadBalancerName)
+			break
+		}
+	}
+	return &elb.DeleteLoadBalancerOutput{}, nil
+}
+
+// DeleteV2LoadBalancer deletes the load balancer along with its listeners,
+// leaving its target groups unused.
+func (c *Client) DeleteV2LoadBalancer(input *elbv2.DeleteLoadBalancerInput) (*elbv2.DeleteLoadBalancerOutput, error) {
+	c.backend.lock.Lock()
+	defer c.backend.lock.Unlock()
+	r, err := c.backend.region(c.region)
+	if err != nil {
+		return nil, err
+	}
+
+	arn := strValue(input.LoadBalancerArn)
+	index := -1
+	for n, lb := range r.v2 {
+		if *lb.LoadBalancerArn == arn {
+			index = n
+		}
+	}
+	if index < 0 {
+		return nil, awserr.New(elbv2.ErrCodeLoadBalancerNotFoundException, fmt.Sprintf("Load balancer '%s' not found", arn), nil)
+	}
+	if r.v2Attributes[arn]["deletion_protection.enabled"] == "true" {
+		return nil, awserr.New(elbv2.ErrCodeOperationNotPermittedException,
+			fmt.Sprintf("Load balancer '%s' cannot be deleted because deletion protection is enabled", arn), nil)
+	}
+
+	// Target groups stay in use until the deletion is waited for, as
+	// listeners are removed after the load balancer
+	r.v2 = append(r.v2[:index], r.v2[index+1:]...)
+	delete(r.v2Tags, arn)
+	delete(r.v2Attributes, arn)
+	return &elbv2.DeleteLoadBalancerOutput{}, nil
+}
+
+// WaitUntilV2LoadBalancersDeletedWithContext releases the target groups of
+// the deleted load balancers.
+func (c *Client) WaitUntilV2LoadBalancersDeletedWithContext(ctx awssdk.Context, input *elbv2.DescribeLoadBalancersInput, opts ...request.WaiterOption) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	c.backend.lock.Lock()
+	defer c.backend.lock.Unlock()
+	r, err := c.backend.region(c.region)
+	if err != nil {
+		return err
+	}
+
+	for _, lb := range r.v2 {
+		if contains(input.LoadBalancerArns, lb.LoadBalancerArn) {
+			return awserr.New(request.WaiterResourceNotReadyErrorCode, fmt.Sprintf("load balancer %s is not deleted", *lb.LoadBalancerArn), nil)
+		}
+	}
+	for _, tg := range r.targetGroups {
+		var used []*string
+		for _, lb := range tg.LoadBalancerArns {
+			if !contains(input.LoadBalancerArns, lb) {
+				used = append(used, lb)
+			}
+		}
+		tg.LoadBalancerArns = used
+	}
+	return nil
+}
+
+func (c *Client) DescribeTargetGroups(input *elbv2.DescribeTargetGroupsInput) (*elbv2.DescribeTargetGroupsOutput, error) {
+	c.backend.lock.Lock()
+	defer c.backend.lock.Unlock()
+	r, err := c.backend.region(c.region)
+	if err != nil {
+		return nil, err
+	}
+
+	var tgs []*elbv2.TargetGroup
+	for _, tg := range r.targetGroups {
+		if len(input.TargetGroupArns) > 0 && !contains(input.TargetGroupArns, tg.TargetGroupArn) {
+			continue
+		}
+		if len(input.Names) > 0 && !contains(input.Names, tg.TargetGroupName) {
+			continue
+		}
+		if input.LoadBalancerArn != nil && !contains(tg.LoadBalancerArns, input.LoadBalancerArn) {
+			continue
+		}
+		tgs = append(tgs, tg)
+	}
+	if len(input.TargetGroupArns) > 0 && len(tgs) < len(input.TargetGroupArns) {
+		return nil, awserr.New(elbv2.ErrCodeTargetGroupNotFoundException, "One or more target groups not found", nil)
+	}
+
+	start, end, next, err := c.backend.page(len(tgs), input.Marker, input.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	return &elbv2.DescribeTargetGroupsOutput{
+		TargetGroups: append([]*elbv2.TargetGroup{}, tgs[start:end]...),
+		NextMarker:   next,
+	}, nil
+}
+
+func (c *Client) DeleteTargetGroup(input *elbv2.DeleteTargetGroupInput) (*elbv2.DeleteTargetGroupOutput, error) {
+	c.backend.lock.Lock()
+	defer c.backend.lock.Unlock()
+	r, err := c.backend.region(c.region)
+	if err != nil {
+		return nil, err
+	}
+
+	// Deleting a target group that doesn't exist succeeds
+	for n, tg := range r.targetGroups {
+		if *tg.TargetGroupArn != strValue(input.TargetGroupArn) {
+			continue
+		}
+		if len(tg.LoadBalancerArns) > 0 {
+			return nil, awserr.New(elbv2.ErrCodeResourceInUseException,
+				fmt.Sprintf("Target group '%s' is currently in use by a listener or a rule", *tg.TargetGroupArn), nil)
+		}
+		r.targetGroups = append(r.targetGroups[:n], r.targetGroups[n+1:]...)
+		delete(r.v2Tags, *tg.TargetGroupArn)
+		break
+	}
+	return &elbv2.DeleteTargetGroupOutput{}, nil
+}
@@ -0,0 +1,241 @@
+// Package fake provides an in-memory implementation of aws.Client, used to
+// exercise commands without reaching AWS.
+package fake
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/eks"
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/organizations"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/jharrington22/aws-resource/pkg/aws"
+)
+
+const (
+	// DefaultAccountID is the account ID of a new backend.
+	DefaultAccountID = "123456789012"
+
+	// DefaultPageSize is the number of items returned per page by a new
+	// backend, small enough for tests to cross page boundaries.
+	DefaultPageSize = 2
+)
+
+// Backend is an in-memory AWS account. Every client created from a backend
+// shares its state, and all methods are safe for concurrent use.
+type Backend struct {
+	// PageSize is the maximum number of items returned by paginated calls.
+	PageSize int
+
+	lock      sync.Mutex
+	accountID string
+	arn       string
+	regions   map[string]*region
+	failures  map[string]error
+	zones     []*route53.HostedZone
+	accounts  []*organizations.Account
+	nextID    int
+
+	// Record sets and tags of hosted zones are keyed by zone ID, without
+	// the /hostedzone/ prefix.
+	records  map[string][]*route53.ResourceRecordSet
+	zoneTags map[string][]*route53.Tag
+
+	// Buckets of every region, as their names are global
+	buckets []*bucket
+}
+
+// region holds the state of a single region.
+type region struct {
+	instances     []*ec2.Instance
+	volumes       []*ec2.Volume
+	snapshots     []*ec2.Snapshot
+	images        []*ec2.Image
+	natGateways   []*ec2.NatGateway
+	addresses     []*ec2.Address
+	loadBalancers []*elb.LoadBalancerDescription
+	v2            []*elbv2.LoadBalancer
+	targetGroups  []*elbv2.TargetGroup
+	dbInstances   []*rds.DBInstance
+	dbClusters    []*rds.DBCluster
+
+	// EKS clusters, and the nodegroups and Fargate profiles of every cluster
+	eksClusters     []*eks.Cluster
+	nodegroups      []*eks.Nodegroup
+	fargateProfiles []*eks.FargateProfile
+
+	stacks []*cloudformation.Stack
+
+	// Final snapshots taken when deleting DB instances and clusters
+	dbSnapshots        []*rds.DBSnapshot
+	dbClusterSnapshots []*rds.DBClusterSnapshot
+
+	// Load balancer tags are keyed by name for classic load balancers and
+	// by ARN for v2 load balancers and target groups.
+	loadBalancerTags map[string][]*elb.Tag
+	v2Tags           map[string][]*elbv2.Tag
+
+	// v2Attributes holds the attributes of v2 load balancers by ARN.
+	v2Attributes map[string]map[string]string
+}
+
+// New creates a backend with the given regions enabled.
+func New(regions ...string) *Backend {
+	b := &Backend{
+		PageSize:  DefaultPageSize,
+		accountID: DefaultAccountID,
+		arn:       fmt.Sprintf("arn:aws:iam::%s:user/fake", DefaultAccountID),
+		regions:   map[string]*region{},
+		failures:  map[string]error{},
+		records:   map[string][]*route53.ResourceRecordSet{},
+		zoneTags:  map[string][]*route53.Tag{},
+	}
+	for _, r := range regions {
+		b.regions[r] = &region{
+			loadBalancerTags: map[string][]*elb.Tag{},
+			v2Tags:           map[string][]*elbv2.Tag{},
+			v2Attributes:     map[string]map[string]string{},
+		}
+	}
+	return b
+}
+
+// SetIdentity sets the account and caller ARN returned by GetCallerIdentity.
+func (b *Backend) SetIdentity(accountID, arn string) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.accountID = accountID
+	b.arn = arn
+}
+
+// Fail makes every call made by clients of the region return err. A nil err
+// clears the failure.
+func (b *Backend) Fail(region string, err error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if err == nil {
+		delete(b.failures, region)
+		return
+	}
+	b.failures[region] = err
+}
+
+// Client returns a client bound to the region.
+func (b *Backend) Client(region string) *Client {
+	return &Client{backend: b, region: region}
+}
+
+// Clients returns a ClientFunc creating clients of the backend.
+func (b *Backend) Clients() aws.ClientFunc {
+	return func(region string) (aws.Client, error) {
+		return b.Client(region), nil
+	}
+}
+
+// Factory returns an aws.Factory whose clients all use the backend,
+// whatever the target.
+func (b *Backend) Factory() aws.Factory {
+	return backendFactory{backend: b}
+}
+
+type backendFactory struct {
+	backend *Backend
+}
+
+func (f backendFactory) Client(target aws.Target, region string) (aws.Client, error) {
+	return f.backend.Client(region), nil
+}
+
+// Regions returns the names of the enabled regions in alphabetical order.
+func (b *Backend) Regions() []string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.regionNames()
+}
+
+func (b *Backend) regionNames() []string {
+	var names []string
+	for name := range b.regions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// id returns a new resource ID with the given prefix. EC2 IDs have 17 hex
+// digits after the prefix.
+func (b *Backend) id(prefix string) string {
+	b.nextID++
+	return fmt.Sprintf("%s-%017x", prefix, b.nextID)
+}
+
+// region returns the state of the named region, or an error matching the
+// one returned by AWS for unknown regions.
+func (b *Backend) region(name string) (*region, error) {
+	if err, ok := b.failures[name]; ok {
+		return nil, err
+	}
+	r, ok := b.regions[name]
+	if !ok {
+		return nil, awserr.New("UnrecognizedClientException", fmt.Sprintf("region %s is not enabled", name), nil)
+	}
+	return r, nil
+}
+
+// page returns the bounds of the page of n items starting at token along
+// with the token of the following page, which is nil on the last page.
+func (b *Backend) page(n int, token *string, max *int64) (int, int, *string, error) {
+	start := 0
+	if token != nil && *token != "" {
+		var err error
+		start, err = strconv.Atoi(*token)
+		if err != nil || start < 0 || start > n {
+			return 0, 0, nil, awserr.New("InvalidNextToken", fmt.Sprintf("invalid token %q", *token), nil)
+		}
+	}
+
+	size := b.PageSize
+	if max != nil && (size <= 0 || int(*max) < size) {
+		size = int(*max)
+	}
+	if size <= 0 || start+size >= n {
+		return start, n, nil, nil
+	}
+	next := strconv.Itoa(start + size)
+	return start, start + size, &next, nil
+}
+
+func contains(values []*string, value *string) bool {
+	if value == nil {
+		return false
+	}
+	for _, v := range values {
+		if v != nil && *v == *value {
+			return true
+		}
+	}
+	return false
+}
+
+func dryRun(value *bool) error {
+	if value != nil && *value {
+		return awserr.New("DryRunOperation", "Request would have succeeded, but DryRun flag is set.", nil)
+	}
+	return nil
+}
+
+// Client is an aws.Client bound to a single region of a backend.
+type Client struct {
+	backend *Backend
+	region  string
+}
+
+var _ aws.Client = &Client{}
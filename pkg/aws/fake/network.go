@@ -0,0 +1,190 @@
+package fake
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// AddNatGateway adds an available NAT gateway to the region, assigning an
+// ID when it's not set. The Elastic IPs of its addresses are associated with
+// the gateway until it's deleted.
+func (b *Backend) AddNatGateway(regionName string, ng *ec2.NatGateway) *ec2.NatGateway {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if ng.NatGatewayId == nil {
+		ng.NatGatewayId = str(b.id("nat"))
+	}
+	if ng.State == nil {
+		ng.State = str(ec2.NatGatewayStateAvailable)
+	}
+	if ng.ConnectivityType == nil {
+		ng.ConnectivityType = str(ec2.ConnectivityTypePublic)
+	}
+	if ng.CreateTime == nil {
+		ng.CreateTime = timePtr(time.Now())
+	}
+	r := b.mustRegion(regionName)
+	for _, a := range ng.NatGatewayAddresses {
+		for _, address := range r.addresses {
+			if strValue(address.AllocationId) == strValue(a.AllocationId) {
+				if a.NetworkInterfaceId == nil {
+					a.NetworkInterfaceId = str(b.id("eni"))
+				}
+				a.PublicIp = address.PublicIp
+				address.AssociationId = str(b.id("eipassoc"))
+				address.NetworkInterfaceId = a.NetworkInterfaceId
+			}
+		}
+	}
+	r.natGateways = append(r.natGateways, ng)
+	return ng
+}
+
+// AddAddress allocates an Elastic IP in the region, assigning an allocation
+// ID and a public IP when they're not set.
+func (b *Backend) AddAddress(regionName string, address *ec2.Address) *ec2.Address {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if address.AllocationId == nil {
+		address.AllocationId = str(b.id("eipalloc"))
+	}
+	if address.PublicIp == nil {
+		b.nextID++
+		address.PublicIp = str(fmt.Sprintf("198.51.100.%d", b.nextID%256))
+	}
+	if address.Domain == nil {
+		address.Domain = str(ec2.DomainTypeVpc)
+	}
+	if address.InstanceId != nil && address.AssociationId == nil {
+		address.AssociationId = str(b.id("eipassoc"))
+	}
+	r := b.mustRegion(regionName)
+	r.addresses = append(r.addresses, address)
+	return address
+}
+
+// NatGateways returns the NAT gateways in the region, including deleted ones.
+func (b *Backend) NatGateways(regionName string) []*ec2.NatGateway {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return append([]*ec2.NatGateway{}, b.mustRegion(regionName).natGateways...)
+}
+
+// Addresses returns the Elastic IPs allocated in the region.
+func (b *Backend) Addresses(regionName string) []*ec2.Address {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return append([]*ec2.Address{}, b.mustRegion(regionName).addresses...)
+}
+
+func (c *Client) DescribeNatGateways(input *ec2.DescribeNatGatewaysInput) (*ec2.DescribeNatGatewaysOutput, error) {
+	c.backend.lock.Lock()
+	defer c.backend.lock.Unlock()
+	r, err := c.backend.region(c.region)
+	if err != nil {
+		return nil, err
+	}
+
+	var ngs []*ec2.NatGateway
+	for _, ng := range r.natGateways {
+		if len(input.NatGatewayIds) == 0 || contains(input.NatGatewayIds, ng.NatGatewayId) {
+			ngs = append(ngs, ng)
+		}
+	}
+	if len(input.NatGatewayIds) > 0 && len(ngs) < len(input.NatGatewayIds) {
+		return nil, awserr.New("NatGatewayNotFound", "The Nat Gateway does not exist", nil)
+	}
+
+	start, end, next, err := c.backend.page(len(ngs), input.NextToken, input.MaxResults)
+	if err != nil {
+		return nil, err
+	}
+	return &ec2.DescribeNatGatewaysOutput{
+		NatGateways: append([]*ec2.NatGateway{}, ngs[start:end]...),
+		NextToken:   next,
+	}, nil
+}
+
+// DeleteNatGateway deletes the NAT gateway at once, rather than after a few
+// minutes in the deleting state, disassociating its Elastic IPs.
+func (c *Client) DeleteNatGateway(input *ec2.DeleteNatGatewayInput) (*ec2.DeleteNatGatewayOutput, error) {
+	c.backend.lock.Lock()
+	defer c.backend.lock.Unlock()
+	r, err := c.backend.region(c.region)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ng := range r.natGateways {
+		if *ng.NatGatewayId != strValue(input.NatGatewayId) {
+			continue
+		}
+		if err := dryRun(input.DryRun); err != nil {
+			return nil, err
+		}
+		ng.State = str(ec2.NatGatewayStateDeleted)
+		for _, a := range ng.NatGatewayAddresses {
+			for _, address := range r.addresses {
+				if strValue(address.AllocationId) == strValue(a.AllocationId) {
+					address.AssociationId = nil
+					address.NetworkInterfaceId = nil
+				}
+			}
+		}
+		return &ec2.DeleteNatGatewayOutput{NatGatewayId: ng.NatGatewayId}, nil
+	}
+	return nil, awserr.New("NatGatewayNotFound", fmt.Sprintf("The Nat Gateway %s was not found", strValue(input.NatGatewayId)), nil)
+}
+
+func (c *Client) DescribeAddresses(input *ec2.DescribeAddressesInput) (*ec2.DescribeAddressesOutput, error) {
+	c.backend.lock.Lock()
+	defer c.backend.lock.Unlock()
+	r, err := c.backend.region(c.region)
+	if err != nil {
+		return nil, err
+	}
+
+	var addresses []*ec2.Address
+	for _, a := range r.addresses {
+		if len(input.AllocationIds) > 0 && !contains(input.AllocationIds, a.AllocationId) {
+			continue
+		}
+		if len(input.PublicIps) > 0 && !contains(input.PublicIps, a.PublicIp) {
+			continue
+		}
+		addresses = append(addresses, a)
+	}
+	if len(input.AllocationIds) > 0 && len(addresses) < len(input.AllocationIds) {
+		return nil, awserr.New("InvalidAllocationID.NotFound", "The allocation ID does not exist", nil)
+	}
+	return &ec2.DescribeAddressesOutput{Addresses: addresses}, nil
+}
+
+func (c *Client) ReleaseAddress(input *ec2.ReleaseAddressInput) (*ec2.ReleaseAddressOutput, error) {
+	c.backend.lock.Lock()
+	defer c.backend.lock.Unlock()
+	r, err := c.backend.region(c.region)
+	if err != nil {
+		return nil, err
+	}
+
+	for n, a := range r.addresses {
+		if strValue(a.AllocationId) != strValue(input.AllocationId) {
+			continue
+		}
+		if a.AssociationId != nil {
+			return nil, awserr.New("InvalidIPAddress.InUse",
+				fmt.Sprintf("Address %s is in use", strValue(a.PublicIp)), nil)
+		}
+		if err := dryRun(input.DryRun); err != nil {
+			return nil, err
+		}
+		r.addresses = append(r.addresses[:n], r.addresses[n+1:]...)
+		return &ec2.ReleaseAddressOutput{}, nil
+	}
+	return nil, awserr.New("InvalidAllocationID.NotFound",
+		fmt.Sprintf("The allocation ID '%s' does not exist", strValue(input.AllocationId)), nil)
+}
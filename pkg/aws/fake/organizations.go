@@ -0,0 +1,129 @@
+package fake
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/organizations"
+	"github.com/jharrington22/aws-resource/pkg/aws"
+)
+
+// DefaultMemberRole is the role an organization lets its management account
+// assume in member accounts.
+const DefaultMemberRole = "OrganizationAccountAccessRole"
+
+// AddAccount adds an account to the organization the backend's account
+// manages, returned by ListAccounts. The status defaults to ACTIVE.
+func (b *Backend) AddAccount(account *organizations.Account) *organizations.Account {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if account.Id == nil {
+		account.Id = str(fmt.Sprintf("%012d", 100000000000+len(b.accounts)))
+	}
+	if account.Name == nil {
+		account.Name = str("account-" + *account.Id)
+	}
+	if account.Status == nil {
+		account.Status = str(organizations.AccountStatusActive)
+	}
+	b.accounts = append(b.accounts, account)
+	return account
+}
+
+func (c *Client) ListAccountsPages(input *organizations.ListAccountsInput, fn func(*organizations.ListAccountsOutput, bool) bool) error {
+	c.backend.lock.Lock()
+	if _, err := c.backend.region(c.region); err != nil {
+		c.backend.lock.Unlock()
+		return err
+	}
+	if len(c.backend.accounts) == 0 {
+		c.backend.lock.Unlock()
+		return awserr.New(organizations.ErrCodeAWSOrganizationsNotInUseException,
+			"Your account is not a member of an organization.", nil)
+	}
+	accounts := append([]*organizations.Account{}, c.backend.accounts...)
+	c.backend.lock.Unlock()
+
+	var token *string
+	for {
+		start, end, next, err := c.backend.page(len(accounts), token, input.MaxResults)
+		if err != nil {
+			return err
+		}
+		output := &organizations.ListAccountsOutput{
+			Accounts:  accounts[start:end],
+			NextToken: next,
+		}
+		if !fn(output, next == nil) || next == nil {
+			return nil
+		}
+		token = next
+	}
+}
+
+// Organization routes the clients of targets assuming a role in a member
+// account to the backend of that account, and the clients of any other
+// target to the backend of the management account.
+type Organization struct {
+	// Role is the only role name that can be assumed in member accounts.
+	Role string
+
+	management *Backend
+	lock       sync.Mutex
+	members    map[string]*Backend
+}
+
+// NewOrganization creates an organization managed by the account of the
+// backend.
+func NewOrganization(management *Backend) *Organization {
+	management.lock.Lock()
+	accountID := management.accountID
+	management.lock.Unlock()
+	management.AddAccount(&organizations.Account{Id: str(accountID), Name: str("management")})
+
+	return &Organization{
+		Role:       DefaultMemberRole,
+		management: management,
+		members:    map[string]*Backend{},
+	}
+}
+
+// AddMember adds an account to the organization whose resources are held by
+// backend, setting the identity of the backend to the account. A nil
+// backend adds an account whose role can't be assumed.
+func (o *Organization) AddMember(accountID, name string, backend *Backend) {
+	o.management.AddAccount(&organizations.Account{Id: str(accountID), Name: str(name)})
+	if backend == nil {
+		return
+	}
+	backend.SetIdentity(accountID, fmt.Sprintf("arn:aws:sts::%s:assumed-role/%s/fake", accountID, o.Role))
+
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	o.members[accountID] = backend
+}
+
+// Factory returns an aws.Factory creating clients of the organization.
+func (o *Organization) Factory() aws.Factory {
+	return o
+}
+
+func (o *Organization) Client(target aws.Target, region string) (aws.Client, error) {
+	if target.RoleArn == "" {
+		return o.management.Client(region), nil
+	}
+
+	// arn:aws:iam::<account>:role/<name>
+	parts := strings.SplitN(target.RoleArn, ":", 6)
+	if len(parts) == 6 && parts[5] == "role/"+o.Role {
+		o.lock.Lock()
+		member, ok := o.members[parts[4]]
+		o.lock.Unlock()
+		if ok {
+			return member.Client(region), nil
+		}
+	}
+	return nil, awserr.New("AccessDenied", fmt.Sprintf("not authorized to perform sts:AssumeRole on resource %s", target.RoleArn), nil)
+}
@@ -0,0 +1,341 @@
+package fake
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/rds"
+)
+
+// AddDBInstance adds an available DB instance to the region, assigning an
+// identifier and ARN when they're not set. Instances with a
+// DBClusterIdentifier are added to the members of that cluster, which must
+// already exist.
+func (b *Backend) AddDBInstance(regionName string, instance *rds.DBInstance) *rds.DBInstance {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if instance.DBInstanceIdentifier == nil {
+		instance.DBInstanceIdentifier = str(b.id("database"))
+	}
+	if instance.DBInstanceArn == nil {
+		instance.DBInstanceArn = str(fmt.Sprintf("arn:aws:rds:%s:%s:db:%s", regionName, b.accountID, *instance.DBInstanceIdentifier))
+	}
+	if instance.DBInstanceStatus == nil {
+		instance.DBInstanceStatus = str("available")
+	}
+	if instance.InstanceCreateTime == nil {
+		instance.InstanceCreateTime = timePtr(time.Now())
+	}
+	r := b.mustRegion(regionName)
+	if instance.DBClusterIdentifier != nil {
+		cluster := dbCluster(r, *instance.DBClusterIdentifier)
+		if cluster == nil {
+			panic(fmt.Sprintf("DB cluster %s not found in %s", *instance.DBClusterIdentifier, regionName))
+		}
+		cluster.DBClusterMembers = append(cluster.DBClusterMembers, &rds.DBClusterMember{
+			DBInstanceIdentifier: instance.DBInstanceIdentifier,
+		})
+	}
+	r.dbInstances = append(r.dbInstances, instance)
+	return instance
+}
+
+// AddDBCluster adds an available DB cluster without instances to the region,
+// assigning an identifier and ARN when they're not set.
+func (b *Backend) AddDBCluster(regionName string, cluster *rds.DBCluster) *rds.DBCluster {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if cluster.DBClusterIdentifier == nil {
+		cluster.DBClusterIdentifier = str(b.id("cluster"))
+	}
+	if cluster.DBClusterArn == nil {
+		cluster.DBClusterArn = str(fmt.Sprintf("arn:aws:rds:%s:%s:cluster:%s", regionName, b.accountID, *cluster.DBClusterIdentifier))
+	}
+	if cluster.Status == nil {
+		cluster.Status = str("available")
+	}
+	if cluster.ClusterCreateTime == nil {
+		cluster.ClusterCreateTime = timePtr(time.Now())
+	}
+	r := b.mustRegion(regionName)
+	r.dbClusters = append(r.dbClusters, cluster)
+	return cluster
+}
+
+// DBInstances returns the DB instances in the region.
+func (b *Backend) DBInstances(regionName string) []*rds.DBInstance {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return append([]*rds.DBInstance{}, b.mustRegion(regionName).dbInstances...)
+}
+
+// DBClusters returns the DB clusters in the region.
+func (b *Backend) DBClusters(regionName string) []*rds.DBCluster {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return append([]*rds.DBCluster{}, b.mustRegion(regionName).dbClusters...)
+}
+
+// DBSnapshots returns the final snapshots taken when deleting DB instances
+// in the region.
+func (b *Backend) DBSnapshots(regionName string) []*rds.DBSnapshot {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return append([]*rds.DBSnapshot{}, b.mustRegion(regionName).dbSnapshots...)
+}
+
+// DBClusterSnapshots returns the final snapshots taken when deleting DB
+// clusters in the region.
+func (b *Backend) DBClusterSnapshots(regionName string) []*rds.DBClusterSnapshot {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return append([]*rds.DBClusterSnapshot{}, b.mustRegion(regionName).dbClusterSnapshots...)
+}
+
+// dbIdentifier returns the identifier of a DB instance or cluster given by
+// identifier or ARN.
+func dbIdentifier(id string) string {
+	return id[strings.LastIndex(id, ":")+1:]
+}
+
+func dbInstance(r *region, id string) (int, *rds.DBInstance) {
+	for n, instance := range r.dbInstances {
+		if *instance.DBInstanceIdentifier == dbIdentifier(id) {
+			return n, instance
+		}
+	}
+	return -1, nil
+}
+
+func dbCluster(r *region, id string) *rds.DBCluster {
+	for _, cluster := range r.dbClusters {
+		if *cluster.DBClusterIdentifier == dbIdentifier(id) {
+			return cluster
+		}
+	}
+	return nil
+}
+
+func dbInstanceNotFound(id string) error {
+	return awserr.New(rds.ErrCodeDBInstanceNotFoundFault, fmt.Sprintf("DBInstance %s not found.", dbIdentifier(id)), nil)
+}
+
+func dbClusterNotFound(id string) error {
+	return awserr.New(rds.ErrCodeDBClusterNotFoundFault, fmt.Sprintf("DBCluster %s not found.", dbIdentifier(id)), nil)
+}
+
+// finalSnapshot returns the error returned when the final snapshot options
+// of a deletion are invalid.
+func finalSnapshot(skip *bool, identifier *string) error {
+	switch {
+	case skip != nil && *skip && identifier != nil:
+		return awserr.New("InvalidParameterCombination", "FinalDBSnapshotIdentifier can not be specified when deleting with SkipFinalSnapshot.", nil)
+	case (skip == nil || !*skip) && identifier == nil:
+		return awserr.New("InvalidParameterCombination", "FinalDBSnapshotIdentifier is required unless SkipFinalSnapshot is specified.", nil)
+	}
+	return nil
+}
+
+func (c *Client) DescribeDBInstances(input *rds.DescribeDBInstancesInput) (*rds.DescribeDBInstancesOutput, error) {
+	c.backend.lock.Lock()
+	defer c.backend.lock.Unlock()
+	r, err := c.backend.region(c.region)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the db-cluster-id filter is supported
+	var clusters []*string
+	for _, f := range input.Filters {
+		if strValue(f.Name) == "db-cluster-id" {
+			for _, v := range f.Values {
+				clusters = append(clusters, str(dbIdentifier(strValue(v))))
+			}
+		}
+	}
+
+	var instances []*rds.DBInstance
+	for _, instance := range r.dbInstances {
+		if input.DBInstanceIdentifier != nil && *instance.DBInstanceIdentifier != dbIdentifier(*input.DBInstanceIdentifier) {
+			continue
+		}
+		if clusters != nil && !contains(clusters, instance.DBClusterIdentifier) {
+			continue
+		}
+		instances = append(instances, instance)
+	}
+	if input.DBInstanceIdentifier != nil && len(instances) == 0 {
+		return nil, dbInstanceNotFound(*input.DBInstanceIdentifier)
+	}
+
+	start, end, next, err := c.backend.page(len(instances), input.Marker, input.MaxRecords)
+	if err != nil {
+		return nil, err
+	}
+	return &rds.DescribeDBInstancesOutput{
+		DBInstances: append([]*rds.DBInstance{}, instances[start:end]...),
+		Marker:      next,
+	}, nil
+}
+
+func (c *Client) DescribeDBClusters(input *rds.DescribeDBClustersInput) (*rds.DescribeDBClustersOutput, error) {
+	c.backend.lock.Lock()
+	defer c.backend.lock.Unlock()
+	r, err := c.backend.region(c.region)
+	if err != nil {
+		return nil, err
+	}
+
+	var clusters []*rds.DBCluster
+	for _, cluster := range r.dbClusters {
+		if input.DBClusterIdentifier == nil || *cluster.DBClusterIdentifier == dbIdentifier(*input.DBClusterIdentifier) {
+			clusters = append(clusters, cluster)
+		}
+	}
+	if input.DBClusterIdentifier != nil && len(clusters) == 0 {
+		return nil, dbClusterNotFound(*input.DBClusterIdentifier)
+	}
+
+	start, end, next, err := c.backend.page(len(clusters), input.Marker, input.MaxRecords)
+	if err != nil {
+		return nil, err
+	}
+	return &rds.DescribeDBClustersOutput{
+		DBClusters: append([]*rds.DBCluster{}, clusters[start:end]...),
+		Marker:     next,
+	}, nil
+}
+
+// ModifyDBInstance only supports changing deletion protection.
+func (c *Client) ModifyDBInstance(input *rds.ModifyDBInstanceInput) (*rds.ModifyDBInstanceOutput, error) {
+	c.backend.lock.Lock()
+	defer c.backend.lock.Unlock()
+	r, err := c.backend.region(c.region)
+	if err != nil {
+		return nil, err
+	}
+
+	_, instance := dbInstance(r, strValue(input.DBInstanceIdentifier))
+	if instance == nil {
+		return nil, dbInstanceNotFound(strValue(input.DBInstanceIdentifier))
+	}
+	if input.DeletionProtection != nil {
+		instance.DeletionProtection = input.DeletionProtection
+	}
+	return &rds.ModifyDBInstanceOutput{DBInstance: instance}, nil
+}
+
+// ModifyDBCluster only supports changing deletion protection.
+func (c *Client) ModifyDBCluster(input *rds.ModifyDBClusterInput) (*rds.ModifyDBClusterOutput, error) {
+	c.backend.lock.Lock()
+	defer c.backend.lock.Unlock()
+	r, err := c.backend.region(c.region)
+	if err != nil {
+		return nil, err
+	}
+
+	cluster := dbCluster(r, strValue(input.DBClusterIdentifier))
+	if cluster == nil {
+		return nil, dbClusterNotFound(strValue(input.DBClusterIdentifier))
+	}
+	if input.DeletionProtection != nil {
+		cluster.DeletionProtection = input.DeletionProtection
+	}
+	return &rds.ModifyDBClusterOutput{DBCluster: cluster}, nil
+}
+
+// DeleteDBInstance deletes the instance at once, rather than after several
+// minutes in the deleting state, taking the final snapshot requested. The
+// final snapshot of instances in a cluster is taken when deleting the
+// cluster.
+func (c *Client) DeleteDBInstance(input *rds.DeleteDBInstanceInput) (*rds.DeleteDBInstanceOutput, error) {
+	c.backend.lock.Lock()
+	defer c.backend.lock.Unlock()
+	r, err := c.backend.region(c.region)
+	if err != nil {
+		return nil, err
+	}
+
+	n, instance := dbInstance(r, strValue(input.DBInstanceIdentifier))
+	if instance == nil {
+		return nil, dbInstanceNotFound(strValue(input.DBInstanceIdentifier))
+	}
+	if instance.DeletionProtection != nil && *instance.DeletionProtection {
+		return nil, awserr.New("InvalidParameterCombination", "Cannot delete protected DB Instance, please disable deletion protection and try again.", nil)
+	}
+
+	if instance.DBClusterIdentifier != nil {
+		if input.FinalDBSnapshotIdentifier != nil {
+			return nil, awserr.New("InvalidParameterCombination", "FinalDBSnapshotIdentifier can not be specified when deleting a cluster instance.", nil)
+		}
+		if cluster := dbCluster(r, *instance.DBClusterIdentifier); cluster != nil {
+			var members []*rds.DBClusterMember
+			for _, m := range cluster.DBClusterMembers {
+				if strValue(m.DBInstanceIdentifier) != *instance.DBInstanceIdentifier {
+					members = append(members, m)
+				}
+			}
+			cluster.DBClusterMembers = members
+		}
+	} else {
+		if err := finalSnapshot(input.SkipFinalSnapshot, input.FinalDBSnapshotIdentifier); err != nil {
+			return nil, err
+		}
+		if input.FinalDBSnapshotIdentifier != nil {
+			r.dbSnapshots = append(r.dbSnapshots, &rds.DBSnapshot{
+				DBSnapshotIdentifier: input.FinalDBSnapshotIdentifier,
+				DBInstanceIdentifier: instance.DBInstanceIdentifier,
+				SnapshotCreateTime:   timePtr(time.Now()),
+				Status:               str("available"),
+			})
+		}
+	}
+
+	r.dbInstances = append(r.dbInstances[:n], r.dbInstances[n+1:]...)
+	instance.DBInstanceStatus = str("deleting")
+	return &rds.DeleteDBInstanceOutput{DBInstance: instance}, nil
+}
+
+// DeleteDBCluster deletes the cluster at once, taking the final snapshot
+// requested. Clusters fail to delete while they have instances.
+func (c *Client) DeleteDBCluster(input *rds.DeleteDBClusterInput) (*rds.DeleteDBClusterOutput, error) {
+	c.backend.lock.Lock()
+	defer c.backend.lock.Unlock()
+	r, err := c.backend.region(c.region)
+	if err != nil {
+		return nil, err
+	}
+
+	cluster := dbCluster(r, strValue(input.DBClusterIdentifier))
+	if cluster == nil {
+		return nil, dbClusterNotFound(strValue(input.DBClusterIdentifier))
+	}
+	if cluster.DeletionProtection != nil && *cluster.DeletionProtection {
+		return nil, awserr.New("InvalidParameterCombination", "Cannot delete protected Cluster, please disable deletion protection and try again.", nil)
+	}
+	if len(cluster.DBClusterMembers) > 0 {
+		return nil, awserr.New(rds.ErrCodeInvalidDBClusterStateFault, "Cluster cannot be deleted, it still contains DB instances in non-deleting state.", nil)
+	}
+	if err := finalSnapshot(input.SkipFinalSnapshot, input.FinalDBSnapshotIdentifier); err != nil {
+		return nil, err
+	}
+	if input.FinalDBSnapshotIdentifier != nil {
+		r.dbClusterSnapshots = append(r.dbClusterSnapshots, &rds.DBClusterSnapshot{
+			DBClusterSnapshotIdentifier: input.FinalDBSnapshotIdentifier,
+			DBClusterIdentifier:         cluster.DBClusterIdentifier,
+			SnapshotCreateTime:          timePtr(time.Now()),
+			Status:                      str("available"),
+		})
+	}
+
+	for n, c := range r.dbClusters {
+		if c == cluster {
+			r.dbClusters = append(r.dbClusters[:n], r.dbClusters[n+1:]...)
+			break
+		}
+	}
+	cluster.Status = str("deleting")
+	return &rds.DeleteDBClusterOutput{DBCluster: cluster}, nil
+}
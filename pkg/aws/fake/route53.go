@@ -0,0 +1,314 @@
+package fake
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/route53"
+)
+
+// maxChanges is the maximum number of changes in a change batch.
+const maxChanges = 1000
+
+// AddHostedZone adds a hosted zone with the given tags, assigning an ID when
+// it's not set. The zone holds the SOA and NS record sets of its apex.
+func (b *Backend) AddHostedZone(zone *route53.HostedZone, tags ...*route53.Tag) *route53.HostedZone {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if zone.Id == nil {
+		b.nextID++
+		zone.Id = str(fmt.Sprintf("/hostedzone/Z%012d", b.nextID))
+	}
+	id := zoneID(*zone.Id)
+	b.records[id] = []*route53.ResourceRecordSet{
+		{
+			Name: zone.Name,
+			Type: str(route53.RRTypeNs),
+			TTL:  int64Ptr(172800),
+			ResourceRecords: []*route53.ResourceRecord{
+				{Value: str("ns-1.awsdns-01.org.")},
+				{Value: str("ns-2.awsdns-02.com.")},
+			},
+		},
+		{
+			Name: zone.Name,
+			Type: str(route53.RRTypeSoa),
+			TTL:  int64Ptr(900),
+			ResourceRecords: []*route53.ResourceRecord{
+				{Value: str("ns-1.awsdns-01.org. awsdns-hostmaster.amazon.com. 1 7200 900 1209600 86400")},
+			},
+		},
+	}
+	b.zoneTags[id] = tags
+	zone.ResourceRecordSetCount = int64Ptr(int64(len(b.records[id])))
+	b.zones = append(b.zones, zone)
+	return zone
+}
+
+// AddRecordSet adds a record set to the hosted zone.
+func (b *Backend) AddRecordSet(zoneId string, set *route53.ResourceRecordSet) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	id := zoneID(zoneId)
+	b.records[id] = append(b.records[id], set)
+	b.zone(id).ResourceRecordSetCount = int64Ptr(int64(len(b.records[id])))
+}
+
+// HostedZones returns the hosted zones.
+func (b *Backend) HostedZones() []*route53.HostedZone {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return append([]*route53.HostedZone{}, b.zones...)
+}
+
+// RecordSets returns the record sets of the hosted zone.
+func (b *Backend) RecordSets(zoneId string) []*route53.ResourceRecordSet {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return append([]*route53.ResourceRecordSet{}, b.records[zoneID(zoneId)]...)
+}
+
+func (b *Backend) zone(id string) *route53.HostedZone {
+	for _, z := range b.zones {
+		if zoneID(*z.Id) == id {
+			return z
+		}
+	}
+	return nil
+}
+
+// zoneID returns the ID of a zone without the /hostedzone/ prefix.
+func zoneID(id string) string {
+	return strings.TrimPrefix(id, "/hostedzone/")
+}
+
+func int64Ptr(n int64) *int64 {
+	return &n
+}
+
+func noSuchHostedZone(id string) error {
+	return awserr.New(route53.ErrCodeNoSuchHostedZone, fmt.Sprintf("No hosted zone found with ID: %s", id), nil)
+}
+
+// ListHostedZonesByName returns the hosted zones in the order they were
+// added, using the ID of the next zone as the pagination marker.
+func (c *Client) ListHostedZonesByName(input *route53.ListHostedZonesByNameInput) (*route53.ListHostedZonesByNameOutput, error) {
+	c.backend.lock.Lock()
+	defer c.backend.lock.Unlock()
+	if _, err := c.backend.region(c.region); err != nil {
+		return nil, err
+	}
+
+	zones := c.backend.zones
+	start := 0
+	if input.HostedZoneId != nil {
+		start = -1
+		for n, z := range zones {
+			if *z.Id == *input.HostedZoneId {
+				start = n
+			}
+		}
+		if start < 0 {
+			return nil, noSuchHostedZone(*input.HostedZoneId)
+		}
+	}
+
+	var max *int64
+	if input.MaxItems != nil {
+		n, err := strconv.ParseInt(*input.MaxItems, 10, 64)
+		if err != nil {
+			return nil, awserr.New(route53.ErrCodeInvalidInput, "invalid maxitems", err)
+		}
+		max = &n
+	}
+	token := strconv.Itoa(start)
+	start, end, next, err := c.backend.page(len(zones), &token, max)
+	if err != nil {
+		return nil, err
+	}
+
+	truncated := next != nil
+	output := &route53.ListHostedZonesByNameOutput{
+		HostedZones: append([]*route53.HostedZone{}, zones[start:end]...),
+		IsTruncated: &truncated,
+		MaxItems:    str(strconv.Itoa(end - start)),
+	}
+	if truncated {
+		output.NextDNSName = zones[end].Name
+		output.NextHostedZoneId = zones[end].Id
+	}
+	return output, nil
+}
+
+func (c *Client) GetHostedZone(input *route53.GetHostedZoneInput) (*route53.GetHostedZoneOutput, error) {
+	c.backend.lock.Lock()
+	defer c.backend.lock.Unlock()
+	if _, err := c.backend.region(c.region); err != nil {
+		return nil, err
+	}
+
+	zone := c.backend.zone(zoneID(strValue(input.Id)))
+	if zone == nil {
+		return nil, noSuchHostedZone(strValue(input.Id))
+	}
+	return &route53.GetHostedZoneOutput{HostedZone: zone}, nil
+}
+
+func (c *Client) ListHostedZoneTags(input *route53.ListTagsForResourcesInput) (*route53.ListTagsForResourcesOutput, error) {
+	c.backend.lock.Lock()
+	defer c.backend.lock.Unlock()
+	if _, err := c.backend.region(c.region); err != nil {
+		return nil, err
+	}
+	if len(input.ResourceIds) > 10 {
+		return nil, awserr.New(route53.ErrCodeInvalidInput, "ResourceIds must have length less than or equal to 10", nil)
+	}
+
+	output := &route53.ListTagsForResourcesOutput{}
+	for _, id := range input.ResourceIds {
+		if c.backend.zone(zoneID(strValue(id))) == nil {
+			return nil, noSuchHostedZone(strValue(id))
+		}
+		output.ResourceTagSets = append(output.ResourceTagSets, &route53.ResourceTagSet{
+			ResourceId:   id,
+			ResourceType: str(route53.TagResourceTypeHostedzone),
+			Tags:         append([]*route53.Tag{}, c.backend.zoneTags[zoneID(strValue(id))]...),
+		})
+	}
+	return output, nil
+}
+
+// ListResourceRecordSets returns the record sets of the zone in the order
+// they were added, starting at the record set named by the input.
+func (c *Client) ListResourceRecordSets(input *route53.ListResourceRecordSetsInput) (*route53.ListResourceRecordSetsOutput, error) {
+	c.backend.lock.Lock()
+	defer c.backend.lock.Unlock()
+	if _, err := c.backend.region(c.region); err != nil {
+		return nil, err
+	}
+
+	id := zoneID(strValue(input.HostedZoneId))
+	if c.backend.zone(id) == nil {
+		return nil, noSuchHostedZone(id)
+	}
+	sets := c.backend.records[id]
+	start := 0
+	if input.StartRecordName != nil {
+		start = len(sets)
+		for n, s := range sets {
+			if strValue(s.Name) == *input.StartRecordName && strValue(s.Type) == strValue(input.StartRecordType) &&
+				strValue(s.SetIdentifier) == strValue(input.StartRecordIdentifier) {
+				start = n
+				break
+			}
+		}
+	}
+
+	var max *int64
+	if input.MaxItems != nil {
+		n, err := strconv.ParseInt(*input.MaxItems, 10, 64)
+		if err != nil {
+			return nil, awserr.New(route53.ErrCodeInvalidInput, "invalid maxitems", err)
+		}
+		max = &n
+	}
+	token := strconv.Itoa(start)
+	start, end, next, err := c.backend.page(len(sets), &token, max)
+	if err != nil {
+		return nil, err
+	}
+
+	truncated := next != nil
+	output := &route53.ListResourceRecordSetsOutput{
+		ResourceRecordSets: append([]*route53.ResourceRecordSet{}, sets[start:end]...),
+		IsTruncated:        &truncated,
+		MaxItems:           str(strconv.Itoa(end - start)),
+	}
+	if truncated {
+		output.NextRecordName = sets[end].Name
+		output.NextRecordType = sets[end].Type
+		output.NextRecordIdentifier = sets[end].SetIdentifier
+	}
+	return output, nil
+}
+
+// ChangeResourceRecordSets applies the changes of the batch, all of them or
+// none. Only deletions are supported.
+func (c *Client) ChangeResourceRecordSets(input *route53.ChangeResourceRecordSetsInput) (*route53.ChangeResourceRecordSetsOutput, error) {
+	c.backend.lock.Lock()
+	defer c.backend.lock.Unlock()
+	if _, err := c.backend.region(c.region); err != nil {
+		return nil, err
+	}
+
+	id := zoneID(strValue(input.HostedZoneId))
+	zone := c.backend.zone(id)
+	if zone == nil {
+		return nil, noSuchHostedZone(id)
+	}
+	if input.ChangeBatch == nil || len(input.ChangeBatch.Changes) == 0 || len(input.ChangeBatch.Changes) > maxChanges {
+		return nil, awserr.New(route53.ErrCodeInvalidChangeBatch, "a change batch holds between 1 and 1000 changes", nil)
+	}
+
+	sets := append([]*route53.ResourceRecordSet{}, c.backend.records[id]...)
+	for _, change := range input.ChangeBatch.Changes {
+		set := change.ResourceRecordSet
+		if strValue(change.Action) != route53.ChangeActionDelete {
+			return nil, awserr.New(route53.ErrCodeInvalidChangeBatch, "only DELETE changes are supported", nil)
+		}
+		if strValue(set.Name) == strValue(zone.Name) && (strValue(set.Type) == route53.RRTypeSoa || strValue(set.Type) == route53.RRTypeNs) {
+			return nil, awserr.New(route53.ErrCodeInvalidChangeBatch,
+				fmt.Sprintf("A HostedZone must contain exactly one SOA and at least one NS record set at its apex, can't delete %s %s",
+					strValue(set.Name), strValue(set.Type)), nil)
+		}
+		index := -1
+		for n, s := range sets {
+			if strValue(s.Name) == strValue(set.Name) && strValue(s.Type) == strValue(set.Type) &&
+				strValue(s.SetIdentifier) == strValue(set.SetIdentifier) {
+				index = n
+			}
+		}
+		if index < 0 {
+			return nil, awserr.New(route53.ErrCodeInvalidChangeBatch,
+				fmt.Sprintf("Tried to delete resource record set [name='%s', type='%s'] but it was not found",
+					strValue(set.Name), strValue(set.Type)), nil)
+		}
+		sets = append(sets[:index], sets[index+1:]...)
+	}
+
+	c.backend.records[id] = sets
+	zone.ResourceRecordSetCount = int64Ptr(int64(len(sets)))
+	return &route53.ChangeResourceRecordSetsOutput{
+		ChangeInfo: &route53.ChangeInfo{Id: str(c.backend.id("change")), Status: str(route53.ChangeStatusPending)},
+	}, nil
+}
+
+// DeleteHostedZone deletes the zone, which must only hold the SOA and NS
+// record sets of its apex.
+func (c *Client) DeleteHostedZone(input *route53.DeleteHostedZoneInput) (*route53.DeleteHostedZoneOutput, error) {
+	c.backend.lock.Lock()
+	defer c.backend.lock.Unlock()
+	if _, err := c.backend.region(c.region); err != nil {
+		return nil, err
+	}
+
+	id := zoneID(strValue(input.Id))
+	for n, z := range c.backend.zones {
+		if zoneID(*z.Id) != id {
+			continue
+		}
+		if len(c.backend.records[id]) > 2 {
+			return nil, awserr.New(route53.ErrCodeHostedZoneNotEmpty,
+				"The specified hosted zone contains non-required resource record sets and so cannot be deleted.", nil)
+		}
+		c.backend.zones = append(c.backend.zones[:n], c.backend.zones[n+1:]...)
+		delete(c.backend.records, id)
+		delete(c.backend.zoneTags, id)
+		return &route53.DeleteHostedZoneOutput{
+			ChangeInfo: &route53.ChangeInfo{Id: str(c.backend.id("change")), Status: str(route53.ChangeStatusPending)},
+		}, nil
+	}
+	return nil, noSuchHostedZone(id)
+}
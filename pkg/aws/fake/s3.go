@@ -0,0 +1,340 @@
+package fake
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// maxDeleteObjects is the maximum number of objects DeleteObjects deletes in
+// a single request.
+const maxDeleteObjects = 1000
+
+// bucket holds the state of an S3 bucket. Buckets are global but can only be
+// reached by clients of their region.
+type bucket struct {
+	region     string
+	bucket     *s3.Bucket
+	versioning *string
+	tags       []*s3.Tag
+
+	// Every version of every object, the current version of an object
+	// being the latest unless a delete marker was added after it
+	versions []*s3.ObjectVersion
+	markers  []*s3.DeleteMarkerEntry
+}
+
+// AddBucket adds a bucket with the given tags in the region, naming it when
+// its name isn't set.
+func (b *Backend) AddBucket(regionName string, s3Bucket *s3.Bucket, tags ...*s3.Tag) *s3.Bucket {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.mustRegion(regionName)
+	if s3Bucket.Name == nil {
+		s3Bucket.Name = str(b.id("bucket"))
+	}
+	if s3Bucket.CreationDate == nil {
+		s3Bucket.CreationDate = timePtr(time.Now())
+	}
+	b.buckets = append(b.buckets, &bucket{region: regionName, bucket: s3Bucket, tags: tags})
+	return s3Bucket
+}
+
+// SetBucketVersioning sets the versioning state of the bucket, Enabled or
+// Suspended.
+func (b *Backend) SetBucketVersioning(name, status string) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.mustBucket(name).versioning = str(status)
+}
+
+// PutObject adds an object of the given size to the bucket. In a versioned
+// bucket it becomes the current version of the key, otherwise it replaces
+// the object with the same key.
+func (b *Backend) PutObject(name, key string, size int64) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	bkt := b.mustBucket(name)
+	versionID := "null"
+	if strValue(bkt.versioning) == s3.BucketVersioningStatusEnabled {
+		versionID = b.id("version")
+	}
+	bkt.remove(key, versionID)
+	bkt.supersede(key)
+	bkt.versions = append(bkt.versions, &s3.ObjectVersion{
+		Key:          str(key),
+		VersionId:    str(versionID),
+		IsLatest:     boolPtr(true),
+		Size:         int64Ptr(size),
+		LastModified: timePtr(time.Now()),
+	})
+}
+
+// DeleteObject deletes the object from the bucket, which adds a delete
+// marker in a versioned bucket.
+func (b *Backend) DeleteObject(name, key string) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.mustBucket(name).delete(b, key)
+}
+
+// Buckets returns the buckets of every region.
+func (b *Backend) Buckets() []*s3.Bucket {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	var buckets []*s3.Bucket
+	for _, bkt := range b.buckets {
+		buckets = append(buckets, bkt.bucket)
+	}
+	return buckets
+}
+
+// ObjectVersions returns the number of object versions and delete markers
+// in the bucket.
+func (b *Backend) ObjectVersions(name string) int {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	bkt := b.mustBucket(name)
+	return len(bkt.versions) + len(bkt.markers)
+}
+
+func (b *Backend) mustBucket(name string) *bucket {
+	for _, bkt := range b.buckets {
+		if *bkt.bucket.Name == name {
+			return bkt
+		}
+	}
+	panic(fmt.Sprintf("bucket %s not found", name))
+}
+
+// supersede marks every version and delete marker of the key as no longer
+// the latest.
+func (bkt *bucket) supersede(key string) {
+	for _, v := range bkt.versions {
+		if *v.Key == key {
+			v.IsLatest = boolPtr(false)
+		}
+	}
+	for _, m := range bkt.markers {
+		if *m.Key == key {
+			m.IsLatest = boolPtr(false)
+		}
+	}
+}
+
+// remove removes the version or delete marker of the key, reporting whether
+// it was a delete marker.
+func (bkt *bucket) remove(key, versionID string) (marker bool) {
+	for n, v := range bkt.versions {
+		if *v.Key == key && *v.VersionId == versionID {
+			bkt.versions = append(bkt.versions[:n], bkt.versions[n+1:]...)
+			return false
+		}
+	}
+	for n, m := range bkt.markers {
+		if *m.Key == key && *m.VersionId == versionID {
+			bkt.markers = append(bkt.markers[:n], bkt.markers[n+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// delete deletes the current version of the key, adding a delete marker in
+// a versioned bucket.
+func (bkt *bucket) delete(b *Backend, key string) {
+	if bkt.versioning == nil {
+		bkt.remove(key, "null")
+		return
+	}
+	bkt.supersede(key)
+	bkt.markers = append(bkt.markers, &s3.DeleteMarkerEntry{
+		Key:          str(key),
+		VersionId:    str(b.id("marker")),
+		IsLatest:     boolPtr(true),
+		LastModified: timePtr(time.Now()),
+	})
+}
+
+// bucket returns the named bucket, failing when the client's region isn't
+// the one of the bucket.
+func (c *Client) bucket(name *string) (*bucket, error) {
+	if err, ok := c.backend.failures[c.region]; ok {
+		return nil, err
+	}
+	for _, bkt := range c.backend.buckets {
+		if *bkt.bucket.Name != strValue(name) {
+			continue
+		}
+		if bkt.region != c.region {
+			return nil, awserr.New("PermanentRedirect", "The bucket you are attempting to access must be addressed using the specified endpoint.", nil)
+		}
+		return bkt, nil
+	}
+	return nil, awserr.New(s3.ErrCodeNoSuchBucket, "The specified bucket does not exist", nil)
+}
+
+func (c *Client) ListBuckets(input *s3.ListBucketsInput) (*s3.ListBucketsOutput, error) {
+	c.backend.lock.Lock()
+	defer c.backend.lock.Unlock()
+	if _, err := c.backend.region(c.region); err != nil {
+		return nil, err
+	}
+
+	output := &s3.ListBucketsOutput{}
+	for _, bkt := range c.backend.buckets {
+		output.Buckets = append(output.Buckets, bkt.bucket)
+	}
+	return output, nil
+}
+
+// GetBucketLocation returns an empty location for buckets in us-east-1, as
+// S3 does. It can be called from any region.
+func (c *Client) GetBucketLocation(input *s3.GetBucketLocationInput) (*s3.GetBucketLocationOutput, error) {
+	c.backend.lock.Lock()
+	defer c.backend.lock.Unlock()
+	if _, err := c.backend.region(c.region); err != nil {
+		return nil, err
+	}
+
+	for _, bkt := range c.backend.buckets {
+		if *bkt.bucket.Name != strValue(input.Bucket) {
+			continue
+		}
+		output := &s3.GetBucketLocationOutput{}
+		if bkt.region != "us-east-1" {
+			output.LocationConstraint = str(bkt.region)
+		}
+		return output, nil
+	}
+	return nil, awserr.New(s3.ErrCodeNoSuchBucket, "The specified bucket does not exist", nil)
+}
+
+func (c *Client) GetBucketVersioning(input *s3.GetBucketVersioningInput) (*s3.GetBucketVersioningOutput, error) {
+	c.backend.lock.Lock()
+	defer c.backend.lock.Unlock()
+	bkt, err := c.bucket(input.Bucket)
+	if err != nil {
+		return nil, err
+	}
+	return &s3.GetBucketVersioningOutput{Status: bkt.versioning}, nil
+}
+
+func (c *Client) GetBucketTagging(input *s3.GetBucketTaggingInput) (*s3.GetBucketTaggingOutput, error) {
+	c.backend.lock.Lock()
+	defer c.backend.lock.Unlock()
+	bkt, err := c.bucket(input.Bucket)
+	if err != nil {
+		return nil, err
+	}
+	if len(bkt.tags) == 0 {
+		return nil, awserr.New("NoSuchTagSet", "The TagSet does not exist", nil)
+	}
+	return &s3.GetBucketTaggingOutput{TagSet: bkt.tags}, nil
+}
+
+// ListObjectsV2 lists the current version of the objects of the bucket.
+func (c *Client) ListObjectsV2(input *s3.ListObjectsV2Input) (*s3.ListObjectsV2Output, error) {
+	c.backend.lock.Lock()
+	defer c.backend.lock.Unlock()
+	bkt, err := c.bucket(input.Bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	var objects []*s3.Object
+	for _, v := range bkt.versions {
+		if *v.IsLatest {
+			objects = append(objects, &s3.Object{Key: v.Key, Size: v.Size, LastModified: v.LastModified})
+		}
+	}
+	start, end, next, err := c.backend.page(len(objects), input.ContinuationToken, input.MaxKeys)
+	if err != nil {
+		return nil, err
+	}
+	return &s3.ListObjectsV2Output{
+		Contents:              append([]*s3.Object{}, objects[start:end]...),
+		KeyCount:              int64Ptr(int64(end - start)),
+		IsTruncated:           boolPtr(next != nil),
+		NextContinuationToken: next,
+	}, nil
+}
+
+// ListObjectVersions lists every version and delete marker of the bucket.
+// The markers returned are opaque rather than the last key and version.
+func (c *Client) ListObjectVersions(input *s3.ListObjectVersionsInput) (*s3.ListObjectVersionsOutput, error) {
+	c.backend.lock.Lock()
+	defer c.backend.lock.Unlock()
+	bkt, err := c.bucket(input.Bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	start, end, next, err := c.backend.page(len(bkt.versions)+len(bkt.markers), input.KeyMarker, input.MaxKeys)
+	if err != nil {
+		return nil, err
+	}
+	output := &s3.ListObjectVersionsOutput{IsTruncated: boolPtr(next != nil)}
+	for n := start; n < end; n++ {
+		if n < len(bkt.versions) {
+			output.Versions = append(output.Versions, bkt.versions[n])
+		} else {
+			output.DeleteMarkers = append(output.DeleteMarkers, bkt.markers[n-len(bkt.versions)])
+		}
+	}
+	if next != nil {
+		output.NextKeyMarker = next
+		output.NextVersionIdMarker = str(strconv.Itoa(end))
+	}
+	return output, nil
+}
+
+// DeleteObjects deletes the versions given, or the current version of the
+// objects given without a version.
+func (c *Client) DeleteObjects(input *s3.DeleteObjectsInput) (*s3.DeleteObjectsOutput, error) {
+	c.backend.lock.Lock()
+	defer c.backend.lock.Unlock()
+	bkt, err := c.bucket(input.Bucket)
+	if err != nil {
+		return nil, err
+	}
+	if input.Delete == nil || len(input.Delete.Objects) == 0 || len(input.Delete.Objects) > maxDeleteObjects {
+		return nil, awserr.New("MalformedXML", "The XML you provided was not well-formed or did not validate against our published schema", nil)
+	}
+
+	output := &s3.DeleteObjectsOutput{}
+	for _, o := range input.Delete.Objects {
+		deleted := &s3.DeletedObject{Key: o.Key, VersionId: o.VersionId}
+		if o.VersionId == nil {
+			bkt.delete(c.backend, strValue(o.Key))
+		} else if bkt.remove(strValue(o.Key), *o.VersionId) {
+			deleted.DeleteMarker = boolPtr(true)
+			deleted.DeleteMarkerVersionId = o.VersionId
+		}
+		output.Deleted = append(output.Deleted, deleted)
+	}
+	return output, nil
+}
+
+func (c *Client) DeleteBucket(input *s3.DeleteBucketInput) (*s3.DeleteBucketOutput, error) {
+	c.backend.lock.Lock()
+	defer c.backend.lock.Unlock()
+	bkt, err := c.bucket(input.Bucket)
+	if err != nil {
+		return nil, err
+	}
+	if len(bkt.versions) > 0 || len(bkt.markers) > 0 {
+		return nil, awserr.New("BucketNotEmpty", "The bucket you tried to delete is not empty", nil)
+	}
+
+	for n, other := range c.backend.buckets {
+		if other == bkt {
+			c.backend.buckets = append(c.backend.buckets[:n], c.backend.buckets[n+1:]...)
+			break
+		}
+	}
+	return &s3.DeleteBucketOutput{}, nil
+}
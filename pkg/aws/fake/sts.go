@@ -0,0 +1,19 @@
+package fake
+
+import (
+	"github.com/aws/aws-sdk-go/service/sts"
+)
+
+func (c *Client) GetCallerIdentity(input *sts.GetCallerIdentityInput) (*sts.GetCallerIdentityOutput, error) {
+	c.backend.lock.Lock()
+	defer c.backend.lock.Unlock()
+	if _, err := c.backend.region(c.region); err != nil {
+		return nil, err
+	}
+
+	return &sts.GetCallerIdentityOutput{
+		Account: str(c.backend.accountID),
+		Arn:     str(c.backend.arn),
+		UserId:  str("AIDAFAKEUSER"),
+	}, nil
+}
@@ -0,0 +1,177 @@
+// Package config loads the configuration file holding the named targets
+// selected with --target, and applies their settings to command flags.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/yaml"
+)
+
+// DefaultFile is the name of the configuration file looked up in the home
+// directory.
+const DefaultFile = ".aws-resource.yaml"
+
+// EnvPrefix prefixes the environment variables overriding flags, e.g.
+// AWS_RESOURCE_REGION overrides --region.
+const EnvPrefix = "AWS_RESOURCE_"
+
+// File is the content of a configuration file.
+type File struct {
+	// Target is the name of the target used when --target isn't given.
+	Target string `json:"target,omitempty"`
+
+	Targets map[string]*Target `json:"targets,omitempty"`
+}
+
+// Target holds the settings of an environment, applied to the flags of the
+// same name that aren't set on the command line.
+type Target struct {
+	Profile       string   `json:"profile,omitempty"`
+	RoleArn       string   `json:"roleArn,omitempty"`
+	ExternalID    string   `json:"externalId,omitempty"`
+	Region        string   `json:"region,omitempty"`
+	Regions       []string `json:"regions,omitempty"`
+	Selector      string   `json:"selector,omitempty"`
+	ProtectedTags []string `json:"protectedTags,omitempty"`
+	DenyList      string   `json:"denyList,omitempty"`
+	AuditLog      string   `json:"auditLog,omitempty"`
+	Output        string   `json:"output,omitempty"`
+}
+
+// Flags returns the settings of the target keyed by flag name, leaving out
+// the ones that aren't set.
+func (t *Target) Flags() map[string]string {
+	flags := map[string]string{}
+	set := func(name, value string) {
+		if value != "" {
+			flags[name] = value
+		}
+	}
+	set("profile", t.Profile)
+	set("role-arn", t.RoleArn)
+	set("external-id", t.ExternalID)
+	set("region", t.Region)
+	set("regions", strings.Join(t.Regions, ","))
+	set("selector", t.Selector)
+	set("protected-tags", strings.Join(t.ProtectedTags, ","))
+	set("deny-list", t.DenyList)
+	set("audit-log", t.AuditLog)
+	set("output", t.Output)
+	return flags
+}
+
+// DefaultPath returns the path of the configuration file in the home
+// directory.
+func DefaultPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, DefaultFile), nil
+}
+
+// Load reads a configuration file. When optional is set a missing file
+// returns an empty configuration.
+func Load(path string, optional bool) (*File, error) {
+	data, err := ioutil.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) && optional {
+		return &File{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read config file: %s", err)
+	}
+
+	file := &File{}
+	if err := yaml.UnmarshalStrict(data, file); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %s", path, err)
+	}
+	return file, nil
+}
+
+// Lookup returns the target called name, or the file's default target when
+// name is empty. It returns nil when no target is selected.
+func (f *File) Lookup(name string) (*Target, error) {
+	if name == "" {
+		name = f.Target
+	}
+	if name == "" {
+		return nil, nil
+	}
+
+	target, ok := f.Targets[name]
+	if !ok || target == nil {
+		var names []string
+		for n := range f.Targets {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("unknown target %q, the config file defines %s", name, describe(names))
+	}
+	return target, nil
+}
+
+func describe(names []string) string {
+	if len(names) == 0 {
+		return "no targets"
+	}
+	return strings.Join(names, ", ")
+}
+
+// EnvName returns the environment variable overriding the flag.
+func EnvName(flag string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(flag, "-", "_"))
+}
+
+// Apply sets the flags of fs from the target's settings, unless they were
+// given on the command line, then from the AWS_RESOURCE_* environment
+// variables, which take precedence over both. Settings for flags fs doesn't
+// have are ignored.
+func Apply(fs *pflag.FlagSet, target *Target, lookupEnv func(string) (string, bool)) error {
+	var settings map[string]string
+	if target != nil {
+		settings = target.Flags()
+	}
+
+	var err error
+	fs.VisitAll(func(f *pflag.Flag) {
+		if err != nil {
+			return
+		}
+		if value, ok := settings[f.Name]; ok && !f.Changed {
+			if setErr := set(f, value); setErr != nil {
+				err = fmt.Errorf("invalid %s in config file: %s", f.Name, setErr)
+				return
+			}
+		}
+		name := EnvName(f.Name)
+		if value, ok := lookupEnv(name); ok {
+			if setErr := set(f, value); setErr != nil {
+				err = fmt.Errorf("invalid %s: %s", name, setErr)
+			}
+		}
+	})
+	return err
+}
+
+// set sets the value of the flag, replacing rather than appending to the
+// values of slices.
+func set(f *pflag.Flag, value string) error {
+	if slice, ok := f.Value.(pflag.SliceValue); ok {
+		var values []string
+		for _, v := range strings.Split(value, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+		return slice.Replace(values)
+	}
+	return f.Value.Set(value)
+}
@@ -0,0 +1,56 @@
+// Package duration parses the ages given to the --older-than and
+// --newer-than flags.
+package duration
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+const (
+	Day  = 24 * time.Hour
+	Week = 7 * Day
+)
+
+var units = map[string]time.Duration{
+	"s": time.Second,
+	"m": time.Minute,
+	"h": time.Hour,
+	"d": Day,
+	"w": Week,
+}
+
+// Parse parses a duration such as "30d", "2h" or "1w2d12h". Each number may
+// be a decimal and is followed by one of the units s, m, h, d (24 hours) or
+// w (7 days).
+func Parse(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+
+	var total time.Duration
+	for rest := s; rest != ""; {
+		i := 0
+		for i < len(rest) && (rest[i] == '.' || rest[i] >= '0' && rest[i] <= '9') {
+			i++
+		}
+		if i == 0 {
+			return 0, fmt.Errorf("invalid duration %q, expected a number such as 30d or 2h", s)
+		}
+		n, err := strconv.ParseFloat(rest[:i], 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q, expected a number such as 30d or 2h", s)
+		}
+		if i == len(rest) {
+			return 0, fmt.Errorf("missing unit in duration %q, expected one of s, m, h, d or w", s)
+		}
+		unit, ok := units[rest[i:i+1]]
+		if !ok {
+			return 0, fmt.Errorf("unknown unit %q in duration %q, expected one of s, m, h, d or w", rest[i:i+1], s)
+		}
+		total += time.Duration(n * float64(unit))
+		rest = rest[i+1:]
+	}
+	return total, nil
+}
@@ -0,0 +1,44 @@
+package duration
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		s    string
+		want time.Duration
+		err  bool
+	}{
+		{s: "30d", want: 30 * Day},
+		{s: "2h", want: 2 * time.Hour},
+		{s: "90m", want: 90 * time.Minute},
+		{s: "45s", want: 45 * time.Second},
+		{s: "1w2d12h", want: Week + 2*Day + 12*time.Hour},
+		{s: "1.5d", want: 36 * time.Hour},
+		{s: "", err: true},
+		{s: "30", err: true},
+		{s: "d", err: true},
+		{s: "30y", err: true},
+		{s: "-1d", err: true},
+		{s: "1..5d", err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			got, err := Parse(tt.s)
+			if tt.err {
+				if err == nil {
+					t.Fatalf("expected an error, got %s", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Fatalf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
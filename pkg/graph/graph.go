@@ -0,0 +1,182 @@
+// Package graph orders the deletion of resources from the relations between
+// them, so that a resource is only deleted once the resources using it are.
+package graph
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jharrington22/aws-resource/pkg/resource"
+)
+
+// Relation is a dependency between two resource types.
+type Relation struct {
+	// Resources of type From use resources of type To, which can only be
+	// deleted once the resources using them are.
+	From, To string
+
+	// Uses returns the IDs of the resources of type To the resource uses.
+	// They're looked up in the resource's account and region.
+	Uses func(r *resource.Resource) []string
+}
+
+// Relations lists the relations known between the supported resource types.
+var Relations = []Relation{
+	// Volumes can't be deleted while attached to an instance
+	{From: "ec2", To: "volumes", Uses: property("volumes")},
+	// Snapshots can't be deleted while backing an image
+	{From: "images", To: "snapshots", Uses: property("snapshots")},
+	// Load balancers stop routing to instances before they're terminated
+	{From: "elb", To: "ec2", Uses: property("instances")},
+	// Target groups can't be deleted while a load balancer forwards to them
+	{From: "elbv2", To: "targetgroups", Uses: property("target-groups")},
+	// Clusters can't be deleted until their instances are being deleted
+	{From: "rds", To: "rds", Uses: property("cluster")},
+	// Clusters can't be deleted while they have nodegroups or Fargate profiles
+	{From: "eks", To: "eks", Uses: property("cluster")},
+
+	// Hosted zones can't be deleted while they hold record sets either, but
+	// record sets aren't a resource type yet: the route53 provider empties
+	// a zone in change batches before deleting it. Relating them here is
+	// deferred until record sets can be deleted in batches as graph nodes.
+}
+
+// property returns a function reading the comma separated IDs of a property.
+func property(name string) func(r *resource.Resource) []string {
+	return func(r *resource.Resource) []string {
+		var ids []string
+		for _, id := range strings.Split(r.Properties[name], ",") {
+			if id != "" {
+				ids = append(ids, id)
+			}
+		}
+		return ids
+	}
+}
+
+// Key identifies a resource in a graph.
+func Key(r *resource.Resource) string {
+	return key(r.Account, r.Type, r.Region, r.ID)
+}
+
+func key(account, typ, region, id string) string {
+	return account + "/" + typ + "/" + region + "/" + id
+}
+
+// Graph holds resources and the order they must be deleted in.
+type Graph struct {
+	resources []*resource.Resource
+	nodes     map[string]*resource.Resource
+
+	// before holds, by key, the resources to delete before a resource, and
+	// uses the resources a resource uses
+	before map[string][]*resource.Resource
+	uses   map[string][]*resource.Resource
+}
+
+// New returns the graph of the resources, related by Relations. Related
+// resources missing from resources are left out.
+func New(resources []*resource.Resource) *Graph {
+	g := &Graph{
+		nodes:  map[string]*resource.Resource{},
+		before: map[string][]*resource.Resource{},
+		uses:   map[string][]*resource.Resource{},
+	}
+	for _, r := range resources {
+		if _, ok := g.nodes[Key(r)]; ok {
+			continue
+		}
+		g.nodes[Key(r)] = r
+		g.resources = append(g.resources, r)
+	}
+
+	for _, r := range g.resources {
+		for _, relation := range Relations {
+			if relation.From != r.Type {
+				continue
+			}
+			for _, id := range relation.Uses(r) {
+				used := key(r.Account, relation.To, r.Region, id)
+				if u, ok := g.nodes[used]; ok {
+					g.before[used] = append(g.before[used], r)
+					g.uses[Key(r)] = append(g.uses[Key(r)], u)
+				}
+			}
+		}
+	}
+	return g
+}
+
+// Before returns the resources of the graph that must be deleted before r.
+func (g *Graph) Before(r *resource.Resource) []*resource.Resource {
+	return g.before[Key(r)]
+}
+
+// Uses returns the resources of the graph r uses, which must be deleted
+// after it.
+func (g *Graph) Uses(r *resource.Resource) []*resource.Resource {
+	return g.uses[Key(r)]
+}
+
+// CycleError is returned when resources depend on each other.
+type CycleError struct {
+	Resources []*resource.Resource
+}
+
+func (e *CycleError) Error() string {
+	var ids []string
+	for _, r := range e.Resources {
+		ids = append(ids, r.Type+" "+r.ID)
+	}
+	return fmt.Sprintf("resources depend on each other: %s", strings.Join(ids, " -> "))
+}
+
+// Order returns the resources in the order they must be deleted. Resources
+// that don't depend on each other keep the order they were given in.
+func (g *Graph) Order() ([]*resource.Resource, error) {
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := map[string]int{}
+	var ordered, path []*resource.Resource
+
+	var visit func(r *resource.Resource) error
+	visit = func(r *resource.Resource) error {
+		k := Key(r)
+		switch state[k] {
+		case done:
+			return nil
+		case visiting:
+			return &CycleError{Resources: append(cycle(path, r), r)}
+		}
+		state[k] = visiting
+		path = append(path, r)
+		for _, before := range g.before[k] {
+			if err := visit(before); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[k] = done
+		ordered = append(ordered, r)
+		return nil
+	}
+
+	for _, r := range g.resources {
+		if err := visit(r); err != nil {
+			return nil, err
+		}
+	}
+	return ordered, nil
+}
+
+// cycle returns the end of the path starting at r.
+func cycle(path []*resource.Resource, r *resource.Resource) []*resource.Resource {
+	for i, p := range path {
+		if Key(p) == Key(r) {
+			return append([]*resource.Resource{}, path[i:]...)
+		}
+	}
+	return path
+}
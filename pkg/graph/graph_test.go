@@ -0,0 +1,79 @@
+package graph
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jharrington22/aws-resource/pkg/resource"
+)
+
+func ids(resources []*resource.Resource) string {
+	var ids []string
+	for _, r := range resources {
+		ids = append(ids, r.ID)
+	}
+	return strings.Join(ids, ",")
+}
+
+func TestOrder(t *testing.T) {
+	snapshot := &resource.Resource{Type: "snapshots", Region: "us-east-1", ID: "snap-1"}
+	shared := &resource.Resource{Type: "snapshots", Region: "us-east-1", ID: "snap-2"}
+	other := &resource.Resource{Type: "snapshots", Region: "eu-west-1", ID: "snap-1"}
+	image := &resource.Resource{Type: "images", Region: "us-east-1", ID: "ami-1",
+		Properties: map[string]string{"snapshots": "snap-1,snap-2"}}
+	second := &resource.Resource{Type: "images", Region: "us-east-1", ID: "ami-2",
+		Properties: map[string]string{"snapshots": "snap-2,snap-missing"}}
+	volume := &resource.Resource{Type: "volumes", Region: "us-east-1", ID: "vol-1"}
+	instance := &resource.Resource{Type: "ec2", Region: "us-east-1", ID: "i-1",
+		Properties: map[string]string{"volumes": "vol-1"}}
+	lb := &resource.Resource{Type: "elb", Region: "us-east-1", ID: "web",
+		Properties: map[string]string{"instances": "i-1"}}
+	cluster := &resource.Resource{Type: "rds", Region: "us-east-1", ID: "cluster:db"}
+	member := &resource.Resource{Type: "rds", Region: "us-east-1", ID: "db:db-1",
+		Properties: map[string]string{"cluster": "cluster:db"}}
+
+	g := New([]*resource.Resource{snapshot, other, shared, volume, second, image, instance, lb, cluster, member})
+	ordered, err := g.Order()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "ami-1,snap-1,snap-1,ami-2,snap-2,web,i-1,vol-1,db:db-1,cluster:db"
+	if got := ids(ordered); got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+	if got := ids(g.Before(shared)); got != "ami-2,ami-1" {
+		t.Errorf("expected both images to use the shared snapshot, got %s", got)
+	}
+	if got := g.Before(other); len(got) != 0 {
+		t.Errorf("expected a snapshot in another region to be unrelated, got %s", ids(got))
+	}
+}
+
+func TestOrderAccounts(t *testing.T) {
+	snapshot := &resource.Resource{Account: "111111111111", Type: "snapshots", Region: "us-east-1", ID: "snap-1"}
+	image := &resource.Resource{Account: "222222222222", Type: "images", Region: "us-east-1", ID: "ami-1",
+		Properties: map[string]string{"snapshots": "snap-1"}}
+	if got := New([]*resource.Resource{snapshot, image}).Before(snapshot); len(got) != 0 {
+		t.Errorf("expected resources of other accounts to be unrelated, got %s", ids(got))
+	}
+}
+
+func TestCycle(t *testing.T) {
+	relations := Relations
+	defer func() { Relations = relations }()
+	Relations = append(Relations, Relation{From: "volumes", To: "ec2", Uses: property("instances")})
+
+	volume := &resource.Resource{Type: "volumes", Region: "us-east-1", ID: "vol-1",
+		Properties: map[string]string{"instances": "i-1"}}
+	instance := &resource.Resource{Type: "ec2", Region: "us-east-1", ID: "i-1",
+		Properties: map[string]string{"volumes": "vol-1"}}
+	_, err := New([]*resource.Resource{volume, instance}).Order()
+	var cycle *CycleError
+	if !errors.As(err, &cycle) {
+		t.Fatalf("expected a cycle, got %v", err)
+	}
+	if got := ids(cycle.Resources); got != "vol-1,i-1,vol-1" {
+		t.Errorf("got cycle %s", got)
+	}
+}
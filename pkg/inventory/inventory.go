@@ -0,0 +1,220 @@
+// Package inventory records every resource found in an account at a point in
+// time, and compares inventories to find what was added, removed or changed.
+package inventory
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jharrington22/aws-resource/pkg/output"
+	"github.com/jharrington22/aws-resource/pkg/resource"
+)
+
+// Version is the version of the inventory file format.
+const Version = 1
+
+// Inventory is the set of resources found in an account.
+type Inventory struct {
+	Version   int                     `json:"version"`
+	AccountID string                  `json:"accountId"`
+	CreatedAt time.Time               `json:"createdAt"`
+	Resources []output.ResourceRecord `json:"resources"`
+}
+
+// New creates an inventory of the resources found in the account.
+func New(accountID string, resources []*resource.Resource) *Inventory {
+	inv := &Inventory{
+		Version:   Version,
+		AccountID: accountID,
+		CreatedAt: time.Now().UTC(),
+		Resources: make([]output.ResourceRecord, 0, len(resources)),
+	}
+	for _, r := range resources {
+		inv.Resources = append(inv.Resources, output.NewResourceRecord(r))
+	}
+	sort.Slice(inv.Resources, func(i, j int) bool {
+		return key(inv.Resources[i]) < key(inv.Resources[j])
+	})
+	return inv
+}
+
+// Save writes the inventory to path.
+func (inv *Inventory) Save(path string) error {
+	data, err := json.MarshalIndent(inv, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append(data, '\n'), 0600)
+}
+
+// Load reads the inventory saved at path.
+func Load(path string) (*Inventory, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	inv := &Inventory{}
+	if err := json.Unmarshal(data, inv); err != nil {
+		return nil, fmt.Errorf("unable to parse inventory %s: %s", path, err)
+	}
+	if inv.Version != Version {
+		return nil, fmt.Errorf("unsupported inventory version %d in %s", inv.Version, path)
+	}
+	return inv, nil
+}
+
+// Change is a resource found in both inventories whose attributes differ.
+type Change struct {
+	Old output.ResourceRecord
+	New output.ResourceRecord
+
+	// Fields names the attributes that changed, such as "state" or
+	// "tags.env", in alphabetical order.
+	Fields []string
+}
+
+// Diff is the difference between two inventories.
+type Diff struct {
+	Added   []output.ResourceRecord
+	Removed []output.ResourceRecord
+	Changed []Change
+}
+
+// Empty reports whether the inventories hold the same resources.
+func (d *Diff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// Compare returns the resources added, removed and changed between the old
+// and new inventories. Resources are identified by their account, type,
+// region and ID.
+func Compare(old, new *Inventory) *Diff {
+	before := map[string]output.ResourceRecord{}
+	for _, r := range old.Resources {
+		before[key(r)] = r
+	}
+	after := map[string]output.ResourceRecord{}
+	for _, r := range new.Resources {
+		after[key(r)] = r
+	}
+
+	diff := &Diff{}
+	for _, r := range new.Resources {
+		previous, ok := before[key(r)]
+		if !ok {
+			diff.Added = append(diff.Added, r)
+			continue
+		}
+		if fields := changes(previous, r); len(fields) > 0 {
+			diff.Changed = append(diff.Changed, Change{Old: previous, New: r, Fields: fields})
+		}
+	}
+	for _, r := range old.Resources {
+		if _, ok := after[key(r)]; !ok {
+			diff.Removed = append(diff.Removed, r)
+		}
+	}
+	return diff
+}
+
+func key(r output.ResourceRecord) string {
+	return r.Account + "/" + r.Type + "/" + r.Region + "/" + r.ID
+}
+
+// changes returns the names of the attributes that differ between records of
+// the same resource.
+func changes(old, new output.ResourceRecord) []string {
+	var fields []string
+	if old.Name != new.Name {
+		fields = append(fields, "name")
+	}
+	if old.State != new.State {
+		fields = append(fields, "state")
+	}
+	if !sameTime(old.CreatedAt, new.CreatedAt) {
+		fields = append(fields, "createdAt")
+	}
+	fields = append(fields, mapChanges("tags", old.Tags, new.Tags)...)
+	fields = append(fields, mapChanges("properties", old.Properties, new.Properties)...)
+	sort.Strings(fields)
+	return fields
+}
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+func mapChanges(prefix string, old, new map[string]string) []string {
+	var fields []string
+	for k, v := range old {
+		if nv, ok := new[k]; !ok || nv != v {
+			fields = append(fields, prefix+"."+k)
+		}
+	}
+	for k := range new {
+		if _, ok := old[k]; !ok {
+			fields = append(fields, prefix+"."+k)
+		}
+	}
+	return fields
+}
+
+// Value returns the value of an attribute named as in Change.Fields.
+func Value(r output.ResourceRecord, field string) string {
+	switch {
+	case field == "name":
+		return r.Name
+	case field == "state":
+		return r.State
+	case field == "createdAt":
+		if r.CreatedAt == nil {
+			return ""
+		}
+		return r.CreatedAt.Format(time.RFC3339)
+	case strings.HasPrefix(field, "tags."):
+		return r.Tags[strings.TrimPrefix(field, "tags.")]
+	case strings.HasPrefix(field, "properties."):
+		return r.Properties[strings.TrimPrefix(field, "properties.")]
+	}
+	return ""
+}
+
+// Kinds of change of a Record.
+const (
+	Added   = "added"
+	Removed = "removed"
+	Changed = "changed"
+)
+
+// Record is the machine readable form of an entry of a diff.
+type Record struct {
+	Change   string                 `json:"change"`
+	Resource output.ResourceRecord  `json:"resource"`
+	Previous *output.ResourceRecord `json:"previous,omitempty"`
+	Fields   []string               `json:"fields,omitempty"`
+}
+
+// Records returns the entries of the diff, added resources first, then
+// removed and changed ones.
+func (d *Diff) Records() []Record {
+	records := []Record{}
+	for _, r := range d.Added {
+		records = append(records, Record{Change: Added, Resource: r})
+	}
+	for _, r := range d.Removed {
+		records = append(records, Record{Change: Removed, Resource: r})
+	}
+	for _, c := range d.Changed {
+		previous := c.Old
+		records = append(records, Record{Change: Changed, Resource: c.New, Previous: &previous, Fields: c.Fields})
+	}
+	return records
+}
@@ -0,0 +1,89 @@
+// Package output writes record sets in the machine readable formats
+// supported by the --output flag.
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Format is the name of an output format.
+type Format string
+
+const (
+	// None keeps the default human readable messages only.
+	None  Format = ""
+	JSON  Format = "json"
+	YAML  Format = "yaml"
+	CSV   Format = "csv"
+	Table Format = "table"
+)
+
+// Formats lists the supported output formats.
+var Formats = []Format{JSON, YAML, CSV, Table}
+
+// ParseFormat validates the name of an output format.
+func ParseFormat(value string) (Format, error) {
+	if value == "" {
+		return None, nil
+	}
+	for _, f := range Formats {
+		if string(f) == strings.ToLower(value) {
+			return f, nil
+		}
+	}
+	return None, fmt.Errorf("unsupported output format %q, must be one of json, yaml, csv or table", value)
+}
+
+// Rows is a tabular view of a record set, used by the csv and table formats.
+type Rows struct {
+	Headers []string
+	Values  [][]string
+}
+
+// Write writes the record set to w. The json and yaml formats marshal
+// records while csv and table write rows.
+func Write(w io.Writer, format Format, records interface{}, rows Rows) error {
+	switch format {
+	case JSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(records)
+	case YAML:
+		data, err := yaml.Marshal(records)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	case CSV:
+		writer := csv.NewWriter(w)
+		if err := writer.Write(rows.Headers); err != nil {
+			return err
+		}
+		if err := writer.WriteAll(rows.Values); err != nil {
+			return err
+		}
+		return writer.Error()
+	case Table:
+		writer := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+		headers := make([]string, len(rows.Headers))
+		for i, h := range rows.Headers {
+			headers[i] = strings.ToUpper(h)
+		}
+		fmt.Fprintln(writer, strings.Join(headers, "\t"))
+		for _, v := range rows.Values {
+			fmt.Fprintln(writer, strings.Join(v, "\t"))
+		}
+		return writer.Flush()
+	case None:
+		return nil
+	}
+	return fmt.Errorf("unsupported output format %q", format)
+}
@@ -0,0 +1,112 @@
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jharrington22/aws-resource/pkg/pricing"
+	"github.com/jharrington22/aws-resource/pkg/resource"
+	"sigs.k8s.io/yaml"
+)
+
+// instance returns an instance named by a tag holding commas and quotes.
+func instance() *resource.Resource {
+	name := `web, "blue"`
+	return &resource.Resource{
+		Account:    "123456789012",
+		Type:       "ec2",
+		ID:         "i-0123456789abcdef0",
+		Region:     "us-east-1",
+		Name:       name,
+		State:      "running",
+		CreatedAt:  time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC),
+		Tags:       map[string]string{"Name": name, resource.StackNameTag: "web"},
+		Properties: map[string]string{"instance-type": "m5.xlarge", "volumes": "vol-1,vol-2"},
+	}
+}
+
+func TestCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteResources(&buf, CSV, []*resource.Resource{instance()}, pricing.Default()); err != nil {
+		t.Fatal(err)
+	}
+
+	header := "account,type,region,id,name,state,created,stack,hourly-cost,monthly-cost,instance-type,volumes\n"
+	if !strings.HasPrefix(buf.String(), header) {
+		t.Fatalf("expected the header %q, got %q", header, buf.String())
+	}
+	if want := `"web, ""blue"""`; !strings.Contains(buf.String(), want) {
+		t.Errorf("expected the name to be escaped as %s, got %q", want, buf.String())
+	}
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"123456789012", "ec2", "us-east-1", "i-0123456789abcdef0", `web, "blue"`, "running", "2022-03-01T12:00:00Z", "web", "0.1920", "140.16", "m5.xlarge", "vol-1,vol-2"}
+	if len(rows) != 2 || !reflect.DeepEqual(rows[1], want) {
+		t.Errorf("expected the row %q, got %q", want, rows[1:])
+	}
+}
+
+func TestFieldNames(t *testing.T) {
+	want := []string{"account", "createdAt", "hourlyCost", "id", "monthlyCost", "name", "properties", "region", "stack", "state", "tags", "type"}
+
+	for _, format := range []Format{JSON, YAML} {
+		var buf bytes.Buffer
+		if err := WriteResources(&buf, format, []*resource.Resource{instance()}, pricing.Default()); err != nil {
+			t.Fatal(err)
+		}
+		data := buf.Bytes()
+		if format == YAML {
+			var err error
+			if data, err = yaml.YAMLToJSON(data); err != nil {
+				t.Fatal(err)
+			}
+		}
+		var records []map[string]interface{}
+		if err := json.Unmarshal(data, &records); err != nil {
+			t.Fatal(err)
+		}
+		if len(records) != 1 {
+			t.Fatalf("expected a single %s record, got %d", format, len(records))
+		}
+		var fields []string
+		for field := range records[0] {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+		if !reflect.DeepEqual(fields, want) {
+			t.Errorf("expected the %s fields %v, got %v", format, want, fields)
+		}
+		if tags := records[0]["tags"].(map[string]interface{}); tags["Name"] != `web, "blue"` {
+			t.Errorf("expected the %s Name tag to be kept, got %v", format, tags["Name"])
+		}
+	}
+}
+
+func TestEmptyTable(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteResources(&buf, Table, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if want := "TYPE   REGION   ID   NAME   STATE   CREATED\n"; buf.String() != want {
+		t.Errorf("expected %q, got %q", want, buf.String())
+	}
+
+	for format, want := range map[Format]string{JSON: "[]\n", YAML: "[]\n", CSV: "type,region,id,name,state,created\n"} {
+		buf.Reset()
+		if err := WriteResources(&buf, format, nil, nil); err != nil {
+			t.Fatal(err)
+		}
+		if buf.String() != want {
+			t.Errorf("expected %s to write %q, got %q", format, want, buf.String())
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package output
+
+import (
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/jharrington22/aws-resource/pkg/pricing"
+	"github.com/jharrington22/aws-resource/pkg/resource"
+)
+
+// ResourceRecord is the machine readable form of a resource.
+type ResourceRecord struct {
+	Account    string            `json:"account,omitempty"`
+	Type       string            `json:"type"`
+	ID         string            `json:"id"`
+	Region     string            `json:"region"`
+	Name       string            `json:"name,omitempty"`
+	State      string            `json:"state,omitempty"`
+	Stack      string            `json:"stack,omitempty"`
+	CreatedAt  *time.Time        `json:"createdAt,omitempty"`
+	Tags       map[string]string `json:"tags,omitempty"`
+	Properties map[string]string `json:"properties,omitempty"`
+
+	// HourlyCost and MonthlyCost are only set when costs are estimated and
+	// the resource has a known price.
+	HourlyCost  *float64 `json:"hourlyCost,omitempty"`
+	MonthlyCost *float64 `json:"monthlyCost,omitempty"`
+}
+
+// NewResourceRecord converts a resource into its record.
+func NewResourceRecord(r *resource.Resource) ResourceRecord {
+	record := ResourceRecord{
+		Account:    r.Account,
+		Type:       r.Type,
+		ID:         r.ID,
+		Region:     r.Region,
+		Name:       r.Name,
+		State:      r.State,
+		Stack:      resource.Stack(r),
+		Tags:       r.Tags,
+		Properties: r.Properties,
+	}
+	if !r.CreatedAt.IsZero() {
+		created := r.CreatedAt.UTC()
+		record.CreatedAt = &created
+	}
+	return record
+}
+
+// WriteResources writes the resources to w in the given format. The csv and
+// table formats have a column for each property found in any resource, an
+// account column when resources of several accounts are written, and a stack
+// column when resources belong to CloudFormation stacks. When prices is set
+// the estimated cost of each resource is included.
+func WriteResources(w io.Writer, format Format, resources []*resource.Resource, prices *pricing.Table) error {
+	records := make([]ResourceRecord, 0, len(resources))
+	keys := map[string]bool{}
+	var accounts, stacks bool
+	for _, r := range resources {
+		accounts = accounts || r.Account != ""
+		stacks = stacks || resource.Stack(r) != ""
+		record := NewResourceRecord(r)
+		if prices != nil {
+			if cost, ok := prices.Estimate(r); ok {
+				hourly, monthly := cost.Hourly, cost.Monthly()
+				record.HourlyCost = &hourly
+				record.MonthlyCost = &monthly
+			}
+		}
+		records = append(records, record)
+		for k := range r.Properties {
+			keys[k] = true
+		}
+	}
+
+	var properties []string
+	for k := range keys {
+		properties = append(properties, k)
+	}
+	sort.Strings(properties)
+
+	var headers []string
+	if accounts {
+		headers = append(headers, "account")
+	}
+	headers = append(headers, "type", "region", "id", "name", "state", "created")
+	if stacks {
+		headers = append(headers, "stack")
+	}
+	if prices != nil {
+		headers = append(headers, "hourly-cost", "monthly-cost")
+	}
+	rows := Rows{
+		Headers: append(headers, properties...),
+	}
+	for _, r := range records {
+		var created string
+		if r.CreatedAt != nil {
+			created = r.CreatedAt.Format(time.RFC3339)
+		}
+		var row []string
+		if accounts {
+			row = append(row, r.Account)
+		}
+		row = append(row, r.Type, r.Region, r.ID, r.Name, r.State, created)
+		if stacks {
+			row = append(row, r.Stack)
+		}
+		if prices != nil {
+			row = append(row, formatCost(r.HourlyCost, 4), formatCost(r.MonthlyCost, 2))
+		}
+		for _, k := range properties {
+			row = append(row, r.Properties[k])
+		}
+		rows.Values = append(rows.Values, row)
+	}
+
+	return Write(w, format, records, rows)
+}
+
+// formatCost formats an optional cost, an unknown cost is left empty.
+func formatCost(cost *float64, precision int) string {
+	if cost == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*cost, 'f', precision, 64)
+}
@@ -0,0 +1,119 @@
+// Package plan records the exact set of resources a delete command would
+// remove so that the deletion can be reviewed and applied later.
+package plan
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/jharrington22/aws-resource/pkg/resource"
+)
+
+const (
+	// Version is the version of the plan file format.
+	Version = 1
+
+	// Delete is the action deleting a resource.
+	Delete = "delete"
+)
+
+// Action is a single operation of a plan.
+type Action struct {
+	Action string            `json:"action"`
+	Type   string            `json:"type"`
+	ID     string            `json:"id"`
+	Region string            `json:"region"`
+	Name   string            `json:"name,omitempty"`
+	Tags   map[string]string `json:"tags,omitempty"`
+
+	// Properties relate the resource to the other resources of the plan,
+	// such as the snapshots backing an image.
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+// Resource returns the resource the action applies to.
+func (a Action) Resource() *resource.Resource {
+	return &resource.Resource{
+		Type:       a.Type,
+		ID:         a.ID,
+		Region:     a.Region,
+		Name:       a.Name,
+		Tags:       a.Tags,
+		Properties: a.Properties,
+	}
+}
+
+// Plan is an ordered list of actions to run against a single account.
+type Plan struct {
+	Version   int       `json:"version"`
+	AccountID string    `json:"accountId"`
+	CreatedAt time.Time `json:"createdAt"`
+	Actions   []Action  `json:"actions"`
+}
+
+// New creates an empty plan for the account.
+func New(accountID string) *Plan {
+	return &Plan{
+		Version:   Version,
+		AccountID: accountID,
+		CreatedAt: time.Now().UTC(),
+		Actions:   []Action{},
+	}
+}
+
+// Delete adds the deletion of the resource to the plan.
+func (p *Plan) Delete(r *resource.Resource) {
+	p.Actions = append(p.Actions, Action{
+		Action:     Delete,
+		Type:       r.Type,
+		ID:         r.ID,
+		Region:     r.Region,
+		Name:       r.Name,
+		Tags:       r.Tags,
+		Properties: r.Properties,
+	})
+}
+
+// Verify returns an error if the plan wasn't created for the account.
+func (p *Plan) Verify(accountID string) error {
+	if p.AccountID != accountID {
+		return fmt.Errorf("plan was created for account %s but the current account is %s", p.AccountID, accountID)
+	}
+	return nil
+}
+
+// Save writes the plan to path.
+func (p *Plan) Save(path string) error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append(data, '\n'), 0600)
+}
+
+// Load reads the plan saved at path.
+func Load(path string) (*Plan, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Plan{}
+	if err := json.Unmarshal(data, p); err != nil {
+		return nil, fmt.Errorf("unable to parse plan %s: %s", path, err)
+	}
+	if p.Version != Version {
+		return nil, fmt.Errorf("unsupported plan version %d in %s", p.Version, path)
+	}
+	if p.AccountID == "" {
+		return nil, fmt.Errorf("plan %s doesn't record an account ID", path)
+	}
+	for i, a := range p.Actions {
+		if a.Action != Delete {
+			return nil, fmt.Errorf("unsupported action %q at position %d in %s", a.Action, i, path)
+		}
+	}
+	return p, nil
+}
@@ -0,0 +1,220 @@
+// Package pricing estimates the cost of resources offline from a price table
+// embedded in the binary, which can be replaced by an updated table.
+package pricing
+
+import (
+	_ "embed"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+
+	"github.com/jharrington22/aws-resource/pkg/resource"
+	"sigs.k8s.io/yaml"
+)
+
+// HoursPerMonth is the number of hours AWS uses to convert between hourly
+// and monthly prices.
+const HoursPerMonth = 730
+
+//go:embed prices.yaml
+var embedded []byte
+
+// Table holds the prices of the supported resource types. Prices of a
+// region found under Regions take precedence over the default ones.
+type Table struct {
+	Currency string `json:"currency"`
+	Updated  string `json:"updated,omitempty"`
+
+	// Instances is the hourly price by instance type.
+	Instances map[string]float64 `json:"instances,omitempty"`
+
+	// Volumes is the price per GB-month by volume type.
+	Volumes map[string]float64 `json:"volumes,omitempty"`
+
+	// Snapshots is the price per GB-month of snapshot storage.
+	Snapshots float64 `json:"snapshots,omitempty"`
+
+	// LoadBalancers is the hourly price by load balancer type, one of
+	// classic, application, network or gateway.
+	LoadBalancers map[string]float64 `json:"loadBalancers,omitempty"`
+
+	// HostedZones is the monthly price of a hosted zone.
+	HostedZones float64 `json:"hostedZones,omitempty"`
+
+	// NatGateways is the hourly price of a NAT gateway.
+	NatGateways float64 `json:"natGateways,omitempty"`
+
+	// ElasticIPs is the hourly price of an Elastic IP that isn't associated.
+	ElasticIPs float64 `json:"elasticIps,omitempty"`
+
+	Regions map[string]*Table `json:"regions,omitempty"`
+}
+
+// Default returns the embedded price table.
+func Default() *Table {
+	table, err := Parse(embedded)
+	if err != nil {
+		panic(fmt.Sprintf("invalid embedded price table: %s", err))
+	}
+	return table
+}
+
+// Load reads a price table from a file.
+func Load(path string) (*Table, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read price table: %s", err)
+	}
+	table, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid price table %s: %s", path, err)
+	}
+	return table, nil
+}
+
+// Parse parses a price table in yaml or json.
+func Parse(data []byte) (*Table, error) {
+	table := &Table{}
+	if err := yaml.UnmarshalStrict(data, table); err != nil {
+		return nil, err
+	}
+	if table.Currency == "" {
+		table.Currency = "USD"
+	}
+	return table, nil
+}
+
+// Cost is the estimated cost of a resource.
+type Cost struct {
+	Hourly float64
+}
+
+// Monthly returns the cost over a month of HoursPerMonth hours.
+func (c Cost) Monthly() float64 {
+	return c.Hourly * HoursPerMonth
+}
+
+// Add returns the sum of both costs.
+func (c Cost) Add(o Cost) Cost {
+	return Cost{Hourly: c.Hourly + o.Hourly}
+}
+
+// Estimate returns the cost of running the resource, and false when the
+// table has no price for it. Images are free as the storage of their
+// snapshots is charged to the snapshots, as are associated Elastic IPs, and
+// snapshots are charged for the full size of their volume as the size of the
+// changed blocks isn't known.
+func (t *Table) Estimate(r *resource.Resource) (Cost, bool) {
+	switch r.Type {
+	case "ec2":
+		price, ok := t.instance(r.Region, r.Properties["instance-type"])
+		return Cost{Hourly: price}, ok
+	case "volumes":
+		price, ok := t.volume(r.Region, r.Properties["volume-type"])
+		size, err := strconv.ParseFloat(r.Properties["size"], 64)
+		if !ok || err != nil {
+			return Cost{}, false
+		}
+		return Cost{Hourly: price * size / HoursPerMonth}, true
+	case "snapshots":
+		price := t.snapshot(r.Region)
+		size, err := strconv.ParseFloat(r.Properties["volume-size"], 64)
+		if price == 0 || err != nil {
+			return Cost{}, false
+		}
+		return Cost{Hourly: price * size / HoursPerMonth}, true
+	case "images":
+		return Cost{}, true
+	case "elb":
+		price, ok := t.loadBalancer(r.Region, "classic")
+		return Cost{Hourly: price}, ok
+	case "elbv2":
+		price, ok := t.loadBalancer(r.Region, r.Properties["type"])
+		return Cost{Hourly: price}, ok
+	case "route53":
+		price := t.hostedZone(r.Region)
+		return Cost{Hourly: price / HoursPerMonth}, price != 0
+	case "natgateways":
+		price := t.natGateway(r.Region)
+		return Cost{Hourly: price}, price != 0
+	case "eips":
+		if r.Properties["associated"] == "true" {
+			return Cost{}, true
+		}
+		price := t.elasticIP(r.Region)
+		return Cost{Hourly: price}, price != 0
+	}
+	return Cost{}, false
+}
+
+// tables returns the tables to look prices up in for the region, the
+// region's own prices first.
+func (t *Table) tables(region string) []*Table {
+	if regional, ok := t.Regions[region]; ok && regional != nil {
+		return []*Table{regional, t}
+	}
+	return []*Table{t}
+}
+
+func (t *Table) instance(region, instanceType string) (float64, bool) {
+	for _, table := range t.tables(region) {
+		if price, ok := table.Instances[instanceType]; ok {
+			return price, true
+		}
+	}
+	return 0, false
+}
+
+func (t *Table) volume(region, volumeType string) (float64, bool) {
+	for _, table := range t.tables(region) {
+		if price, ok := table.Volumes[volumeType]; ok {
+			return price, true
+		}
+	}
+	return 0, false
+}
+
+func (t *Table) loadBalancer(region, lbType string) (float64, bool) {
+	for _, table := range t.tables(region) {
+		if price, ok := table.LoadBalancers[lbType]; ok {
+			return price, true
+		}
+	}
+	return 0, false
+}
+
+func (t *Table) snapshot(region string) float64 {
+	for _, table := range t.tables(region) {
+		if table.Snapshots != 0 {
+			return table.Snapshots
+		}
+	}
+	return 0
+}
+
+func (t *Table) hostedZone(region string) float64 {
+	for _, table := range t.tables(region) {
+		if table.HostedZones != 0 {
+			return table.HostedZones
+		}
+	}
+	return 0
+}
+
+func (t *Table) natGateway(region string) float64 {
+	for _, table := range t.tables(region) {
+		if table.NatGateways != 0 {
+			return table.NatGateways
+		}
+	}
+	return 0
+}
+
+func (t *Table) elasticIP(region string) float64 {
+	for _, table := range t.tables(region) {
+		if table.ElasticIPs != 0 {
+			return table.ElasticIPs
+		}
+	}
+	return 0
+}
@@ -0,0 +1,114 @@
+package pricing
+
+import (
+	"math"
+	"testing"
+
+	"github.com/jharrington22/aws-resource/pkg/resource"
+)
+
+func TestEstimate(t *testing.T) {
+	table := Default()
+	table.Regions = map[string]*Table{
+		"eu-west-1": {Instances: map[string]float64{"m5.xlarge": 0.214}},
+	}
+
+	tests := []struct {
+		name     string
+		resource *resource.Resource
+		hourly   float64
+		known    bool
+	}{
+		{
+			name:     "instance",
+			resource: &resource.Resource{Type: "ec2", Region: "us-east-1", Properties: map[string]string{"instance-type": "m5.xlarge"}},
+			hourly:   0.192,
+			known:    true,
+		},
+		{
+			name:     "regional instance",
+			resource: &resource.Resource{Type: "ec2", Region: "eu-west-1", Properties: map[string]string{"instance-type": "m5.xlarge"}},
+			hourly:   0.214,
+			known:    true,
+		},
+		{
+			name:     "regional default",
+			resource: &resource.Resource{Type: "ec2", Region: "eu-west-1", Properties: map[string]string{"instance-type": "t3.micro"}},
+			hourly:   0.0104,
+			known:    true,
+		},
+		{
+			name:     "unknown instance type",
+			resource: &resource.Resource{Type: "ec2", Region: "us-east-1", Properties: map[string]string{"instance-type": "x9.huge"}},
+		},
+		{
+			name:     "volume",
+			resource: &resource.Resource{Type: "volumes", Region: "us-east-1", Properties: map[string]string{"volume-type": "gp3", "size": "100"}},
+			hourly:   8.0 / HoursPerMonth,
+			known:    true,
+		},
+		{
+			name:     "snapshot",
+			resource: &resource.Resource{Type: "snapshots", Region: "us-east-1", Properties: map[string]string{"volume-size": "10"}},
+			hourly:   0.5 / HoursPerMonth,
+			known:    true,
+		},
+		{
+			name:     "network load balancer",
+			resource: &resource.Resource{Type: "elbv2", Region: "us-east-1", Properties: map[string]string{"type": "network"}},
+			hourly:   0.0225,
+			known:    true,
+		},
+		{
+			name:     "hosted zone",
+			resource: &resource.Resource{Type: "route53", Region: resource.GlobalRegion},
+			hourly:   0.5 / HoursPerMonth,
+			known:    true,
+		},
+		{
+			name:     "nat gateway",
+			resource: &resource.Resource{Type: "natgateways", Region: "us-east-1"},
+			hourly:   0.045,
+			known:    true,
+		},
+		{
+			name:     "unassociated elastic ip",
+			resource: &resource.Resource{Type: "eips", Region: "us-east-1", Properties: map[string]string{"associated": "false"}},
+			hourly:   0.005,
+			known:    true,
+		},
+		{
+			name:     "associated elastic ip",
+			resource: &resource.Resource{Type: "eips", Region: "us-east-1", Properties: map[string]string{"associated": "true"}},
+			known:    true,
+		},
+		{
+			name:     "unknown type",
+			resource: &resource.Resource{Type: "unknown", Region: "us-east-1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cost, known := table.Estimate(tt.resource)
+			if known != tt.known {
+				t.Fatalf("got known %t, want %t", known, tt.known)
+			}
+			if math.Abs(cost.Hourly-tt.hourly) > 1e-9 {
+				t.Fatalf("got %f per hour, want %f", cost.Hourly, tt.hourly)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	if _, err := Parse([]byte("instance:\n  m5.xlarge: 0.1\n")); err == nil {
+		t.Fatalf("expected unknown fields to be rejected")
+	}
+	table, err := Parse([]byte(`{"instances": {"m5.xlarge": 0.1}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if table.Currency != "USD" {
+		t.Fatalf("expected currency to default to USD, got %q", table.Currency)
+	}
+}
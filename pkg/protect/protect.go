@@ -0,0 +1,165 @@
+// Package protect decides which resources must never be deleted, either
+// because they carry a protection tag or because they match an entry of a
+// deny-list file.
+package protect
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/jharrington22/aws-resource/pkg/resource"
+	"github.com/jharrington22/aws-resource/pkg/selector"
+)
+
+// DefaultTag protects the resources tagged with it whatever the tags given
+// with --protected-tags.
+const DefaultTag = "aws-resource/protect=true"
+
+// DefaultDenyList is the name of the deny-list file looked up in the home
+// directory.
+const DefaultDenyList = ".aws-resource-deny-list"
+
+// Policy matches the resources that must never be deleted.
+type Policy struct {
+	Tags []selector.Requirement
+	Deny []Entry
+}
+
+// New returns a policy protecting the resources with DefaultTag or one of
+// the tags, given as key, matching any value, or key=value.
+func New(tags []string) (*Policy, error) {
+	policy := &Policy{}
+	for _, tag := range append([]string{DefaultTag}, tags...) {
+		sel, err := selector.Parse(tag)
+		if err != nil || len(sel) != 1 || (sel[0].Operator != selector.Exists && sel[0].Operator != selector.Equals) {
+			return nil, fmt.Errorf("invalid protected tag %q, expected key or key=value", tag)
+		}
+		policy.Tags = append(policy.Tags, sel[0])
+	}
+	return policy, nil
+}
+
+// Protected returns why the resource is protected, if it is.
+func (p *Policy) Protected(r *resource.Resource) (string, bool) {
+	for _, requirement := range p.Tags {
+		if requirement.Matches(r.Tags) {
+			return "tag " + requirement.String(), true
+		}
+	}
+	for _, entry := range p.Deny {
+		if entry.Matches(r) {
+			return "deny-list entry " + entry.String(), true
+		}
+	}
+	return "", false
+}
+
+// Entry is a line of a deny-list file. It's either a pattern matched against
+// the ID and name of resources of every type, or a type followed by a
+// pattern only matching resources of that type. Patterns match whole
+// strings, with * matching any sequence of characters and ? a single one.
+type Entry struct {
+	Type    string
+	Pattern string
+
+	re *regexp.Regexp
+}
+
+// NewEntry returns an entry matching resources of the type, or of every type
+// when it's empty, whose ID or name matches the pattern.
+func NewEntry(typ, pattern string) Entry {
+	expr := regexp.QuoteMeta(pattern)
+	expr = strings.ReplaceAll(expr, `\*`, ".*")
+	expr = strings.ReplaceAll(expr, `\?`, ".")
+	return Entry{
+		Type:    typ,
+		Pattern: pattern,
+		re:      regexp.MustCompile("^" + expr + "$"),
+	}
+}
+
+// Matches reports whether the entry matches the resource.
+func (e Entry) Matches(r *resource.Resource) bool {
+	if e.Type != "" && e.Type != r.Type {
+		return false
+	}
+	return e.re.MatchString(r.ID) || (r.Name != "" && e.re.MatchString(r.Name))
+}
+
+// String returns the entry as written in a deny-list file.
+func (e Entry) String() string {
+	if e.Type == "" {
+		return e.Pattern
+	}
+	return e.Type + " " + e.Pattern
+}
+
+// DefaultDenyListPath returns the path of the deny-list file in the home
+// directory.
+func DefaultDenyListPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, DefaultDenyList), nil
+}
+
+// LoadDenyList reads a deny-list file. When optional is set a missing file
+// returns no entries.
+func LoadDenyList(path string, optional bool) ([]Entry, error) {
+	data, err := ioutil.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) && optional {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read deny-list: %s", err)
+	}
+	entries, err := ParseDenyList(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid deny-list %s: %s", path, err)
+	}
+	return entries, nil
+}
+
+// ParseDenyList parses the entries of a deny-list file, one per line. Empty
+// lines and text following a # are ignored.
+func ParseDenyList(data []byte) ([]Entry, error) {
+	var entries []Entry
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		switch len(fields) {
+		case 0:
+		case 1:
+			entries = append(entries, NewEntry("", fields[0]))
+		case 2:
+			if !known(fields[0]) {
+				return nil, fmt.Errorf("line %d: unknown resource type %q", n, fields[0])
+			}
+			entries = append(entries, NewEntry(fields[0], fields[1]))
+		default:
+			return nil, fmt.Errorf("line %d: expected a pattern, optionally preceded by a type", n)
+		}
+	}
+	return entries, scanner.Err()
+}
+
+func known(typ string) bool {
+	for _, t := range resource.Types() {
+		if t == typ {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+package protect
+
+import (
+	"testing"
+
+	"github.com/jharrington22/aws-resource/pkg/resource"
+)
+
+func TestProtected(t *testing.T) {
+	policy, err := New([]string{"keep", "owner=platform"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	policy.Deny, err = ParseDenyList([]byte(`
+# Production database
+i-0123456789abcdef0
+images prod-*   # golden images
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		resource *resource.Resource
+		reason   string
+	}{
+		{
+			name:     "default tag",
+			resource: &resource.Resource{Type: "ec2", ID: "i-1", Tags: map[string]string{"aws-resource/protect": "true"}},
+			reason:   "tag aws-resource/protect=true",
+		},
+		{
+			name:     "default tag with another value",
+			resource: &resource.Resource{Type: "ec2", ID: "i-1", Tags: map[string]string{"aws-resource/protect": "false"}},
+		},
+		{
+			name:     "key",
+			resource: &resource.Resource{Type: "ec2", ID: "i-1", Tags: map[string]string{"keep": ""}},
+			reason:   "tag keep",
+		},
+		{
+			name:     "key and value",
+			resource: &resource.Resource{Type: "ec2", ID: "i-1", Tags: map[string]string{"owner": "platform"}},
+			reason:   "tag owner=platform",
+		},
+		{
+			name:     "id",
+			resource: &resource.Resource{Type: "ec2", ID: "i-0123456789abcdef0"},
+			reason:   "deny-list entry i-0123456789abcdef0",
+		},
+		{
+			name:     "name pattern",
+			resource: &resource.Resource{Type: "images", ID: "ami-1", Name: "prod-base-2022"},
+			reason:   "deny-list entry images prod-*",
+		},
+		{
+			name:     "name pattern of another type",
+			resource: &resource.Resource{Type: "snapshots", ID: "snap-1", Name: "prod-base-2022"},
+		},
+		{
+			name:     "partial match",
+			resource: &resource.Resource{Type: "images", ID: "ami-1", Name: "staging-prod-base"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason, protected := policy.Protected(tt.resource)
+			if protected != (tt.reason != "") || reason != tt.reason {
+				t.Fatalf("got %q, %t, want %q", reason, protected, tt.reason)
+			}
+		})
+	}
+}
+
+func TestParseDenyList(t *testing.T) {
+	for _, data := range []string{"ec2 i-1 i-2\n", "widgets my-widget\n"} {
+		if _, err := ParseDenyList([]byte(data)); err == nil {
+			t.Errorf("expected %q to be rejected", data)
+		}
+	}
+	if _, err := New([]string{"env!=prod"}); err == nil {
+		t.Errorf("expected an invalid protected tag to be rejected")
+	}
+}
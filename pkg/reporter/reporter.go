@@ -31,13 +31,14 @@ func (b *Builder) Build() (result *Object, err error) {
 	return
 }
 
-// Infof prints an informative message with the given format and arguments.
+// Infof prints an informative message with the given format and arguments. Messages are written
+// to the standard error stream so that the standard output only contains machine readable output.
 func (r *Object) Infof(format string, args ...interface{}) {
 	message := fmt.Sprintf(format, args...)
 	if r.useColors() {
-		_, _ = fmt.Fprintf(os.Stdout, "%s%s\n", infoPrefix, message)
+		_, _ = fmt.Fprintf(os.Stderr, "%s%s\n", infoPrefix, message)
 	} else {
-		_, _ = fmt.Fprintf(os.Stdout, "%s%s\n", "INFO: ", message)
+		_, _ = fmt.Fprintf(os.Stderr, "%s%s\n", "INFO: ", message)
 	}
 }
 
@@ -84,11 +85,23 @@ func (r *Object) useColors() bool {
 // Determine whether the reporter output is meant for the terminal
 // or whether it's piped or redirected to a file.
 func (r *Object) IsTerminal() bool {
-	stdout, err := os.Stdout.Stat()
+	return isTerminal(os.Stdout)
+}
+
+// IsInputTerminal determines whether the standard input is a terminal an
+// operator can answer prompts from, so that confirmations fail closed.
+func (r *Object) IsInputTerminal() bool {
+	return isTerminal(os.Stdin)
+}
+
+// isTerminal determines whether the file is a terminal. A file that can't be
+// inspected isn't one.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
 	if err != nil {
-		return true
+		return false
 	}
-	return (stdout.Mode()&os.ModeDevice != 0) && (stdout.Mode()&os.ModeNamedPipe == 0)
+	return (info.Mode()&os.ModeDevice != 0) && (info.Mode()&os.ModeNamedPipe == 0)
 }
 
 // CreateReporterOrExit creates the reportor instance or exits to the console
@@ -0,0 +1,135 @@
+package resource
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/jharrington22/aws-resource/pkg/aws"
+)
+
+func init() {
+	Register("ec2", func(clients aws.ClientFunc) Provider {
+		return &instances{clients: clients}
+	})
+}
+
+// instances provides running EC2 instances.
+type instances struct {
+	clients aws.ClientFunc
+}
+
+func (p *instances) Type() string     { return "ec2" }
+func (p *instances) Describe() string { return "running instances" }
+func (p *instances) Global() bool     { return false }
+
+func (p *instances) List(ctx context.Context, region string) ([]*Resource, error) {
+	client, err := p.clients(region)
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []*Resource
+	err = client.DescribeInstancesPages(&ec2.DescribeInstancesInput{}, func(page *ec2.DescribeInstancesOutput, lastPage bool) bool {
+		for _, r := range page.Reservations {
+			for _, i := range r.Instances {
+				if i.State == nil || value(i.State.Name) != ec2.InstanceStateNameRunning {
+					continue
+				}
+				resources = append(resources, p.resource(region, i))
+			}
+		}
+		return ctx.Err() == nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resources, ctx.Err()
+}
+
+// Get returns the instance whatever its state.
+func (p *instances) Get(ctx context.Context, region, id string) (*Resource, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	client, err := p.clients(region)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := client.DescribeInstances(&ec2.DescribeInstancesInput{
+		InstanceIds: []*string{&id},
+	})
+	if err != nil {
+		if isNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	for _, r := range output.Reservations {
+		for _, i := range r.Instances {
+			return p.resource(region, i), nil
+		}
+	}
+	return nil, nil
+}
+
+func (p *instances) resource(region string, i *ec2.Instance) *Resource {
+	tags := ec2Tags(i.Tags)
+	var volumes []string
+	for _, bdm := range i.BlockDeviceMappings {
+		if bdm.Ebs != nil && bdm.Ebs.VolumeId != nil {
+			volumes = append(volumes, *bdm.Ebs.VolumeId)
+		}
+	}
+	var state string
+	if i.State != nil {
+		state = value(i.State.Name)
+	}
+	return &Resource{
+		Type:      p.Type(),
+		ID:        value(i.InstanceId),
+		Region:    region,
+		Name:      tags["Name"],
+		State:     state,
+		CreatedAt: timeValue(i.LaunchTime),
+		Tags:      tags,
+		Properties: map[string]string{
+			"instance-type": value(i.InstanceType),
+			"image-id":      value(i.ImageId),
+			"volumes":       strings.Join(volumes, ","),
+		},
+		Raw: i,
+	}
+}
+
+func (p *instances) Delete(ctx context.Context, r *Resource, dryRun bool) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	client, err := p.clients(r.Region)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.TerminateInstances(&ec2.TerminateInstancesInput{
+		DryRun:      &dryRun,
+		InstanceIds: []*string{&r.ID},
+	})
+	if err != nil && !(dryRun && isDryRun(err)) {
+		return err
+	}
+	return nil
+}
+
+// WaitDeleted waits for the instance to be terminated, which detaches its
+// volumes.
+func (p *instances) WaitDeleted(ctx context.Context, r *Resource) error {
+	client, err := p.clients(r.Region)
+	if err != nil {
+		return err
+	}
+	return client.WaitUntilInstanceTerminatedWithContext(ctx, &ec2.DescribeInstancesInput{
+		InstanceIds: []*string{&r.ID},
+	})
+}
@@ -0,0 +1,130 @@
+package resource
+
+import (
+	"context"
+	"net"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/jharrington22/aws-resource/pkg/aws"
+)
+
+const (
+	// AddressInUse is the error code returned when releasing an Elastic IP
+	// that is still associated.
+	AddressInUse = "InvalidIPAddress.InUse"
+)
+
+func init() {
+	Register("eips", func(clients aws.ClientFunc) Provider {
+		return &addresses{clients: clients}
+	})
+}
+
+// addresses provides Elastic IPs, identified by their allocation ID or, for
+// EC2-Classic addresses, their public IP.
+type addresses struct {
+	clients aws.ClientFunc
+}
+
+func (p *addresses) Type() string     { return "eips" }
+func (p *addresses) Describe() string { return "Elastic IPs" }
+func (p *addresses) Global() bool     { return false }
+
+func (p *addresses) List(ctx context.Context, region string) ([]*Resource, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	client, err := p.clients(region)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := client.DescribeAddresses(&ec2.DescribeAddressesInput{})
+	if err != nil {
+		return nil, err
+	}
+	var resources []*Resource
+	for _, a := range output.Addresses {
+		resources = append(resources, p.resource(region, a))
+	}
+	return resources, ctx.Err()
+}
+
+// Get returns the address.
+func (p *addresses) Get(ctx context.Context, region, id string) (*Resource, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	client, err := p.clients(region)
+	if err != nil {
+		return nil, err
+	}
+
+	input := &ec2.DescribeAddressesInput{AllocationIds: []*string{&id}}
+	if isPublicIP(id) {
+		input = &ec2.DescribeAddressesInput{PublicIps: []*string{&id}}
+	}
+	output, err := client.DescribeAddresses(input)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	for _, a := range output.Addresses {
+		return p.resource(region, a), nil
+	}
+	return nil, nil
+}
+
+func (p *addresses) resource(region string, a *ec2.Address) *Resource {
+	tags := ec2Tags(a.Tags)
+	id := value(a.AllocationId)
+	if id == "" {
+		id = value(a.PublicIp)
+	}
+	return &Resource{
+		Type:   p.Type(),
+		ID:     id,
+		Region: region,
+		Name:   tags["Name"],
+		Tags:   tags,
+		Properties: map[string]string{
+			"public-ip":            value(a.PublicIp),
+			"domain":               value(a.Domain),
+			"associated":           strconv.FormatBool(a.AssociationId != nil || a.InstanceId != nil),
+			"instance-id":          value(a.InstanceId),
+			"network-interface-id": value(a.NetworkInterfaceId),
+		},
+		Raw: a,
+	}
+}
+
+// Delete releases the address. Associated addresses fail with an
+// AddressInUse error which is returned unchanged.
+func (p *addresses) Delete(ctx context.Context, r *Resource, dryRun bool) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	client, err := p.clients(r.Region)
+	if err != nil {
+		return err
+	}
+
+	input := &ec2.ReleaseAddressInput{DryRun: &dryRun, AllocationId: &r.ID}
+	if isPublicIP(r.ID) {
+		input = &ec2.ReleaseAddressInput{DryRun: &dryRun, PublicIp: &r.ID}
+	}
+	_, err = client.ReleaseAddress(input)
+	if err != nil && !(dryRun && isDryRun(err)) {
+		return err
+	}
+	return nil
+}
+
+// isPublicIP reports whether the ID of an address is its public IP rather
+// than an allocation ID.
+func isPublicIP(id string) bool {
+	return net.ParseIP(id) != nil
+}
@@ -0,0 +1,382 @@
+package resource
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/eks"
+	"github.com/jharrington22/aws-resource/pkg/aws"
+)
+
+// Kinds of EKS resources.
+const (
+	EKSCluster        = "cluster"
+	EKSNodegroup      = "nodegroup"
+	EKSFargateProfile = "fargate-profile"
+)
+
+// EKSDeleteTimeout bounds the time waited for an EKS cluster, nodegroup or
+// Fargate profile to be deleted.
+const EKSDeleteTimeout = time.Hour
+
+func init() {
+	Register("eks", func(clients aws.ClientFunc) Provider {
+		return &kubernetes{clients: clients}
+	})
+}
+
+// kubernetes provides EKS clusters along with their nodegroups and Fargate
+// profiles, which must be deleted before the cluster. All are identified by
+// their ARN as nodegroups and profiles of different clusters can share a
+// name.
+type kubernetes struct {
+	clients aws.ClientFunc
+}
+
+func (p *kubernetes) Type() string     { return "eks" }
+func (p *kubernetes) Describe() string { return "EKS clusters, nodegroups and Fargate profiles" }
+func (p *kubernetes) Global() bool     { return false }
+
+func (p *kubernetes) List(ctx context.Context, region string) ([]*Resource, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	client, err := p.clients(region)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []*string
+	input := &eks.ListClustersInput{}
+	for {
+		output, err := client.ListClusters(input)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, output.Clusters...)
+		if output.NextToken == nil || ctx.Err() != nil {
+			break
+		}
+		input.NextToken = output.NextToken
+	}
+
+	var resources []*Resource
+	for _, name := range names {
+		if ctx.Err() != nil {
+			break
+		}
+		cluster, err := client.DescribeCluster(&eks.DescribeClusterInput{Name: name})
+		if err != nil {
+			if isEKSNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+
+		nodegroups, err := listNodegroups(client, name)
+		if err != nil {
+			return nil, err
+		}
+		profiles, err := listFargateProfiles(client, name)
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, p.cluster(region, cluster.Cluster, len(nodegroups), len(profiles)))
+
+		for _, n := range nodegroups {
+			output, err := client.DescribeNodegroup(&eks.DescribeNodegroupInput{ClusterName: name, NodegroupName: n})
+			if err != nil {
+				if isEKSNotFound(err) {
+					continue
+				}
+				return nil, err
+			}
+			resources = append(resources, p.nodegroup(region, output.Nodegroup))
+		}
+		for _, n := range profiles {
+			output, err := client.DescribeFargateProfile(&eks.DescribeFargateProfileInput{ClusterName: name, FargateProfileName: n})
+			if err != nil {
+				if isEKSNotFound(err) {
+					continue
+				}
+				return nil, err
+			}
+			resources = append(resources, p.fargateProfile(region, output.FargateProfile))
+		}
+	}
+	return resources, ctx.Err()
+}
+
+// Get returns the cluster, nodegroup or Fargate profile whose ARN is id.
+func (p *kubernetes) Get(ctx context.Context, region, id string) (*Resource, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	client, err := p.clients(region)
+	if err != nil {
+		return nil, err
+	}
+
+	kind, cluster, name := eksResource(id)
+	switch kind {
+	case EKSCluster:
+		output, err := client.DescribeCluster(&eks.DescribeClusterInput{Name: &name})
+		if err != nil {
+			return nil, ignoreEKSNotFound(err)
+		}
+		nodegroups, err := listNodegroups(client, &name)
+		if err != nil {
+			return nil, ignoreEKSNotFound(err)
+		}
+		profiles, err := listFargateProfiles(client, &name)
+		if err != nil {
+			return nil, ignoreEKSNotFound(err)
+		}
+		return p.cluster(region, output.Cluster, len(nodegroups), len(profiles)), nil
+	case EKSNodegroup:
+		output, err := client.DescribeNodegroup(&eks.DescribeNodegroupInput{ClusterName: &cluster, NodegroupName: &name})
+		if err != nil {
+			return nil, ignoreEKSNotFound(err)
+		}
+		return p.nodegroup(region, output.Nodegroup), nil
+	case EKSFargateProfile:
+		output, err := client.DescribeFargateProfile(&eks.DescribeFargateProfileInput{ClusterName: &cluster, FargateProfileName: &name})
+		if err != nil {
+			return nil, ignoreEKSNotFound(err)
+		}
+		return p.fargateProfile(region, output.FargateProfile), nil
+	}
+	return nil, fmt.Errorf("unknown EKS resource %s", id)
+}
+
+func (p *kubernetes) cluster(region string, c *eks.Cluster, nodegroups, profiles int) *Resource {
+	return &Resource{
+		Type:      p.Type(),
+		ID:        value(c.Arn),
+		Region:    region,
+		Name:      value(c.Name),
+		State:     value(c.Status),
+		CreatedAt: timeValue(c.CreatedAt),
+		Tags:      eksTags(c.Tags),
+		Properties: map[string]string{
+			"kind":             EKSCluster,
+			"version":          value(c.Version),
+			"platform-version": value(c.PlatformVersion),
+			"nodegroups":       strconv.Itoa(nodegroups),
+			"fargate-profiles": strconv.Itoa(profiles),
+		},
+		Raw: c,
+	}
+}
+
+func (p *kubernetes) nodegroup(region string, n *eks.Nodegroup) *Resource {
+	var instanceTypes []string
+	for _, t := range n.InstanceTypes {
+		instanceTypes = append(instanceTypes, value(t))
+	}
+	var desired int64
+	if n.ScalingConfig != nil {
+		desired = int64Value(n.ScalingConfig.DesiredSize)
+	}
+	return &Resource{
+		Type:      p.Type(),
+		ID:        value(n.NodegroupArn),
+		Region:    region,
+		Name:      value(n.NodegroupName),
+		State:     value(n.Status),
+		CreatedAt: timeValue(n.CreatedAt),
+		Tags:      eksTags(n.Tags),
+		Properties: map[string]string{
+			"kind":           EKSNodegroup,
+			"cluster":        eksClusterARN(value(n.NodegroupArn), value(n.ClusterName)),
+			"version":        value(n.Version),
+			"instance-types": strings.Join(instanceTypes, ","),
+			"capacity-type":  value(n.CapacityType),
+			"desired-size":   strconv.FormatInt(desired, 10),
+		},
+		Raw: n,
+	}
+}
+
+func (p *kubernetes) fargateProfile(region string, f *eks.FargateProfile) *Resource {
+	var namespaces []string
+	for _, s := range f.Selectors {
+		namespaces = append(namespaces, value(s.Namespace))
+	}
+	return &Resource{
+		Type:      p.Type(),
+		ID:        value(f.FargateProfileArn),
+		Region:    region,
+		Name:      value(f.FargateProfileName),
+		State:     value(f.Status),
+		CreatedAt: timeValue(f.CreatedAt),
+		Tags:      eksTags(f.Tags),
+		Properties: map[string]string{
+			"kind":       EKSFargateProfile,
+			"cluster":    eksClusterARN(value(f.FargateProfileArn), value(f.ClusterName)),
+			"namespaces": strings.Join(namespaces, ","),
+		},
+		Raw: f,
+	}
+}
+
+// Delete removes the cluster, nodegroup or Fargate profile and waits for it
+// to be deleted, as clusters can't be deleted while they have nodegroups or
+// Fargate profiles and only one profile of a cluster can be deleted at a
+// time. The resource is described again first, so that one already being
+// deleted, or already gone, is only waited for. As the API has no dry run, a
+// dry run only checks the resource still exists.
+func (p *kubernetes) Delete(ctx context.Context, r *Resource, dryRun bool) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	client, err := p.clients(r.Region)
+	if err != nil {
+		return err
+	}
+
+	kind, cluster, name := eksResource(r.ID)
+	current, err := p.Get(ctx, r.Region, r.ID)
+	if err != nil {
+		return err
+	}
+	if current == nil {
+		if dryRun {
+			return fmt.Errorf("%s %s doesn't exist anymore", kind, name)
+		}
+		return nil
+	}
+	if dryRun {
+		return nil
+	}
+
+	// The waiters poll until the timeout rather than for a number of
+	// attempts, nodegroups taking a while to drain their nodes
+	ctx, cancel := context.WithTimeout(ctx, EKSDeleteTimeout)
+	defer cancel()
+	wait := []request.WaiterOption{
+		request.WithWaiterMaxAttempts(0),
+		request.WithWaiterDelay(request.ConstantWaiterDelay(30 * time.Second)),
+	}
+	switch kind {
+	case EKSCluster:
+		if current.State != eks.ClusterStatusDeleting {
+			_, err = client.DeleteCluster(&eks.DeleteClusterInput{Name: &name})
+		}
+		if err == nil {
+			err = client.WaitUntilClusterDeletedWithContext(ctx, &eks.DescribeClusterInput{Name: &name}, wait...)
+		}
+	case EKSNodegroup:
+		input := &eks.DescribeNodegroupInput{ClusterName: &cluster, NodegroupName: &name}
+		if current.State != eks.NodegroupStatusDeleting {
+			_, err = client.DeleteNodegroup(&eks.DeleteNodegroupInput{ClusterName: &cluster, NodegroupName: &name})
+		}
+		if err == nil {
+			err = client.WaitUntilNodegroupDeletedWithContext(ctx, input, wait...)
+		}
+	case EKSFargateProfile:
+		input := &eks.DescribeFargateProfileInput{ClusterName: &cluster, FargateProfileName: &name}
+		if current.State != eks.FargateProfileStatusDeleting {
+			_, err = client.DeleteFargateProfile(&eks.DeleteFargateProfileInput{ClusterName: &cluster, FargateProfileName: &name})
+		}
+		if err == nil {
+			err = client.WaitUntilFargateProfileDeletedWithContext(ctx, input, wait...)
+		}
+	default:
+		return fmt.Errorf("unknown EKS resource %s", r.ID)
+	}
+	return ignoreEKSNotFound(err)
+}
+
+func listNodegroups(client aws.Client, cluster *string) ([]*string, error) {
+	var names []*string
+	input := &eks.ListNodegroupsInput{ClusterName: cluster}
+	for {
+		output, err := client.ListNodegroups(input)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, output.Nodegroups...)
+		if output.NextToken == nil {
+			return names, nil
+		}
+		input.NextToken = output.NextToken
+	}
+}
+
+func listFargateProfiles(client aws.Client, cluster *string) ([]*string, error) {
+	var names []*string
+	input := &eks.ListFargateProfilesInput{ClusterName: cluster}
+	for {
+		output, err := client.ListFargateProfiles(input)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, output.FargateProfileNames...)
+		if output.NextToken == nil {
+			return names, nil
+		}
+		input.NextToken = output.NextToken
+	}
+}
+
+// eksResource returns the kind, cluster name and name of the EKS resource
+// with the ARN, such as arn:aws:eks:<region>:<account>:nodegroup/<cluster>/<name>/<id>.
+func eksResource(arn string) (kind, cluster, name string) {
+	parts := strings.SplitN(arn, ":", 6)
+	if len(parts) < 6 {
+		return "", "", ""
+	}
+	path := strings.Split(parts[5], "/")
+	switch {
+	case path[0] == "cluster" && len(path) == 2:
+		return EKSCluster, path[1], path[1]
+	case path[0] == "nodegroup" && len(path) >= 3:
+		return EKSNodegroup, path[1], path[2]
+	case path[0] == "fargateprofile" && len(path) >= 3:
+		return EKSFargateProfile, path[1], path[2]
+	}
+	return "", "", ""
+}
+
+// eksClusterARN returns the ARN of the cluster, in the account and region
+// of the other ARN.
+func eksClusterARN(arn, cluster string) string {
+	parts := strings.SplitN(arn, ":", 6)
+	if len(parts) < 6 {
+		return cluster
+	}
+	return strings.Join(append(parts[:5], "cluster/"+cluster), ":")
+}
+
+func eksTags(tags map[string]*string) map[string]string {
+	result := map[string]string{}
+	for k, v := range tags {
+		if v != nil {
+			result[k] = *v
+		}
+	}
+	return result
+}
+
+// isEKSNotFound reports whether err is returned for an EKS resource that
+// doesn't exist.
+func isEKSNotFound(err error) bool {
+	var aerr awserr.Error
+	return errors.As(err, &aerr) && aerr.Code() == eks.ErrCodeResourceNotFoundException
+}
+
+// ignoreEKSNotFound returns err unless it's returned for an EKS resource
+// that doesn't exist.
+func ignoreEKSNotFound(err error) error {
+	if isEKSNotFound(err) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,48 @@
+package resource_test
+
+import (
+	"context"
+	"testing"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/eks"
+	"github.com/jharrington22/aws-resource/pkg/aws/fake"
+	"github.com/jharrington22/aws-resource/pkg/resource"
+)
+
+func TestEKSDeleteCurrentState(t *testing.T) {
+	backend := fake.New("us-east-1")
+	cluster := backend.AddEKSCluster("us-east-1", &eks.Cluster{Name: awssdk.String("sandbox")})
+	listed := backend.AddFargateProfile("us-east-1", &eks.FargateProfile{ClusterName: cluster.Name})
+	other := backend.AddFargateProfile("us-east-1", &eks.FargateProfile{ClusterName: cluster.Name})
+
+	p, err := resource.New("eks", backend.Clients())
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := resource.Get(context.Background(), p, "us-east-1", *listed.FargateProfileArn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Both profiles started being deleted after being listed, deleting the
+	// listed one again would fail as only one profile of a cluster can be
+	// deleted at a time
+	listed.Status = awssdk.String(eks.FargateProfileStatusDeleting)
+	other.Status = awssdk.String(eks.FargateProfileStatusDeleting)
+	if err := p.Delete(context.Background(), r, false); err != nil {
+		t.Fatalf("expected the profile being deleted to only be waited for, got %s", err)
+	}
+	profiles := backend.FargateProfiles("us-east-1")
+	if len(profiles) != 1 || *profiles[0].FargateProfileArn != *other.FargateProfileArn {
+		t.Errorf("expected only %s to be left, got %v", *other.FargateProfileName, profiles)
+	}
+
+	// Resources already gone are done
+	if err := p.Delete(context.Background(), r, false); err != nil {
+		t.Errorf("expected deleting a profile already gone to succeed, got %s", err)
+	}
+	if err := p.Delete(context.Background(), r, true); err == nil {
+		t.Errorf("expected a dry run of a profile already gone to fail")
+	}
+}
@@ -0,0 +1,168 @@
+package resource
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/jharrington22/aws-resource/pkg/aws"
+)
+
+// maxTagRequest is the maximum number of load balancers whose tags can be
+// described in a single request.
+const maxTagRequest = 20
+
+func init() {
+	Register("elb", func(clients aws.ClientFunc) Provider {
+		return &loadBalancers{clients: clients}
+	})
+}
+
+// loadBalancers provides classic load balancers.
+type loadBalancers struct {
+	clients aws.ClientFunc
+}
+
+func (p *loadBalancers) Type() string     { return "elb" }
+func (p *loadBalancers) Describe() string { return "running load balancers" }
+func (p *loadBalancers) Global() bool     { return false }
+
+func (p *loadBalancers) List(ctx context.Context, region string) ([]*Resource, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	client, err := p.clients(region)
+	if err != nil {
+		return nil, err
+	}
+
+	var lbs []*elb.LoadBalancerDescription
+	input := &elb.DescribeLoadBalancersInput{}
+	for {
+		output, err := client.DescribeLoadBalancers(input)
+		if err != nil {
+			return nil, err
+		}
+		lbs = append(lbs, output.LoadBalancerDescriptions...)
+		if output.NextMarker == nil || ctx.Err() != nil {
+			break
+		}
+		input.Marker = output.NextMarker
+	}
+
+	resources, err := p.resources(client, region, lbs)
+	if err != nil {
+		return nil, err
+	}
+	return resources, ctx.Err()
+}
+
+// Get returns the load balancer named id.
+func (p *loadBalancers) Get(ctx context.Context, region, id string) (*Resource, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	client, err := p.clients(region)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := client.DescribeLoadBalancers(&elb.DescribeLoadBalancersInput{
+		LoadBalancerNames: []*string{&id},
+	})
+	if err != nil {
+		if isNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	resources, err := p.resources(client, region, output.LoadBalancerDescriptions)
+	if err != nil || len(resources) == 0 {
+		return nil, err
+	}
+	return resources[0], nil
+}
+
+func (p *loadBalancers) resources(client aws.Client, region string, lbs []*elb.LoadBalancerDescription) ([]*Resource, error) {
+	tags, err := p.tags(client, lbs)
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []*Resource
+	for _, lb := range lbs {
+		var instances []string
+		for _, i := range lb.Instances {
+			instances = append(instances, value(i.InstanceId))
+		}
+		resources = append(resources, &Resource{
+			Type:      p.Type(),
+			ID:        value(lb.LoadBalancerName),
+			Region:    region,
+			Name:      value(lb.LoadBalancerName),
+			CreatedAt: timeValue(lb.CreatedTime),
+			Tags:      tags[value(lb.LoadBalancerName)],
+			Properties: map[string]string{
+				"dns-name":  value(lb.DNSName),
+				"scheme":    value(lb.Scheme),
+				"instances": strings.Join(instances, ","),
+			},
+			Raw: lb,
+		})
+	}
+	return resources, nil
+}
+
+// tags returns the tags of the load balancers keyed by name. DescribeTags
+// accepts at most 20 load balancers per request.
+func (p *loadBalancers) tags(client aws.Client, lbs []*elb.LoadBalancerDescription) (map[string]map[string]string, error) {
+	result := map[string]map[string]string{}
+	for start := 0; start < len(lbs); start += maxTagRequest {
+		end := start + maxTagRequest
+		if end > len(lbs) {
+			end = len(lbs)
+		}
+		input := &elb.DescribeTagsInput{}
+		for _, lb := range lbs[start:end] {
+			result[value(lb.LoadBalancerName)] = map[string]string{}
+			input.LoadBalancerNames = append(input.LoadBalancerNames, lb.LoadBalancerName)
+		}
+		output, err := client.DescribeLoadBalancerTags(input)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range output.TagDescriptions {
+			tags := map[string]string{}
+			for _, t := range d.Tags {
+				if t.Key != nil && t.Value != nil {
+					tags[*t.Key] = *t.Value
+				}
+			}
+			result[value(d.LoadBalancerName)] = tags
+		}
+	}
+	return result, nil
+}
+
+// Delete removes the load balancer, deregistering its instances. As the API
+// has no dry run, a dry run only checks the load balancer exists.
+func (p *loadBalancers) Delete(ctx context.Context, r *Resource, dryRun bool) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	client, err := p.clients(r.Region)
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		_, err = client.DescribeLoadBalancers(&elb.DescribeLoadBalancersInput{
+			LoadBalancerNames: []*string{&r.ID},
+		})
+		return err
+	}
+	_, err = client.DeleteLoadBalancer(&elb.DeleteLoadBalancerInput{
+		LoadBalancerName: &r.ID,
+	})
+	return err
+}
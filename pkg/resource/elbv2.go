@@ -0,0 +1,245 @@
+package resource
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/jharrington22/aws-resource/pkg/aws"
+)
+
+// deletionProtection is the attribute of v2 load balancers refusing their
+// deletion when enabled.
+const deletionProtection = "deletion_protection.enabled"
+
+func init() {
+	Register("elbv2", func(clients aws.ClientFunc) Provider {
+		return &loadBalancersV2{clients: clients}
+	})
+}
+
+// loadBalancersV2 provides application, network and gateway load balancers.
+type loadBalancersV2 struct {
+	clients aws.ClientFunc
+}
+
+func (p *loadBalancersV2) Type() string     { return "elbv2" }
+func (p *loadBalancersV2) Describe() string { return "running v2 load balancers" }
+func (p *loadBalancersV2) Global() bool     { return false }
+
+func (p *loadBalancersV2) List(ctx context.Context, region string) ([]*Resource, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	client, err := p.clients(region)
+	if err != nil {
+		return nil, err
+	}
+
+	var lbs []*elbv2.LoadBalancer
+	input := &elbv2.DescribeLoadBalancersInput{}
+	for {
+		output, err := client.DescribeV2LoadBalancers(input)
+		if err != nil {
+			return nil, err
+		}
+		lbs = append(lbs, output.LoadBalancers...)
+		if output.NextMarker == nil || ctx.Err() != nil {
+			break
+		}
+		input.Marker = output.NextMarker
+	}
+	if len(lbs) == 0 {
+		return nil, ctx.Err()
+	}
+
+	tgs, err := targetGroups(client, &elbv2.DescribeTargetGroupsInput{})
+	if err != nil {
+		return nil, err
+	}
+	resources, err := p.resources(client, region, lbs, tgs, false)
+	if err != nil {
+		return nil, err
+	}
+	return resources, ctx.Err()
+}
+
+// Get returns the load balancer whose ARN is id, including whether deletion
+// protection is enabled.
+func (p *loadBalancersV2) Get(ctx context.Context, region, id string) (*Resource, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	client, err := p.clients(region)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := client.DescribeV2LoadBalancers(&elbv2.DescribeLoadBalancersInput{
+		LoadBalancerArns: []*string{&id},
+	})
+	if err != nil {
+		if isNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if len(output.LoadBalancers) == 0 {
+		return nil, nil
+	}
+	tgs, err := targetGroups(client, &elbv2.DescribeTargetGroupsInput{LoadBalancerArn: &id})
+	if err != nil {
+		return nil, err
+	}
+	resources, err := p.resources(client, region, output.LoadBalancers, tgs, true)
+	if err != nil {
+		return nil, err
+	}
+	return resources[0], nil
+}
+
+// resources returns the resources of the load balancers, along with the
+// target groups they forward to among tgs. Whether deletion protection is
+// enabled takes a request per load balancer, so it's only looked up with
+// attributes, which listing doesn't do.
+func (p *loadBalancersV2) resources(client aws.Client, region string, lbs []*elbv2.LoadBalancer, tgs []*elbv2.TargetGroup, attributes bool) ([]*Resource, error) {
+	var arns []*string
+	for _, lb := range lbs {
+		arns = append(arns, lb.LoadBalancerArn)
+	}
+	tags, err := elbv2Tags(client, arns)
+	if err != nil {
+		return nil, err
+	}
+
+	used := map[string][]string{}
+	for _, tg := range tgs {
+		for _, arn := range tg.LoadBalancerArns {
+			used[value(arn)] = append(used[value(arn)], value(tg.TargetGroupArn))
+		}
+	}
+
+	var resources []*Resource
+	for _, lb := range lbs {
+		var state string
+		if lb.State != nil {
+			state = value(lb.State.Code)
+		}
+		r := &Resource{
+			Type:      p.Type(),
+			ID:        value(lb.LoadBalancerArn),
+			Region:    region,
+			Name:      value(lb.LoadBalancerName),
+			State:     state,
+			CreatedAt: timeValue(lb.CreatedTime),
+			Tags:      tags[value(lb.LoadBalancerArn)],
+			Properties: map[string]string{
+				"dns-name":      value(lb.DNSName),
+				"scheme":        value(lb.Scheme),
+				"type":          value(lb.Type),
+				"target-groups": strings.Join(used[value(lb.LoadBalancerArn)], ","),
+			},
+			Raw: lb,
+		}
+		if attributes {
+			protected, err := p.deletionProtected(client, r.ID)
+			if err != nil {
+				return nil, err
+			}
+			r.Properties["deletion-protection"] = strconv.FormatBool(protected)
+		}
+		resources = append(resources, r)
+	}
+	return resources, nil
+}
+
+// deletionProtected reports whether deletion protection is enabled on the
+// load balancer.
+func (p *loadBalancersV2) deletionProtected(client aws.Client, arn string) (bool, error) {
+	output, err := client.DescribeV2LoadBalancerAttributes(&elbv2.DescribeLoadBalancerAttributesInput{
+		LoadBalancerArn: &arn,
+	})
+	if err != nil {
+		return false, err
+	}
+	for _, a := range output.Attributes {
+		if value(a.Key) == deletionProtection {
+			return value(a.Value) == "true", nil
+		}
+	}
+	return false, nil
+}
+
+// WaitDeleted waits for the load balancer to be deleted, as its target
+// groups stay in use until its listeners are removed.
+func (p *loadBalancersV2) WaitDeleted(ctx context.Context, r *Resource) error {
+	client, err := p.clients(r.Region)
+	if err != nil {
+		return err
+	}
+	return client.WaitUntilV2LoadBalancersDeletedWithContext(ctx, &elbv2.DescribeLoadBalancersInput{
+		LoadBalancerArns: []*string{&r.ID},
+	})
+}
+
+// elbv2Tags returns the tags of load balancers or target groups keyed by ARN.
+// DescribeTags accepts at most 20 resources per request.
+func elbv2Tags(client aws.Client, arns []*string) (map[string]map[string]string, error) {
+	result := map[string]map[string]string{}
+	for start := 0; start < len(arns); start += maxTagRequest {
+		end := start + maxTagRequest
+		if end > len(arns) {
+			end = len(arns)
+		}
+		input := &elbv2.DescribeTagsInput{}
+		for _, arn := range arns[start:end] {
+			result[value(arn)] = map[string]string{}
+			input.ResourceArns = append(input.ResourceArns, arn)
+		}
+		output, err := client.DescribeV2LoadBalancerTags(input)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range output.TagDescriptions {
+			tags := map[string]string{}
+			for _, t := range d.Tags {
+				if t.Key != nil && t.Value != nil {
+					tags[*t.Key] = *t.Value
+				}
+			}
+			result[value(d.ResourceArn)] = tags
+		}
+	}
+	return result, nil
+}
+
+// Delete removes the load balancer along with its listeners, leaving its
+// target groups. Load balancers with deletion protection enabled fail with
+// an OperationNotPermitted error which is returned unchanged. As the API has
+// no dry run, a dry run only checks deletion protection is disabled.
+func (p *loadBalancersV2) Delete(ctx context.Context, r *Resource, dryRun bool) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	client, err := p.clients(r.Region)
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		protected, err := p.deletionProtected(client, r.ID)
+		if err != nil {
+			return err
+		}
+		if protected {
+			return fmt.Errorf("deletion protection is enabled on %s", r.Name)
+		}
+		return nil
+	}
+	_, err = client.DeleteV2LoadBalancer(&elbv2.DeleteLoadBalancerInput{
+		LoadBalancerArn: &r.ID,
+	})
+	return err
+}
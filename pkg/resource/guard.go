@@ -0,0 +1,211 @@
+package resource
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/jharrington22/aws-resource/pkg/aws"
+)
+
+// Guard decides which resources must never be deleted.
+type Guard interface {
+	// Protected returns why the resource is protected, if it is.
+	Protected(r *Resource) (reason string, protected bool)
+}
+
+// ProtectedError is returned when deleting a resource the guard protects.
+type ProtectedError struct {
+	Resource *Resource
+	Reason   string
+}
+
+func (e *ProtectedError) Error() string {
+	return fmt.Sprintf("%s %s in %s is protected by %s", e.Resource.Type, e.Resource.ID, e.Resource.Region, e.Reason)
+}
+
+// Getter can be implemented by providers able to look a single resource up,
+// so that the guard checks its current tags rather than the ones it had
+// when it was listed or planned.
+type Getter interface {
+	// Get returns the resource, or nil when it doesn't exist.
+	Get(ctx context.Context, region, id string) (*Resource, error)
+}
+
+// Deletion is an attempt to delete a resource.
+type Deletion struct {
+	// Resource is the resource as last seen before the attempt.
+	Resource *Resource
+
+	// Caller is the ARN of the identity making the attempt, in Account.
+	Caller  string
+	Account string
+
+	DryRun bool
+
+	// Err is the error the attempt failed with, a *ProtectedError when the
+	// guard refused to delete the resource.
+	Err error
+}
+
+// Recorder records every attempt to delete a resource.
+type Recorder interface {
+	// Check returns why attempts can't be recorded, if they can't, so that
+	// nothing is deleted without being recorded.
+	Check() error
+
+	Record(d Deletion) error
+}
+
+// UnrecordedError is returned when a resource was deleted but the deletion
+// couldn't be recorded. The deletion itself succeeded.
+type UnrecordedError struct {
+	Resource *Resource
+	Err      error
+}
+
+func (e *UnrecordedError) Error() string {
+	return fmt.Sprintf("%s %s in %s was deleted but the deletion wasn't recorded: %s", e.Resource.Type, e.Resource.ID, e.Resource.Region, e.Err)
+}
+
+// Get looks a single resource up with a provider implementing Getter, and
+// returns nil when it doesn't exist.
+func Get(ctx context.Context, p Provider, region, id string) (*Resource, error) {
+	if g, ok := p.(*guarded); ok {
+		p = g.Provider
+	}
+	getter, ok := p.(Getter)
+	if !ok {
+		return nil, ErrNotSupported
+	}
+	return getter.Get(ctx, region, id)
+}
+
+// Waiter can be implemented by providers whose deletions complete after
+// Delete returns, so that the resources a deleted resource used are only
+// deleted once it's gone.
+type Waiter interface {
+	// WaitDeleted waits for the deletion of the resource to complete.
+	WaitDeleted(ctx context.Context, r *Resource) error
+}
+
+// WaitDeleted waits for the deletion of the resource to complete with a
+// provider implementing Waiter. Deletions of other providers are complete
+// once Delete returns.
+func WaitDeleted(ctx context.Context, p Provider, r *Resource) error {
+	if g, ok := p.(*guarded); ok {
+		p = g.Provider
+	}
+	waiter, ok := p.(Waiter)
+	if !ok {
+		return nil
+	}
+	return waiter.WaitDeleted(ctx, r)
+}
+
+var (
+	guardLock sync.RWMutex
+	guard     Guard
+	recorder  Recorder
+)
+
+// SetGuard sets the guard checked by every provider created by New before
+// deleting a resource.
+func SetGuard(g Guard) {
+	guardLock.Lock()
+	defer guardLock.Unlock()
+	guard = g
+}
+
+// SetRecorder sets the recorder every provider created by New passes its
+// attempts to delete a resource to.
+func SetRecorder(r Recorder) {
+	guardLock.Lock()
+	defer guardLock.Unlock()
+	recorder = r
+}
+
+func current() (Guard, Recorder) {
+	guardLock.RLock()
+	defer guardLock.RUnlock()
+	return guard, recorder
+}
+
+// guarded refuses to delete the resources protected by the guard, and
+// records every attempt to delete a resource.
+type guarded struct {
+	Provider
+
+	clients  aws.ClientFunc
+	identity struct {
+		sync.Once
+		caller, account string
+	}
+}
+
+func (p *guarded) Delete(ctx context.Context, r *Resource, dryRun bool) error {
+	g, rec := current()
+	if rec == nil {
+		_, err := p.delete(ctx, g, r, dryRun)
+		return err
+	}
+
+	// Resources are only deleted when the attempt can be recorded
+	if err := rec.Check(); err != nil {
+		return fmt.Errorf("unable to record the deletion of %s, not deleting it: %s", r.ID, err)
+	}
+
+	// The caller is looked up before the attempt, which may fail because
+	// the credentials expired
+	p.identity.Do(func() {
+		client, err := p.clients(r.Region)
+		if err != nil {
+			return
+		}
+		output, err := client.GetCallerIdentity(&sts.GetCallerIdentityInput{})
+		if err != nil {
+			return
+		}
+		p.identity.caller, p.identity.account = value(output.Arn), value(output.Account)
+	})
+
+	last, err := p.delete(ctx, g, r, dryRun)
+	recErr := rec.Record(Deletion{
+		Resource: last,
+		Caller:   p.identity.caller,
+		Account:  p.identity.account,
+		DryRun:   dryRun,
+		Err:      err,
+	})
+	if recErr != nil && err == nil {
+		return &UnrecordedError{Resource: last, Err: recErr}
+	}
+	return err
+}
+
+// delete deletes the resource unless the guard protects it, and returns the
+// resource as last seen.
+func (p *guarded) delete(ctx context.Context, g Guard, r *Resource, dryRun bool) (*Resource, error) {
+	if g == nil {
+		return r, p.Provider.Delete(ctx, r, dryRun)
+	}
+
+	if reason, ok := g.Protected(r); ok {
+		return r, &ProtectedError{Resource: r, Reason: reason}
+	}
+	last := r
+	if getter, ok := p.Provider.(Getter); ok {
+		current, err := getter.Get(ctx, r.Region, r.ID)
+		if err != nil {
+			return r, fmt.Errorf("unable to check whether %s is protected: %s", r.ID, err)
+		}
+		if current != nil {
+			last = current
+			if reason, ok := g.Protected(current); ok {
+				return last, &ProtectedError{Resource: current, Reason: reason}
+			}
+		}
+	}
+	return last, p.Provider.Delete(ctx, r, dryRun)
+}
@@ -0,0 +1,80 @@
+package resource_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/jharrington22/aws-resource/pkg/aws/fake"
+	"github.com/jharrington22/aws-resource/pkg/resource"
+)
+
+// recorder fails its checks with check and its records with record.
+type recorder struct {
+	check, record error
+	recorded      int
+}
+
+func (r *recorder) Check() error { return r.check }
+
+func (r *recorder) Record(d resource.Deletion) error {
+	r.recorded++
+	return r.record
+}
+
+func TestRecorderFailure(t *testing.T) {
+	defer resource.SetRecorder(nil)
+	failure := errors.New("disk full")
+
+	tests := []struct {
+		name       string
+		recorder   *recorder
+		terminated bool
+		unrecorded bool
+	}{
+		{
+			name:     "check fails",
+			recorder: &recorder{check: failure},
+		},
+		{
+			name:       "record fails",
+			recorder:   &recorder{record: failure},
+			terminated: true,
+			unrecorded: true,
+		},
+		{
+			name:       "recorded",
+			recorder:   &recorder{},
+			terminated: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend := fake.New("us-east-1")
+			instance := backend.AddInstance("us-east-1", &ec2.Instance{})
+			p, err := resource.New("ec2", backend.Clients())
+			if err != nil {
+				t.Fatal(err)
+			}
+			resource.SetRecorder(tt.recorder)
+
+			err = p.Delete(context.Background(), &resource.Resource{Type: "ec2", ID: *instance.InstanceId, Region: "us-east-1"}, false)
+			var unrecorded *resource.UnrecordedError
+			switch {
+			case tt.unrecorded && !errors.As(err, &unrecorded):
+				t.Errorf("expected the deletion to be reported as unrecorded, got %v", err)
+			case !tt.unrecorded && tt.terminated && err != nil:
+				t.Errorf("unexpected error: %s", err)
+			case !tt.terminated && err == nil:
+				t.Errorf("expected the deletion to be refused")
+			}
+			if terminated := *instance.State.Name == ec2.InstanceStateNameTerminated; terminated != tt.terminated {
+				t.Errorf("expected terminated to be %t, instance is %s", tt.terminated, *instance.State.Name)
+			}
+			if !tt.terminated && tt.recorder.recorded != 0 {
+				t.Errorf("expected nothing to be recorded when the check fails")
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package resource
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/jharrington22/aws-resource/pkg/aws"
+)
+
+func init() {
+	Register("images", func(clients aws.ClientFunc) Provider {
+		return &images{clients: clients}
+	})
+}
+
+// images provides the AMIs owned by the account.
+type images struct {
+	clients aws.ClientFunc
+}
+
+func (p *images) Type() string     { return "images" }
+func (p *images) Describe() string { return "images" }
+func (p *images) Global() bool     { return false }
+
+func (p *images) List(ctx context.Context, region string) ([]*Resource, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	client, err := p.clients(region)
+	if err != nil {
+		return nil, err
+	}
+
+	owner := "self"
+	output, err := client.DescribeImages(&ec2.DescribeImagesInput{
+		Owners: []*string{&owner},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []*Resource
+	for _, i := range output.Images {
+		resources = append(resources, p.resource(region, i))
+	}
+	return resources, nil
+}
+
+// Get returns the image.
+func (p *images) Get(ctx context.Context, region, id string) (*Resource, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	client, err := p.clients(region)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := client.DescribeImages(&ec2.DescribeImagesInput{
+		ImageIds: []*string{&id},
+	})
+	if err != nil {
+		if isNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	for _, i := range output.Images {
+		return p.resource(region, i), nil
+	}
+	return nil, nil
+}
+
+func (p *images) resource(region string, i *ec2.Image) *Resource {
+	// CreationDate is an ISO 8601 string rather than a timestamp
+	created, _ := time.Parse(time.RFC3339, value(i.CreationDate))
+	return &Resource{
+		Type:      p.Type(),
+		ID:        value(i.ImageId),
+		Region:    region,
+		Name:      value(i.Name),
+		State:     value(i.State),
+		CreatedAt: created,
+		Tags:      ec2Tags(i.Tags),
+		Properties: map[string]string{
+			"snapshots": strings.Join(ImageSnapshots(i), ","),
+		},
+		Raw: i,
+	}
+}
+
+func (p *images) Delete(ctx context.Context, r *Resource, dryRun bool) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	client, err := p.clients(r.Region)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.DeregisterImage(&ec2.DeregisterImageInput{
+		DryRun:  &dryRun,
+		ImageId: &r.ID,
+	})
+	if err != nil && !(dryRun && isDryRun(err)) {
+		return err
+	}
+	return nil
+}
+
+// ImageSnapshots returns the IDs of the EBS snapshots backing the image.
+func ImageSnapshots(image *ec2.Image) []string {
+	var ids []string
+	for _, bdm := range image.BlockDeviceMappings {
+		if bdm.Ebs != nil && bdm.Ebs.SnapshotId != nil {
+			ids = append(ids, *bdm.Ebs.SnapshotId)
+		}
+	}
+	return ids
+}
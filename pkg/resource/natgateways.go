@@ -0,0 +1,129 @@
+package resource
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/jharrington22/aws-resource/pkg/aws"
+)
+
+func init() {
+	Register("natgateways", func(clients aws.ClientFunc) Provider {
+		return &natGateways{clients: clients}
+	})
+}
+
+// natGateways provides the NAT gateways that aren't deleted.
+type natGateways struct {
+	clients aws.ClientFunc
+}
+
+func (p *natGateways) Type() string     { return "natgateways" }
+func (p *natGateways) Describe() string { return "NAT gateways" }
+func (p *natGateways) Global() bool     { return false }
+
+func (p *natGateways) List(ctx context.Context, region string) ([]*Resource, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	client, err := p.clients(region)
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []*Resource
+	input := &ec2.DescribeNatGatewaysInput{}
+	for {
+		output, err := client.DescribeNatGateways(input)
+		if err != nil {
+			return nil, err
+		}
+		for _, ng := range output.NatGateways {
+			if value(ng.State) != ec2.NatGatewayStateDeleted {
+				resources = append(resources, p.resource(region, ng))
+			}
+		}
+		if output.NextToken == nil || ctx.Err() != nil {
+			break
+		}
+		input.NextToken = output.NextToken
+	}
+	return resources, ctx.Err()
+}
+
+// Get returns the NAT gateway whatever its state.
+func (p *natGateways) Get(ctx context.Context, region, id string) (*Resource, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	client, err := p.clients(region)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := client.DescribeNatGateways(&ec2.DescribeNatGatewaysInput{
+		NatGatewayIds: []*string{&id},
+	})
+	if err != nil {
+		if isNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	for _, ng := range output.NatGateways {
+		return p.resource(region, ng), nil
+	}
+	return nil, nil
+}
+
+func (p *natGateways) resource(region string, ng *ec2.NatGateway) *Resource {
+	tags := ec2Tags(ng.Tags)
+	var ips, allocations []string
+	for _, a := range ng.NatGatewayAddresses {
+		if a.PublicIp != nil {
+			ips = append(ips, value(a.PublicIp))
+		}
+		if a.AllocationId != nil {
+			allocations = append(allocations, value(a.AllocationId))
+		}
+	}
+	return &Resource{
+		Type:      p.Type(),
+		ID:        value(ng.NatGatewayId),
+		Region:    region,
+		Name:      tags["Name"],
+		State:     value(ng.State),
+		CreatedAt: timeValue(ng.CreateTime),
+		Tags:      tags,
+		Properties: map[string]string{
+			"vpc-id":            value(ng.VpcId),
+			"subnet-id":         value(ng.SubnetId),
+			"connectivity-type": value(ng.ConnectivityType),
+			"public-ips":        strings.Join(ips, ","),
+			"allocation-ids":    strings.Join(allocations, ","),
+		},
+		Raw: ng,
+	}
+}
+
+// Delete deletes the NAT gateway, which takes a few minutes during which its
+// Elastic IPs stay associated.
+func (p *natGateways) Delete(ctx context.Context, r *Resource, dryRun bool) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	client, err := p.clients(r.Region)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.DeleteNatGateway(&ec2.DeleteNatGatewayInput{
+		DryRun:       &dryRun,
+		NatGatewayId: &r.ID,
+	})
+	if err != nil && !(dryRun && isDryRun(err)) {
+		return err
+	}
+	return nil
+}
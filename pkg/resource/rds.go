@@ -0,0 +1,292 @@
+package resource
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/jharrington22/aws-resource/pkg/aws"
+)
+
+// Kinds of RDS resources.
+const (
+	DBInstance = "instance"
+	DBCluster  = "cluster"
+)
+
+func init() {
+	Register("rds", func(clients aws.ClientFunc) Provider {
+		return &databases{clients: clients}
+	})
+}
+
+// DatabaseDeletion holds the options used to delete DB instances and
+// clusters.
+type DatabaseDeletion struct {
+	// SkipFinalSnapshot deletes databases without taking a final snapshot.
+	SkipFinalSnapshot bool
+
+	// DisableDeletionProtection disables deletion protection before
+	// deleting databases, rather than failing to delete them.
+	DisableDeletionProtection bool
+}
+
+type databaseDeletionKey struct{}
+
+// WithDatabaseDeletion returns a context in which DB instances and clusters
+// are deleted with the options. Otherwise a final snapshot is taken and
+// databases with deletion protection fail to delete.
+func WithDatabaseDeletion(ctx context.Context, opts DatabaseDeletion) context.Context {
+	return context.WithValue(ctx, databaseDeletionKey{}, opts)
+}
+
+// databases provides RDS DB instances and clusters, including Aurora
+// clusters. Both are identified by their ARN as instances and clusters can
+// share an identifier, which is used as their name.
+type databases struct {
+	clients aws.ClientFunc
+}
+
+func (p *databases) Type() string     { return "rds" }
+func (p *databases) Describe() string { return "RDS instances and clusters" }
+func (p *databases) Global() bool     { return false }
+
+func (p *databases) List(ctx context.Context, region string) ([]*Resource, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	client, err := p.clients(region)
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []*Resource
+	clusters := &rds.DescribeDBClustersInput{}
+	for {
+		output, err := client.DescribeDBClusters(clusters)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range output.DBClusters {
+			resources = append(resources, p.cluster(region, c))
+		}
+		if output.Marker == nil || ctx.Err() != nil {
+			break
+		}
+		clusters.Marker = output.Marker
+	}
+
+	instances := &rds.DescribeDBInstancesInput{}
+	for {
+		output, err := client.DescribeDBInstances(instances)
+		if err != nil {
+			return nil, err
+		}
+		for _, i := range output.DBInstances {
+			resources = append(resources, p.instance(region, i))
+		}
+		if output.Marker == nil || ctx.Err() != nil {
+			break
+		}
+		instances.Marker = output.Marker
+	}
+	return resources, ctx.Err()
+}
+
+// Get returns the DB instance or cluster whose ARN is id.
+func (p *databases) Get(ctx context.Context, region, id string) (*Resource, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	client, err := p.clients(region)
+	if err != nil {
+		return nil, err
+	}
+
+	if dbKind(id) == DBCluster {
+		output, err := client.DescribeDBClusters(&rds.DescribeDBClustersInput{
+			DBClusterIdentifier: &id,
+		})
+		if err != nil {
+			if isNotFound(err) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		for _, c := range output.DBClusters {
+			return p.cluster(region, c), nil
+		}
+		return nil, nil
+	}
+
+	output, err := client.DescribeDBInstances(&rds.DescribeDBInstancesInput{
+		DBInstanceIdentifier: &id,
+	})
+	if err != nil {
+		if isNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	for _, i := range output.DBInstances {
+		return p.instance(region, i), nil
+	}
+	return nil, nil
+}
+
+func (p *databases) instance(region string, i *rds.DBInstance) *Resource {
+	var cluster string
+	if i.DBClusterIdentifier != nil {
+		cluster = dbARN(value(i.DBInstanceArn), DBCluster, *i.DBClusterIdentifier)
+	}
+	return &Resource{
+		Type:      p.Type(),
+		ID:        value(i.DBInstanceArn),
+		Region:    region,
+		Name:      value(i.DBInstanceIdentifier),
+		State:     value(i.DBInstanceStatus),
+		CreatedAt: timeValue(i.InstanceCreateTime),
+		Tags:      rdsTags(i.TagList),
+		Properties: map[string]string{
+			"kind":                DBInstance,
+			"engine":              value(i.Engine),
+			"engine-version":      value(i.EngineVersion),
+			"class":               value(i.DBInstanceClass),
+			"storage":             strconv.FormatInt(int64Value(i.AllocatedStorage), 10),
+			"storage-type":        value(i.StorageType),
+			"multi-az":            strconv.FormatBool(boolValue(i.MultiAZ)),
+			"cluster":             cluster,
+			"deletion-protection": strconv.FormatBool(boolValue(i.DeletionProtection)),
+		},
+		Raw: i,
+	}
+}
+
+func (p *databases) cluster(region string, c *rds.DBCluster) *Resource {
+	var members []string
+	for _, m := range c.DBClusterMembers {
+		members = append(members, dbARN(value(c.DBClusterArn), DBInstance, value(m.DBInstanceIdentifier)))
+	}
+	return &Resource{
+		Type:      p.Type(),
+		ID:        value(c.DBClusterArn),
+		Region:    region,
+		Name:      value(c.DBClusterIdentifier),
+		State:     value(c.Status),
+		CreatedAt: timeValue(c.ClusterCreateTime),
+		Tags:      rdsTags(c.TagList),
+		Properties: map[string]string{
+			"kind":                DBCluster,
+			"engine":              value(c.Engine),
+			"engine-version":      value(c.EngineVersion),
+			"class":               value(c.DBClusterInstanceClass),
+			"storage":             strconv.FormatInt(int64Value(c.AllocatedStorage), 10),
+			"storage-type":        value(c.StorageType),
+			"multi-az":            strconv.FormatBool(boolValue(c.MultiAZ)),
+			"members":             strings.Join(members, ","),
+			"deletion-protection": strconv.FormatBool(boolValue(c.DeletionProtection)),
+		},
+		Raw: c,
+	}
+}
+
+// Delete removes the DB instance or cluster, taking a final snapshot named
+// after it unless the context says otherwise. Instances of a cluster are
+// deleted without a final snapshot, which is taken when deleting the
+// cluster, and clusters fail to delete until their instances are deleting.
+// As the API has no dry run, a dry run only checks deletion protection.
+func (p *databases) Delete(ctx context.Context, r *Resource, dryRun bool) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	client, err := p.clients(r.Region)
+	if err != nil {
+		return err
+	}
+
+	opts, _ := ctx.Value(databaseDeletionKey{}).(DatabaseDeletion)
+	protected := r.Properties["deletion-protection"] == "true"
+	if dryRun {
+		if protected && !opts.DisableDeletionProtection {
+			return fmt.Errorf("deletion protection is enabled on %s", r.Name)
+		}
+		return nil
+	}
+
+	var snapshot *string
+	if !opts.SkipFinalSnapshot {
+		snapshot = awssdk.String(fmt.Sprintf("%s-final-%s", r.Name, time.Now().UTC().Format("20060102-150405")))
+	}
+
+	if dbKind(r.ID) == DBCluster {
+		if protected && opts.DisableDeletionProtection {
+			_, err = client.ModifyDBCluster(&rds.ModifyDBClusterInput{
+				DBClusterIdentifier: &r.Name,
+				DeletionProtection:  awssdk.Bool(false),
+				ApplyImmediately:    awssdk.Bool(true),
+			})
+			if err != nil {
+				return err
+			}
+		}
+		_, err = client.DeleteDBCluster(&rds.DeleteDBClusterInput{
+			DBClusterIdentifier:       &r.Name,
+			SkipFinalSnapshot:         awssdk.Bool(snapshot == nil),
+			FinalDBSnapshotIdentifier: snapshot,
+		})
+		return err
+	}
+
+	if protected && opts.DisableDeletionProtection {
+		_, err = client.ModifyDBInstance(&rds.ModifyDBInstanceInput{
+			DBInstanceIdentifier: &r.Name,
+			DeletionProtection:   awssdk.Bool(false),
+			ApplyImmediately:     awssdk.Bool(true),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	input := &rds.DeleteDBInstanceInput{DBInstanceIdentifier: &r.Name}
+	if r.Properties["cluster"] == "" {
+		input.SkipFinalSnapshot = awssdk.Bool(snapshot == nil)
+		input.FinalDBSnapshotIdentifier = snapshot
+	}
+	_, err = client.DeleteDBInstance(input)
+	return err
+}
+
+// dbKind returns whether the ARN is the one of a DB instance or cluster.
+func dbKind(arn string) string {
+	if strings.Contains(arn, ":cluster:") {
+		return DBCluster
+	}
+	return DBInstance
+}
+
+// dbARN returns the ARN of the DB instance or cluster with the identifier,
+// in the account and region of the other ARN.
+func dbARN(arn, kind, identifier string) string {
+	parts := strings.SplitN(arn, ":", 6)
+	if len(parts) < 6 {
+		return identifier
+	}
+	if kind == DBInstance {
+		kind = "db"
+	}
+	return strings.Join(append(parts[:5], kind, identifier), ":")
+}
+
+func rdsTags(tags []*rds.Tag) map[string]string {
+	result := map[string]string{}
+	for _, t := range tags {
+		if t.Key != nil && t.Value != nil {
+			result[*t.Key] = *t.Value
+		}
+	}
+	return result
+}
@@ -0,0 +1,33 @@
+package resource
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/jharrington22/aws-resource/pkg/aws"
+)
+
+// GlobalRegion is the region reported for resources of global providers.
+const GlobalRegion = "global"
+
+// Regions returns the names of the regions enabled in the account, querying
+// them with a client built for region.
+func Regions(clients aws.ClientFunc, region string) ([]string, error) {
+	client, err := clients(region)
+	if err != nil {
+		return nil, fmt.Errorf("unable to build AWS client: %s", err)
+	}
+
+	output, err := client.DescribeRegions(&ec2.DescribeRegionsInput{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe regions: %s", err)
+	}
+
+	var regions []string
+	for _, r := range output.Regions {
+		regions = append(regions, *r.RegionName)
+	}
+	sort.Strings(regions)
+	return regions, nil
+}
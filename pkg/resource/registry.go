@@ -0,0 +1,57 @@
+package resource
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/jharrington22/aws-resource/pkg/aws"
+)
+
+// Constructor creates a provider that uses clients to reach AWS.
+type Constructor func(clients aws.ClientFunc) Provider
+
+var (
+	registryLock sync.RWMutex
+	registry     = map[string]Constructor{}
+)
+
+// Register makes a provider available under the given type name. It panics if
+// the name is already registered.
+func Register(typ string, constructor Constructor) {
+	registryLock.Lock()
+	defer registryLock.Unlock()
+	if _, ok := registry[typ]; ok {
+		panic(fmt.Sprintf("resource type %q registered twice", typ))
+	}
+	registry[typ] = constructor
+}
+
+// Types returns the registered type names in alphabetical order.
+func Types() []string {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+	types := make([]string, 0, len(registry))
+	for typ := range registry {
+		types = append(types, typ)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// New creates the provider registered under typ. Providers supporting
+// deletion refuse to delete the resources protected by the guard set with
+// SetGuard, and pass every attempt to the recorder set with SetRecorder.
+func New(typ string, clients aws.ClientFunc) (Provider, error) {
+	registryLock.RLock()
+	constructor, ok := registry[typ]
+	registryLock.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown resource type %q", typ)
+	}
+	p := constructor(clients)
+	if !Deletable(p) {
+		return p, nil
+	}
+	return &guarded{Provider: p, clients: clients}, nil
+}
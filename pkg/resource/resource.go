@@ -0,0 +1,132 @@
+// Package resource contains the providers used to list and delete the AWS
+// resource types supported by aws-resource.
+package resource
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+const (
+	// DryRunOperation is the error code returned by EC2 when a request made
+	// with the DryRun flag set would have succeeded.
+	DryRunOperation = "DryRunOperation"
+)
+
+// ErrNotSupported is returned by providers for operations they don't implement.
+var ErrNotSupported = errors.New("operation not supported")
+
+// Resource describes a single AWS resource discovered by a provider.
+type Resource struct {
+	Type       string
+	ID         string
+	Region     string
+	Name       string
+	State      string
+	CreatedAt  time.Time
+	Tags       map[string]string
+	Properties map[string]string
+
+	// Account is the ID of the account the resource was found in, only set
+	// when a command runs in several accounts.
+	Account string
+
+	// Raw holds the AWS SDK object the resource was built from.
+	Raw interface{}
+}
+
+// Provider lists and deletes resources of a single type.
+type Provider interface {
+	// Type returns the name used to select the provider on the command line,
+	// e.g. "ec2" or "snapshots".
+	Type() string
+
+	// Describe returns a short plural description of the resources, used in
+	// messages such as "Found 3 running instances in us-east-1".
+	Describe() string
+
+	// Global reports whether the resources exist outside of any region, in
+	// which case List is only called once.
+	Global() bool
+
+	// List returns the resources found in the given region.
+	List(ctx context.Context, region string) ([]*Resource, error)
+
+	// Delete removes the resource. When dryRun is set the request is only
+	// validated and nil is returned if it would have succeeded.
+	Delete(ctx context.Context, r *Resource, dryRun bool) error
+}
+
+// readOnly can be embedded by providers that don't support deletion.
+type readOnly struct{}
+
+func (readOnly) Delete(ctx context.Context, r *Resource, dryRun bool) error {
+	return ErrNotSupported
+}
+
+func (readOnly) notDeletable() {}
+
+// Deletable reports whether the provider supports deleting resources.
+func Deletable(p Provider) bool {
+	_, ok := p.(interface{ notDeletable() })
+	return !ok
+}
+
+// isDryRun reports whether err is the error returned by a successful dry run.
+func isDryRun(err error) bool {
+	var aerr awserr.Error
+	return errors.As(err, &aerr) && aerr.Code() == DryRunOperation
+}
+
+// isNotFound reports whether err is returned for a resource that doesn't
+// exist, such as InvalidInstanceID.NotFound, LoadBalancerNotFound or
+// DBClusterNotFoundFault. Errors for malformed IDs, such as
+// InvalidInstanceID.Malformed, are returned to the caller.
+func isNotFound(err error) bool {
+	var aerr awserr.Error
+	if !errors.As(err, &aerr) {
+		return false
+	}
+	code := strings.TrimSuffix(aerr.Code(), "Fault")
+	return strings.HasSuffix(code, "NotFound")
+}
+
+func ec2Tags(tags []*ec2.Tag) map[string]string {
+	result := map[string]string{}
+	for _, t := range tags {
+		if t.Key != nil && t.Value != nil {
+			result[*t.Key] = *t.Value
+		}
+	}
+	return result
+}
+
+func value(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func timeValue(t *time.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return *t
+}
+
+func int64Value(i *int64) int64 {
+	if i == nil {
+		return 0
+	}
+	return *i
+}
+
+func boolValue(b *bool) bool {
+	return b != nil && *b
+}
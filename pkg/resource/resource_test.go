@@ -0,0 +1,30 @@
+package resource
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+)
+
+func TestIsNotFound(t *testing.T) {
+	tests := []struct {
+		err      error
+		notFound bool
+	}{
+		{err: awserr.New("InvalidInstanceID.NotFound", "", nil), notFound: true},
+		{err: awserr.New("LoadBalancerNotFound", "", nil), notFound: true},
+		{err: awserr.New("DBClusterNotFoundFault", "", nil), notFound: true},
+		{err: fmt.Errorf("describing: %w", awserr.New("InvalidVolume.NotFound", "", nil)), notFound: true},
+		// A malformed ID is a mistake of the caller, not a missing resource
+		{err: awserr.New("InvalidInstanceID.Malformed", "", nil)},
+		{err: awserr.New("UnauthorizedOperation", "", nil)},
+		{err: errors.New("NotFound")},
+	}
+	for _, tt := range tests {
+		if got := isNotFound(tt.err); got != tt.notFound {
+			t.Errorf("isNotFound(%v) = %t, expected %t", tt.err, got, tt.notFound)
+		}
+	}
+}
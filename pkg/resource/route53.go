@@ -0,0 +1,278 @@
+package resource
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/jharrington22/aws-resource/pkg/aws"
+)
+
+// route53Region is the region used to build clients for the global Route53 API.
+const route53Region = "us-east-1"
+
+const (
+	// maxTagZones is the maximum number of hosted zones whose tags can be
+	// listed in a single request.
+	maxTagZones = 10
+
+	// A change batch holds at most 1000 changes, and 32000 characters of
+	// record values.
+	maxChanges     = 1000
+	maxChangeChars = 32000
+)
+
+func init() {
+	Register("route53", func(clients aws.ClientFunc) Provider {
+		return &hostedZones{clients: clients}
+	})
+}
+
+type zoneChangesKey struct{}
+
+// zoneChanges are the change batches emptying a zone.
+type zoneChanges struct {
+	id      string
+	batches []*route53.ChangeResourceRecordSetsInput
+}
+
+// WithZoneChanges returns a context in which deleting the zone sends the
+// change batches returned by EmptyZoneChanges rather than listing its record
+// sets again, so that exactly the batches shown are sent.
+func WithZoneChanges(ctx context.Context, zone *Resource, batches []*route53.ChangeResourceRecordSetsInput) context.Context {
+	return context.WithValue(ctx, zoneChangesKey{}, zoneChanges{id: zone.ID, batches: batches})
+}
+
+// hostedZones provides Route53 hosted zones.
+type hostedZones struct {
+	clients aws.ClientFunc
+}
+
+func (p *hostedZones) Type() string     { return "route53" }
+func (p *hostedZones) Describe() string { return "hosted zones" }
+func (p *hostedZones) Global() bool     { return true }
+
+func (p *hostedZones) List(ctx context.Context, region string) ([]*Resource, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	client, err := p.clients(route53Region)
+	if err != nil {
+		return nil, err
+	}
+
+	var zones []*route53.HostedZone
+	input := &route53.ListHostedZonesByNameInput{}
+	for {
+		output, err := client.ListHostedZonesByName(input)
+		if err != nil {
+			return nil, err
+		}
+		zones = append(zones, output.HostedZones...)
+		if output.IsTruncated == nil || !*output.IsTruncated || ctx.Err() != nil {
+			break
+		}
+		input.DNSName = output.NextDNSName
+		input.HostedZoneId = output.NextHostedZoneId
+	}
+
+	resources, err := p.resources(client, zones)
+	if err != nil {
+		return nil, err
+	}
+	return resources, ctx.Err()
+}
+
+// Get returns the hosted zone.
+func (p *hostedZones) Get(ctx context.Context, region, id string) (*Resource, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	client, err := p.clients(route53Region)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := client.GetHostedZone(&route53.GetHostedZoneInput{Id: &id})
+	if err != nil {
+		var aerr awserr.Error
+		if errors.As(err, &aerr) && aerr.Code() == route53.ErrCodeNoSuchHostedZone {
+			return nil, nil
+		}
+		return nil, err
+	}
+	resources, err := p.resources(client, []*route53.HostedZone{output.HostedZone})
+	if err != nil {
+		return nil, err
+	}
+	return resources[0], nil
+}
+
+func (p *hostedZones) resources(client aws.Client, zones []*route53.HostedZone) ([]*Resource, error) {
+	tags, err := p.tags(client, zones)
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []*Resource
+	for _, z := range zones {
+		private := false
+		if z.Config != nil && z.Config.PrivateZone != nil {
+			private = *z.Config.PrivateZone
+		}
+		resources = append(resources, &Resource{
+			Type:   p.Type(),
+			ID:     value(z.Id),
+			Region: GlobalRegion,
+			Name:   value(z.Name),
+			Tags:   tags[zoneID(value(z.Id))],
+			Properties: map[string]string{
+				"record-count": strconv.FormatInt(int64Value(z.ResourceRecordSetCount), 10),
+				"private":      strconv.FormatBool(private),
+			},
+			Raw: z,
+		})
+	}
+	return resources, nil
+}
+
+// tags returns the tags of the zones keyed by zone ID. ListTagsForResources
+// accepts at most 10 zones per request.
+func (p *hostedZones) tags(client aws.Client, zones []*route53.HostedZone) (map[string]map[string]string, error) {
+	result := map[string]map[string]string{}
+	for start := 0; start < len(zones); start += maxTagZones {
+		end := start + maxTagZones
+		if end > len(zones) {
+			end = len(zones)
+		}
+		input := &route53.ListTagsForResourcesInput{
+			ResourceType: awssdk.String(route53.TagResourceTypeHostedzone),
+		}
+		for _, z := range zones[start:end] {
+			id := zoneID(value(z.Id))
+			result[id] = map[string]string{}
+			input.ResourceIds = append(input.ResourceIds, &id)
+		}
+		output, err := client.ListHostedZoneTags(input)
+		if err != nil {
+			return nil, err
+		}
+		for _, set := range output.ResourceTagSets {
+			tags := map[string]string{}
+			for _, t := range set.Tags {
+				if t.Key != nil && t.Value != nil {
+					tags[*t.Key] = *t.Value
+				}
+			}
+			result[zoneID(value(set.ResourceId))] = tags
+		}
+	}
+	return result, nil
+}
+
+// zoneID returns the ID of a zone without the /hostedzone/ prefix.
+func zoneID(id string) string {
+	return strings.TrimPrefix(id, "/hostedzone/")
+}
+
+// Delete deletes every record set of the zone but the SOA and NS record sets
+// of its apex, then the zone. The record sets are only listed when the
+// context holds no change batches for the zone. As the API has no dry run, a
+// dry run only checks the record sets can be listed.
+func (p *hostedZones) Delete(ctx context.Context, r *Resource, dryRun bool) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	client, err := p.clients(route53Region)
+	if err != nil {
+		return err
+	}
+
+	changes, ok := ctx.Value(zoneChangesKey{}).(zoneChanges)
+	batches := changes.batches
+	if !ok || changes.id != r.ID {
+		batches, err = EmptyZoneChanges(ctx, p.clients, r)
+		if err != nil {
+			return err
+		}
+	}
+	if dryRun {
+		return nil
+	}
+	for _, batch := range batches {
+		if _, err := client.ChangeResourceRecordSets(batch); err != nil {
+			return err
+		}
+	}
+	_, err = client.DeleteHostedZone(&route53.DeleteHostedZoneInput{Id: &r.ID})
+	return err
+}
+
+// EmptyZoneChanges returns the change batches deleting every record set of
+// the zone but the SOA and NS record sets of its apex, which are deleted
+// along with the zone.
+func EmptyZoneChanges(ctx context.Context, clients aws.ClientFunc, zone *Resource) ([]*route53.ChangeResourceRecordSetsInput, error) {
+	client, err := clients(route53Region)
+	if err != nil {
+		return nil, err
+	}
+
+	var sets []*route53.ResourceRecordSet
+	input := &route53.ListResourceRecordSetsInput{HostedZoneId: &zone.ID}
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		output, err := client.ListResourceRecordSets(input)
+		if err != nil {
+			return nil, err
+		}
+		sets = append(sets, output.ResourceRecordSets...)
+		if output.IsTruncated == nil || !*output.IsTruncated {
+			break
+		}
+		input.StartRecordName = output.NextRecordName
+		input.StartRecordType = output.NextRecordType
+		input.StartRecordIdentifier = output.NextRecordIdentifier
+	}
+
+	// The apex is named by the zone's only SOA record set
+	var apex string
+	for _, set := range sets {
+		if value(set.Type) == route53.RRTypeSoa {
+			apex = value(set.Name)
+		}
+	}
+
+	var batches []*route53.ChangeResourceRecordSetsInput
+	var batch *route53.ChangeBatch
+	chars := 0
+	for _, set := range sets {
+		typ := value(set.Type)
+		if value(set.Name) == apex && (typ == route53.RRTypeSoa || typ == route53.RRTypeNs) {
+			continue
+		}
+		size := 0
+		for _, record := range set.ResourceRecords {
+			size += len(value(record.Value))
+		}
+		if batch == nil || len(batch.Changes) == maxChanges || chars+size > maxChangeChars {
+			batch = &route53.ChangeBatch{}
+			chars = 0
+			batches = append(batches, &route53.ChangeResourceRecordSetsInput{
+				HostedZoneId: &zone.ID,
+				ChangeBatch:  batch,
+			})
+		}
+		batch.Changes = append(batch.Changes, &route53.Change{
+			Action:            awssdk.String(route53.ChangeActionDelete),
+			ResourceRecordSet: set,
+		})
+		chars += size
+	}
+	return batches, nil
+}
@@ -0,0 +1,282 @@
+package resource
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/jharrington22/aws-resource/pkg/aws"
+)
+
+// s3Region is the region used to build clients listing buckets, which are
+// then reached with clients of their own region.
+const s3Region = "us-east-1"
+
+const (
+	// DefaultObjectSample is the number of objects of each bucket counted
+	// when listing buckets, unless the context says otherwise.
+	DefaultObjectSample = 10000
+
+	// maxObjects is the maximum number of objects listed, and deleted, in a
+	// single request.
+	maxObjects = 1000
+)
+
+func init() {
+	Register("s3", func(clients aws.ClientFunc) Provider {
+		return &buckets{clients: clients}
+	})
+}
+
+type objectSampleKey struct{}
+
+// WithObjectSample returns a context in which listing buckets counts at most
+// n objects of each bucket to estimate its object count and size, and none
+// when n is 0.
+func WithObjectSample(ctx context.Context, n int) context.Context {
+	return context.WithValue(ctx, objectSampleKey{}, n)
+}
+
+func objectSample(ctx context.Context) int {
+	if n, ok := ctx.Value(objectSampleKey{}).(int); ok {
+		return n
+	}
+	return DefaultObjectSample
+}
+
+// buckets provides S3 buckets. The API is global but each bucket is reported
+// in, and reached through, its own region.
+type buckets struct {
+	clients aws.ClientFunc
+}
+
+func (p *buckets) Type() string     { return "s3" }
+func (p *buckets) Describe() string { return "S3 buckets" }
+func (p *buckets) Global() bool     { return true }
+
+func (p *buckets) List(ctx context.Context, region string) ([]*Resource, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	client, err := p.clients(s3Region)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := client.ListBuckets(&s3.ListBucketsInput{})
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []*Resource
+	for _, b := range output.Buckets {
+		if ctx.Err() != nil {
+			break
+		}
+		r, err := p.resource(client, b, objectSample(ctx))
+		if err != nil {
+			return nil, err
+		}
+		if r != nil {
+			resources = append(resources, r)
+		}
+	}
+	return resources, ctx.Err()
+}
+
+// Get returns the bucket named id, without counting its objects.
+func (p *buckets) Get(ctx context.Context, region, id string) (*Resource, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	client, err := p.clients(s3Region)
+	if err != nil {
+		return nil, err
+	}
+	return p.resource(client, &s3.Bucket{Name: &id}, 0)
+}
+
+// resource returns the resource of the bucket, or nil when it doesn't exist
+// anymore, counting at most sample of its objects.
+func (p *buckets) resource(client aws.Client, b *s3.Bucket, sample int) (*Resource, error) {
+	location, err := client.GetBucketLocation(&s3.GetBucketLocationInput{Bucket: b.Name})
+	if err != nil {
+		if isNoSuchBucket(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	region := s3.NormalizeBucketLocation(value(location.LocationConstraint))
+	regional, err := p.clients(region)
+	if err != nil {
+		return nil, err
+	}
+
+	versioning, err := regional.GetBucketVersioning(&s3.GetBucketVersioningInput{Bucket: b.Name})
+	if err != nil {
+		if isNoSuchBucket(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	status := value(versioning.Status)
+	if status == "" {
+		status = "Disabled"
+	}
+
+	tags := map[string]string{}
+	tagging, err := regional.GetBucketTagging(&s3.GetBucketTaggingInput{Bucket: b.Name})
+	var aerr awserr.Error
+	switch {
+	case err == nil:
+		for _, t := range tagging.TagSet {
+			if t.Key != nil && t.Value != nil {
+				tags[*t.Key] = *t.Value
+			}
+		}
+	case errors.As(err, &aerr) && aerr.Code() == "NoSuchTagSet":
+	case isNoSuchBucket(err):
+		return nil, nil
+	default:
+		return nil, err
+	}
+
+	r := &Resource{
+		Type:      p.Type(),
+		ID:        value(b.Name),
+		Region:    region,
+		Name:      tags["Name"],
+		CreatedAt: timeValue(b.CreationDate),
+		Tags:      tags,
+		Properties: map[string]string{
+			"versioning": status,
+		},
+		Raw: b,
+	}
+	if sample > 0 {
+		objects, size, sampled, err := countObjects(regional, value(b.Name), sample)
+		if err != nil {
+			return nil, err
+		}
+		r.Properties["objects"] = strconv.Itoa(objects)
+		r.Properties["size"] = strconv.FormatInt(size, 10)
+		r.Properties["sampled"] = strconv.FormatBool(sampled)
+	}
+	return r, nil
+}
+
+// countObjects returns the number and total size of the current objects of
+// the bucket, counting at most sample of them. sampled is set when the
+// bucket holds more objects, the count and size then being lower bounds.
+func countObjects(client aws.Client, bucket string, sample int) (objects int, size int64, sampled bool, err error) {
+	input := &s3.ListObjectsV2Input{Bucket: &bucket}
+	for {
+		max := sample - objects
+		if max > maxObjects {
+			max = maxObjects
+		}
+		input.MaxKeys = awssdk.Int64(int64(max))
+		output, err := client.ListObjectsV2(input)
+		if err != nil {
+			return 0, 0, false, err
+		}
+		for _, o := range output.Contents {
+			objects++
+			size += int64Value(o.Size)
+		}
+		if output.IsTruncated == nil || !*output.IsTruncated {
+			return objects, size, false, nil
+		}
+		if objects >= sample {
+			return objects, size, true, nil
+		}
+		input.ContinuationToken = output.NextContinuationToken
+	}
+}
+
+// Delete empties the bucket, deleting every version of its objects along
+// with their delete markers, then deletes the bucket. As the API has no dry
+// run, a dry run only checks the bucket can be reached.
+func (p *buckets) Delete(ctx context.Context, r *Resource, dryRun bool) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	client, err := p.clients(r.Region)
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		_, err = client.GetBucketVersioning(&s3.GetBucketVersioningInput{Bucket: &r.ID})
+		return err
+	}
+	if _, err := EmptyBucket(ctx, p.clients, r, false); err != nil {
+		return err
+	}
+	_, err = client.DeleteBucket(&s3.DeleteBucketInput{Bucket: &r.ID})
+	return err
+}
+
+// EmptyBucket deletes every object version and delete marker of the bucket
+// in batches, and returns how many were deleted. A dry run only counts them.
+func EmptyBucket(ctx context.Context, clients aws.ClientFunc, bucket *Resource, dryRun bool) (int, error) {
+	client, err := clients(bucket.Region)
+	if err != nil {
+		return 0, err
+	}
+
+	deleted := 0
+	input := &s3.ListObjectVersionsInput{Bucket: &bucket.ID, MaxKeys: awssdk.Int64(maxObjects)}
+	for {
+		if err := ctx.Err(); err != nil {
+			return deleted, err
+		}
+		output, err := client.ListObjectVersions(input)
+		if err != nil {
+			return deleted, err
+		}
+
+		var objects []*s3.ObjectIdentifier
+		for _, v := range output.Versions {
+			objects = append(objects, &s3.ObjectIdentifier{Key: v.Key, VersionId: v.VersionId})
+		}
+		for _, m := range output.DeleteMarkers {
+			objects = append(objects, &s3.ObjectIdentifier{Key: m.Key, VersionId: m.VersionId})
+		}
+		if len(objects) > 0 && !dryRun {
+			result, err := client.DeleteObjects(&s3.DeleteObjectsInput{
+				Bucket: &bucket.ID,
+				Delete: &s3.Delete{Objects: objects, Quiet: awssdk.Bool(true)},
+			})
+			if err != nil {
+				return deleted, err
+			}
+			if len(result.Errors) > 0 {
+				e := result.Errors[0]
+				return deleted, fmt.Errorf("unable to delete %s version %s: %s", value(e.Key), value(e.VersionId), value(e.Message))
+			}
+		}
+		deleted += len(objects)
+
+		if output.IsTruncated == nil || !*output.IsTruncated {
+			return deleted, nil
+		}
+		// Deleted versions are no longer listed, so the next batch is the
+		// start of what's left
+		if dryRun {
+			input.KeyMarker = output.NextKeyMarker
+			input.VersionIdMarker = output.NextVersionIdMarker
+		}
+	}
+}
+
+// isNoSuchBucket reports whether err is returned for a bucket that doesn't
+// exist.
+func isNoSuchBucket(err error) bool {
+	var aerr awserr.Error
+	return errors.As(err, &aerr) && aerr.Code() == s3.ErrCodeNoSuchBucket
+}
@@ -0,0 +1,97 @@
+package resource
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// DefaultConcurrency is the number of regions scanned at the same time when
+// no concurrency is given.
+const DefaultConcurrency = 8
+
+// RegionResult holds the resources a provider found in a single region.
+type RegionResult struct {
+	Region    string
+	Resources []*Resource
+}
+
+// RegionError is the error returned when listing a region failed.
+type RegionError struct {
+	Region string
+	Err    error
+}
+
+func (e *RegionError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Region, e.Err)
+}
+
+func (e *RegionError) Unwrap() error {
+	return e.Err
+}
+
+// ScanError aggregates the errors of every region that failed during a scan.
+type ScanError struct {
+	Errors []*RegionError
+}
+
+func (e *ScanError) Error() string {
+	var msgs []string
+	for _, err := range e.Errors {
+		msgs = append(msgs, err.Error())
+	}
+	return fmt.Sprintf("%d regions failed: %s", len(e.Errors), strings.Join(msgs, "; "))
+}
+
+// Scan lists the resources the provider finds in each of the regions, using
+// up to concurrency regions at the same time. Results are returned in the
+// order the regions were given and only include the regions that succeeded.
+// When any region fails a *ScanError holding every failure is returned along
+// with the results of the other regions. Global providers are only listed
+// once.
+func Scan(ctx context.Context, p Provider, regions []string, concurrency int) ([]RegionResult, error) {
+	if p.Global() {
+		regions = []string{GlobalRegion}
+	}
+	if concurrency < 1 {
+		concurrency = DefaultConcurrency
+	}
+
+	results := make([]RegionResult, len(regions))
+	errs := make([]error, len(regions))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+	for i, region := range regions {
+		wg.Add(1)
+		go func(i int, region string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			if err := ctx.Err(); err != nil {
+				errs[i] = err
+				return
+			}
+			resources, err := p.List(ctx, region)
+			results[i] = RegionResult{Region: region, Resources: resources}
+			errs[i] = err
+		}(i, region)
+	}
+	wg.Wait()
+
+	var succeeded []RegionResult
+	scanErr := &ScanError{}
+	for i, err := range errs {
+		if err != nil {
+			scanErr.Errors = append(scanErr.Errors, &RegionError{Region: regions[i], Err: err})
+			continue
+		}
+		succeeded = append(succeeded, results[i])
+	}
+	if len(scanErr.Errors) > 0 {
+		return succeeded, scanErr
+	}
+	return succeeded, nil
+}
@@ -0,0 +1,173 @@
+package resource_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jharrington22/aws-resource/pkg/resource"
+)
+
+// lister is a provider listing a single resource named after the region with
+// list, or failing with the error list returns.
+type lister struct {
+	list func(ctx context.Context, region string) error
+}
+
+func (p *lister) Type() string     { return "test" }
+func (p *lister) Describe() string { return "test resources" }
+func (p *lister) Global() bool     { return false }
+
+func (p *lister) List(ctx context.Context, region string) ([]*resource.Resource, error) {
+	if err := p.list(ctx, region); err != nil {
+		return nil, err
+	}
+	return []*resource.Resource{{ID: region, Region: region}}, nil
+}
+
+func (p *lister) Delete(ctx context.Context, r *resource.Resource, dryRun bool) error {
+	return resource.ErrNotSupported
+}
+
+// regions returns the regions of the results, in order.
+func regions(results []resource.RegionResult) []string {
+	var regions []string
+	for _, result := range results {
+		regions = append(regions, result.Region)
+	}
+	return regions
+}
+
+func TestScanOrder(t *testing.T) {
+	// Each region waits for the next one to complete, so they complete in
+	// the reverse of the order they were given in
+	given := []string{"us-east-1", "us-west-2", "eu-west-1"}
+	done := map[string]chan struct{}{}
+	for _, region := range given {
+		done[region] = make(chan struct{})
+	}
+	var completed []string
+	var mu sync.Mutex
+	p := &lister{list: func(ctx context.Context, region string) error {
+		for i, r := range given[:len(given)-1] {
+			if r == region {
+				<-done[given[i+1]]
+			}
+		}
+		mu.Lock()
+		completed = append(completed, region)
+		mu.Unlock()
+		close(done[region])
+		return nil
+	}}
+
+	results, err := resource.Scan(context.Background(), p, given, len(given))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"eu-west-1", "us-west-2", "us-east-1"}; !reflect.DeepEqual(completed, want) {
+		t.Fatalf("expected regions to complete in order %v, got %v", want, completed)
+	}
+	if got := regions(results); !reflect.DeepEqual(got, given) {
+		t.Errorf("expected results in order %v, got %v", given, got)
+	}
+	for _, result := range results {
+		if len(result.Resources) != 1 || result.Resources[0].ID != result.Region {
+			t.Errorf("unexpected resources in %s: %v", result.Region, result.Resources)
+		}
+	}
+}
+
+func TestScanConcurrency(t *testing.T) {
+	given := []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"}
+	var mu sync.Mutex
+	var active, max int
+	p := &lister{list: func(ctx context.Context, region string) error {
+		mu.Lock()
+		active++
+		if active > max {
+			max = active
+		}
+		mu.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		mu.Lock()
+		active--
+		mu.Unlock()
+		return nil
+	}}
+
+	for _, concurrency := range []int{1, 3} {
+		max = 0
+		results, err := resource.Scan(context.Background(), p, given, concurrency)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(results) != len(given) {
+			t.Errorf("expected %d results, got %d", len(given), len(results))
+		}
+		if max > concurrency {
+			t.Errorf("expected at most %d regions listed at the same time, got %d", concurrency, max)
+		}
+	}
+}
+
+func TestScanPartialResults(t *testing.T) {
+	failure := errors.New("access denied")
+	p := &lister{list: func(ctx context.Context, region string) error {
+		if region == "us-west-2" {
+			return failure
+		}
+		return nil
+	}}
+
+	results, err := resource.Scan(context.Background(), p, []string{"us-east-1", "us-west-2", "eu-west-1"}, 2)
+	if want := []string{"us-east-1", "eu-west-1"}; !reflect.DeepEqual(regions(results), want) {
+		t.Errorf("expected results for %v, got %v", want, regions(results))
+	}
+	var scanErr *resource.ScanError
+	if !errors.As(err, &scanErr) {
+		t.Fatalf("expected a scan error, got %v", err)
+	}
+	if len(scanErr.Errors) != 1 || scanErr.Errors[0].Region != "us-west-2" || !errors.Is(scanErr.Errors[0], failure) {
+		t.Errorf("expected us-west-2 to fail with %q, got %v", failure, scanErr)
+	}
+}
+
+func TestScanCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The first region cancels the scan, so no other region is listed
+	var listed []string
+	p := &lister{list: func(ctx context.Context, region string) error {
+		listed = append(listed, region)
+		cancel()
+		return nil
+	}}
+
+	given := []string{"us-east-1", "us-west-2", "eu-west-1"}
+	results, err := resource.Scan(ctx, p, given, 1)
+	if len(listed) != 1 {
+		t.Fatalf("expected a single region to be listed, got %v", listed)
+	}
+	if !reflect.DeepEqual(regions(results), listed) {
+		t.Errorf("expected results for %v, got %v", listed, regions(results))
+	}
+	var scanErr *resource.ScanError
+	if !errors.As(err, &scanErr) {
+		t.Fatalf("expected a scan error, got %v", err)
+	}
+	if len(scanErr.Errors) != len(given)-1 {
+		t.Errorf("expected %d regions to fail, got %v", len(given)-1, scanErr)
+	}
+	for _, regionErr := range scanErr.Errors {
+		if !errors.Is(regionErr, context.Canceled) {
+			t.Errorf("expected %s to be cancelled, got %v", regionErr.Region, regionErr.Err)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package resource
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/jharrington22/aws-resource/pkg/aws"
+)
+
+const (
+	// InvalidSnapshotInUse is the error code returned when deleting a
+	// snapshot that is still backing an AMI.
+	InvalidSnapshotInUse = "InvalidSnapshot.InUse"
+)
+
+func init() {
+	Register("snapshots", func(clients aws.ClientFunc) Provider {
+		return &snapshots{clients: clients}
+	})
+}
+
+// snapshots provides the EBS snapshots owned by the account.
+type snapshots struct {
+	clients aws.ClientFunc
+}
+
+func (p *snapshots) Type() string     { return "snapshots" }
+func (p *snapshots) Describe() string { return "snapshots" }
+func (p *snapshots) Global() bool     { return false }
+
+func (p *snapshots) List(ctx context.Context, region string) ([]*Resource, error) {
+	client, err := p.clients(region)
+	if err != nil {
+		return nil, err
+	}
+
+	owner := "self"
+	input := &ec2.DescribeSnapshotsInput{
+		OwnerIds: []*string{&owner},
+	}
+
+	var resources []*Resource
+	err = client.DescribeSnapshotsPages(input, func(page *ec2.DescribeSnapshotsOutput, lastPage bool) bool {
+		for _, s := range page.Snapshots {
+			resources = append(resources, p.resource(region, s))
+		}
+		return ctx.Err() == nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resources, ctx.Err()
+}
+
+// Get returns the snapshot.
+func (p *snapshots) Get(ctx context.Context, region, id string) (*Resource, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	client, err := p.clients(region)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := client.DescribeSnapshots(&ec2.DescribeSnapshotsInput{
+		SnapshotIds: []*string{&id},
+	})
+	if err != nil {
+		if isNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	for _, s := range output.Snapshots {
+		return p.resource(region, s), nil
+	}
+	return nil, nil
+}
+
+func (p *snapshots) resource(region string, s *ec2.Snapshot) *Resource {
+	tags := ec2Tags(s.Tags)
+	return &Resource{
+		Type:      p.Type(),
+		ID:        value(s.SnapshotId),
+		Region:    region,
+		Name:      tags["Name"],
+		State:     value(s.State),
+		CreatedAt: timeValue(s.StartTime),
+		Tags:      tags,
+		Properties: map[string]string{
+			"volume-id":   value(s.VolumeId),
+			"volume-size": strconv.FormatInt(int64Value(s.VolumeSize), 10),
+		},
+		Raw: s,
+	}
+}
+
+// Delete removes the snapshot. Snapshots backing an AMI fail with an
+// InvalidSnapshotInUse error which is returned unchanged.
+func (p *snapshots) Delete(ctx context.Context, r *Resource, dryRun bool) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	client, err := p.clients(r.Region)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.DeleteSnapshot(&ec2.DeleteSnapshotInput{
+		DryRun:     &dryRun,
+		SnapshotId: &r.ID,
+	})
+	if err != nil && !(dryRun && isDryRun(err)) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,199 @@
+package resource
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/jharrington22/aws-resource/pkg/aws"
+)
+
+const (
+	// StackNameTag and StackIDTag are set by CloudFormation on the
+	// resources it creates to the name and ID of their stack.
+	StackNameTag = "aws:cloudformation:stack-name"
+	StackIDTag   = "aws:cloudformation:stack-id"
+
+	// StackTimeout bounds the time waited for a stack to be deleted.
+	StackTimeout = time.Hour
+)
+
+func init() {
+	Register("stacks", func(clients aws.ClientFunc) Provider {
+		return &stacks{clients: clients}
+	})
+}
+
+// Stack returns the name of the CloudFormation stack the resource belongs
+// to, or an empty string.
+func Stack(r *Resource) string {
+	return r.Tags[StackNameTag]
+}
+
+// StackOf returns the CloudFormation stack the resource belongs to, or nil.
+// The stack is only known by its name and ID, its other fields are left
+// empty.
+func StackOf(r *Resource) *Resource {
+	name := Stack(r)
+	if name == "" {
+		return nil
+	}
+	id := r.Tags[StackIDTag]
+	if id == "" {
+		id = name
+	}
+	return &Resource{
+		Account: r.Account,
+		Type:    "stacks",
+		ID:      id,
+		Region:  r.Region,
+		Name:    name,
+	}
+}
+
+// stacks provides CloudFormation stacks, identified by their ID.
+type stacks struct {
+	clients aws.ClientFunc
+}
+
+func (p *stacks) Type() string     { return "stacks" }
+func (p *stacks) Describe() string { return "CloudFormation stacks" }
+func (p *stacks) Global() bool     { return false }
+
+func (p *stacks) List(ctx context.Context, region string) ([]*Resource, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	client, err := p.clients(region)
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []*Resource
+	input := &cloudformation.DescribeStacksInput{}
+	for {
+		output, err := client.DescribeStacks(input)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range output.Stacks {
+			resources = append(resources, p.resource(region, s))
+		}
+		if output.NextToken == nil || ctx.Err() != nil {
+			break
+		}
+		input.NextToken = output.NextToken
+	}
+	return resources, ctx.Err()
+}
+
+// Get returns the stack whose ID or name is id.
+func (p *stacks) Get(ctx context.Context, region, id string) (*Resource, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	client, err := p.clients(region)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := client.DescribeStacks(&cloudformation.DescribeStacksInput{StackName: &id})
+	if err != nil {
+		if isStackNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	for _, s := range output.Stacks {
+		if value(s.StackStatus) == cloudformation.StackStatusDeleteComplete {
+			return nil, nil
+		}
+		return p.resource(region, s), nil
+	}
+	return nil, nil
+}
+
+func (p *stacks) resource(region string, s *cloudformation.Stack) *Resource {
+	tags := map[string]string{}
+	for _, t := range s.Tags {
+		if t.Key != nil && t.Value != nil {
+			tags[*t.Key] = *t.Value
+		}
+	}
+	return &Resource{
+		Type:      p.Type(),
+		ID:        value(s.StackId),
+		Region:    region,
+		Name:      value(s.StackName),
+		State:     value(s.StackStatus),
+		CreatedAt: timeValue(s.CreationTime),
+		Tags:      tags,
+		Properties: map[string]string{
+			"description":            value(s.Description),
+			"parent":                 value(s.ParentId),
+			"termination-protection": strconv.FormatBool(boolValue(s.EnableTerminationProtection)),
+		},
+		Raw: s,
+	}
+}
+
+// Delete deletes the stack along with its resources, and waits for the
+// deletion to complete. Stacks already being deleted are only waited for.
+// As the API has no dry run, a dry run only checks the stack exists and
+// termination protection is disabled.
+func (p *stacks) Delete(ctx context.Context, r *Resource, dryRun bool) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	client, err := p.clients(r.Region)
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		// The stack is looked up as resources only know its name and ID
+		current, err := p.Get(ctx, r.Region, r.ID)
+		if err != nil {
+			return err
+		}
+		if current == nil {
+			return fmt.Errorf("stack %s doesn't exist anymore", r.Name)
+		}
+		if current.Properties["termination-protection"] == "true" {
+			return fmt.Errorf("termination protection is enabled on %s", r.Name)
+		}
+		return nil
+	}
+
+	if r.State != cloudformation.StackStatusDeleteInProgress {
+		_, err = client.DeleteStack(&cloudformation.DeleteStackInput{StackName: &r.ID})
+		if err != nil {
+			return err
+		}
+	}
+
+	// The waiter polls until the timeout rather than for a number of
+	// attempts, stacks taking as long to delete as their slowest resource
+	ctx, cancel := context.WithTimeout(ctx, StackTimeout)
+	defer cancel()
+	err = client.WaitUntilStackDeleteCompleteWithContext(ctx, &cloudformation.DescribeStacksInput{
+		StackName: &r.ID,
+	}, request.WithWaiterMaxAttempts(0), request.WithWaiterDelay(request.ConstantWaiterDelay(30*time.Second)))
+	if err != nil {
+		return fmt.Errorf("stack %s wasn't deleted: %s", r.Name, err)
+	}
+	return nil
+}
+
+// isStackNotFound reports whether err is returned for a stack that doesn't
+// exist, which CloudFormation reports as a validation error.
+func isStackNotFound(err error) bool {
+	var aerr awserr.Error
+	return errors.As(err, &aerr) && aerr.Code() == "ValidationError" && strings.Contains(aerr.Message(), "does not exist")
+}
@@ -0,0 +1,156 @@
+package resource
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/jharrington22/aws-resource/pkg/aws"
+)
+
+func init() {
+	Register("targetgroups", func(clients aws.ClientFunc) Provider {
+		return &targetGroupsProvider{clients: clients}
+	})
+}
+
+// targetGroupsProvider provides the target groups of v2 load balancers.
+type targetGroupsProvider struct {
+	clients aws.ClientFunc
+}
+
+func (p *targetGroupsProvider) Type() string     { return "targetgroups" }
+func (p *targetGroupsProvider) Describe() string { return "target groups" }
+func (p *targetGroupsProvider) Global() bool     { return false }
+
+func (p *targetGroupsProvider) List(ctx context.Context, region string) ([]*Resource, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	client, err := p.clients(region)
+	if err != nil {
+		return nil, err
+	}
+
+	tgs, err := targetGroups(client, &elbv2.DescribeTargetGroupsInput{})
+	if err != nil {
+		return nil, err
+	}
+	resources, err := p.resources(client, region, tgs)
+	if err != nil {
+		return nil, err
+	}
+	return resources, ctx.Err()
+}
+
+// Get returns the target group whose ARN is id.
+func (p *targetGroupsProvider) Get(ctx context.Context, region, id string) (*Resource, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	client, err := p.clients(region)
+	if err != nil {
+		return nil, err
+	}
+
+	tgs, err := targetGroups(client, &elbv2.DescribeTargetGroupsInput{
+		TargetGroupArns: []*string{&id},
+	})
+	if err != nil {
+		if isNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	resources, err := p.resources(client, region, tgs)
+	if err != nil || len(resources) == 0 {
+		return nil, err
+	}
+	return resources[0], nil
+}
+
+func (p *targetGroupsProvider) resources(client aws.Client, region string, tgs []*elbv2.TargetGroup) ([]*Resource, error) {
+	var arns []*string
+	for _, tg := range tgs {
+		arns = append(arns, tg.TargetGroupArn)
+	}
+	tags, err := elbv2Tags(client, arns)
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []*Resource
+	for _, tg := range tgs {
+		var lbs []string
+		for _, arn := range tg.LoadBalancerArns {
+			lbs = append(lbs, value(arn))
+		}
+		resources = append(resources, &Resource{
+			Type:   p.Type(),
+			ID:     value(tg.TargetGroupArn),
+			Region: region,
+			Name:   value(tg.TargetGroupName),
+			Tags:   tags[value(tg.TargetGroupArn)],
+			Properties: map[string]string{
+				"protocol":       value(tg.Protocol),
+				"port":           strconv.FormatInt(int64Value(tg.Port), 10),
+				"target-type":    value(tg.TargetType),
+				"vpc-id":         value(tg.VpcId),
+				"load-balancers": strings.Join(lbs, ","),
+			},
+			Raw: tg,
+		})
+	}
+	return resources, nil
+}
+
+// targetGroups returns every target group described by the input.
+func targetGroups(client aws.Client, input *elbv2.DescribeTargetGroupsInput) ([]*elbv2.TargetGroup, error) {
+	var tgs []*elbv2.TargetGroup
+	for {
+		output, err := client.DescribeTargetGroups(input)
+		if err != nil {
+			return nil, err
+		}
+		tgs = append(tgs, output.TargetGroups...)
+		if output.NextMarker == nil {
+			return tgs, nil
+		}
+		input.Marker = output.NextMarker
+	}
+}
+
+// Delete removes the target group. Target groups a load balancer still
+// forwards to fail with a ResourceInUse error which is returned unchanged.
+// As the API has no dry run, a dry run only checks no load balancer uses the
+// target group.
+func (p *targetGroupsProvider) Delete(ctx context.Context, r *Resource, dryRun bool) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	client, err := p.clients(r.Region)
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		tgs, err := targetGroups(client, &elbv2.DescribeTargetGroupsInput{
+			TargetGroupArns: []*string{&r.ID},
+		})
+		if err != nil {
+			return err
+		}
+		for _, tg := range tgs {
+			if len(tg.LoadBalancerArns) > 0 {
+				return fmt.Errorf("target group %s is in use by %s", r.Name, value(tg.LoadBalancerArns[0]))
+			}
+		}
+		return nil
+	}
+	_, err = client.DeleteTargetGroup(&elbv2.DeleteTargetGroupInput{
+		TargetGroupArn: &r.ID,
+	})
+	return err
+}
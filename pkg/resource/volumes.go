@@ -0,0 +1,184 @@
+package resource
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/jharrington22/aws-resource/pkg/aws"
+)
+
+const (
+	// VolumeInUse is the error code returned when deleting a volume that is
+	// still attached to an instance.
+	VolumeInUse = "VolumeInUse"
+
+	// SnapshotTimeout bounds the time waited for a final snapshot of a
+	// volume to complete.
+	SnapshotTimeout = 2 * time.Hour
+)
+
+func init() {
+	Register("volumes", func(clients aws.ClientFunc) Provider {
+		return &volumes{clients: clients}
+	})
+}
+
+// volumes provides EBS volumes.
+type volumes struct {
+	clients aws.ClientFunc
+}
+
+func (p *volumes) Type() string     { return "volumes" }
+func (p *volumes) Describe() string { return "volumes" }
+func (p *volumes) Global() bool     { return false }
+
+func (p *volumes) List(ctx context.Context, region string) ([]*Resource, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	client, err := p.clients(region)
+	if err != nil {
+		return nil, err
+	}
+
+	var volumes []*ec2.Volume
+	input := &ec2.DescribeVolumesInput{}
+	for {
+		output, err := client.DescribeVolumes(input)
+		if err != nil {
+			return nil, err
+		}
+		volumes = append(volumes, output.Volumes...)
+		if output.NextToken == nil || ctx.Err() != nil {
+			break
+		}
+		input.NextToken = output.NextToken
+	}
+
+	var resources []*Resource
+	for _, v := range volumes {
+		resources = append(resources, p.resource(region, v))
+	}
+	return resources, ctx.Err()
+}
+
+// Get returns the volume.
+func (p *volumes) Get(ctx context.Context, region, id string) (*Resource, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	client, err := p.clients(region)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := client.DescribeVolumes(&ec2.DescribeVolumesInput{
+		VolumeIds: []*string{&id},
+	})
+	if err != nil {
+		if isNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	for _, v := range output.Volumes {
+		return p.resource(region, v), nil
+	}
+	return nil, nil
+}
+
+func (p *volumes) resource(region string, v *ec2.Volume) *Resource {
+	tags := ec2Tags(v.Tags)
+	var instances []string
+	for _, a := range v.Attachments {
+		instances = append(instances, value(a.InstanceId))
+	}
+	return &Resource{
+		Type:      p.Type(),
+		ID:        value(v.VolumeId),
+		Region:    region,
+		Name:      tags["Name"],
+		State:     value(v.State),
+		CreatedAt: timeValue(v.CreateTime),
+		Tags:      tags,
+		Properties: map[string]string{
+			"volume-type": value(v.VolumeType),
+			"size":        strconv.FormatInt(int64Value(v.Size), 10),
+			"attached":    strconv.FormatBool(len(v.Attachments) > 0),
+			"instances":   strings.Join(instances, ","),
+		},
+		Raw: v,
+	}
+}
+
+// Delete removes the volume. Volumes attached to an instance fail with a
+// VolumeInUse error which is returned unchanged.
+func (p *volumes) Delete(ctx context.Context, r *Resource, dryRun bool) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	client, err := p.clients(r.Region)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.DeleteVolume(&ec2.DeleteVolumeInput{
+		DryRun:   &dryRun,
+		VolumeId: &r.ID,
+	})
+	if err != nil && !(dryRun && isDryRun(err)) {
+		return err
+	}
+	return nil
+}
+
+// SnapshotVolume takes a snapshot of the volume, tagged with the volume's
+// tags and ID, and waits for it to complete. It returns the snapshot, or nil
+// on a dry run.
+func SnapshotVolume(ctx context.Context, clients aws.ClientFunc, volume *Resource, dryRun bool) (*Resource, error) {
+	client, err := clients(volume.Region)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := []*ec2.Tag{{Key: awssdk.String("aws-resource/volume-id"), Value: awssdk.String(volume.ID)}}
+	for k, v := range volume.Tags {
+		if !strings.HasPrefix(k, "aws:") {
+			tags = append(tags, &ec2.Tag{Key: awssdk.String(k), Value: awssdk.String(v)})
+		}
+	}
+	snapshot, err := client.CreateSnapshot(&ec2.CreateSnapshotInput{
+		DryRun:      &dryRun,
+		VolumeId:    &volume.ID,
+		Description: awssdk.String(fmt.Sprintf("Final snapshot of %s", volume.ID)),
+		TagSpecifications: []*ec2.TagSpecification{{
+			ResourceType: awssdk.String(ec2.ResourceTypeSnapshot),
+			Tags:         tags,
+		}},
+	})
+	if dryRun && isDryRun(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// The waiter polls until the timeout rather than for a number of
+	// attempts, snapshots of large volumes taking hours
+	ctx, cancel := context.WithTimeout(ctx, SnapshotTimeout)
+	defer cancel()
+	err = client.WaitUntilSnapshotCompletedWithContext(ctx, &ec2.DescribeSnapshotsInput{
+		SnapshotIds: []*string{snapshot.SnapshotId},
+	}, request.WithWaiterMaxAttempts(0), request.WithWaiterDelay(request.ConstantWaiterDelay(15*time.Second)))
+	if err != nil {
+		return nil, fmt.Errorf("snapshot %s of %s didn't complete: %s", value(snapshot.SnapshotId), volume.ID, err)
+	}
+	snapshot.State = awssdk.String(ec2.SnapshotStateCompleted)
+	return (&snapshots{}).resource(volume.Region, snapshot), nil
+}
@@ -0,0 +1,257 @@
+// Package selector parses tag selector expressions, such as
+// "env=dev,owner!=prod,!keep,cluster in (a,b)", and matches them against the
+// tags of resources.
+package selector
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Operator is the comparison made by a requirement.
+type Operator string
+
+const (
+	Equals       Operator = "="
+	NotEquals    Operator = "!="
+	In           Operator = "in"
+	NotIn        Operator = "notin"
+	Exists       Operator = "exists"
+	DoesNotExist Operator = "!"
+)
+
+// Requirement is a single comparison made against the value of a tag.
+type Requirement struct {
+	Key      string
+	Operator Operator
+	Values   []string
+}
+
+// Matches reports whether tags satisfy the requirement. As with Kubernetes
+// label selectors, != and notin match resources without the tag.
+func (r Requirement) Matches(tags map[string]string) bool {
+	value, ok := tags[r.Key]
+	switch r.Operator {
+	case Equals, In:
+		return ok && r.has(value)
+	case NotEquals, NotIn:
+		return !ok || !r.has(value)
+	case Exists:
+		return ok
+	case DoesNotExist:
+		return !ok
+	}
+	return false
+}
+
+func (r Requirement) has(value string) bool {
+	for _, v := range r.Values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the requirement in the syntax accepted by Parse.
+func (r Requirement) String() string {
+	switch r.Operator {
+	case Equals, NotEquals:
+		return r.Key + string(r.Operator) + r.Values[0]
+	case In, NotIn:
+		return fmt.Sprintf("%s %s (%s)", r.Key, r.Operator, strings.Join(r.Values, ","))
+	case DoesNotExist:
+		return "!" + r.Key
+	}
+	return r.Key
+}
+
+// Selector matches resources satisfying all of its requirements. The zero
+// value matches everything.
+type Selector []Requirement
+
+// Empty reports whether the selector matches everything.
+func (s Selector) Empty() bool {
+	return len(s) == 0
+}
+
+// Matches reports whether tags satisfy every requirement of the selector.
+func (s Selector) Matches(tags map[string]string) bool {
+	for _, r := range s {
+		if !r.Matches(tags) {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns the selector in the syntax accepted by Parse.
+func (s Selector) String() string {
+	parts := make([]string, len(s))
+	for i, r := range s {
+		parts[i] = r.String()
+	}
+	return strings.Join(parts, ",")
+}
+
+// Parse parses a comma separated list of requirements, each one of:
+//
+//	key=value, key==value   the tag is set to value
+//	key!=value              the tag is missing or set to another value
+//	key in (a,b)            the tag is set to one of the values
+//	key notin (a,b)         the tag is missing or set to none of the values
+//	key                     the tag is set
+//	!key                    the tag is missing
+//
+// Keys and values may contain any character other than the separators, so
+// tags such as "kubernetes.io/cluster/name" or "aws:cloudformation:stack-name"
+// can be selected. An empty expression returns an empty selector.
+func Parse(expression string) (Selector, error) {
+	parts, err := split(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	var selector Selector
+	for _, part := range parts {
+		r, err := parseRequirement(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid selector %q: %s", expression, err)
+		}
+		selector = append(selector, r)
+	}
+	return selector, nil
+}
+
+// split splits the expression on the commas that aren't part of a set of
+// values, dropping empty requirements.
+func split(expression string) ([]string, error) {
+	var parts []string
+	var depth, start int
+	for i, c := range expression {
+		switch c {
+		case '(':
+			depth++
+			if depth > 1 {
+				return nil, fmt.Errorf("invalid selector %q: nested parentheses", expression)
+			}
+		case ')':
+			depth--
+			if depth < 0 {
+				return nil, fmt.Errorf("invalid selector %q: unexpected ')'", expression)
+			}
+		case ',':
+			if depth == 0 {
+				parts = append(parts, expression[start:i])
+				start = i + 1
+			}
+		}
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("invalid selector %q: missing ')'", expression)
+	}
+	parts = append(parts, expression[start:])
+
+	var result []string
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			result = append(result, part)
+		}
+	}
+	return result, nil
+}
+
+func parseRequirement(s string) (Requirement, error) {
+	if strings.HasPrefix(s, "!") && !strings.ContainsAny(s, "=(") {
+		key := strings.TrimSpace(s[1:])
+		if err := validKey(key); err != nil {
+			return Requirement{}, err
+		}
+		return Requirement{Key: key, Operator: DoesNotExist}, nil
+	}
+
+	if i := strings.Index(s, "("); i >= 0 {
+		return parseSet(s, i)
+	}
+
+	for _, op := range []struct {
+		token    string
+		operator Operator
+	}{
+		{"!=", NotEquals},
+		{"==", Equals},
+		{"=", Equals},
+	} {
+		i := strings.Index(s, op.token)
+		if i < 0 {
+			continue
+		}
+		key := strings.TrimSpace(s[:i])
+		if err := validKey(key); err != nil {
+			return Requirement{}, err
+		}
+		value := strings.TrimSpace(s[i+len(op.token):])
+		if strings.Contains(value, "=") {
+			return Requirement{}, fmt.Errorf("unexpected operator in value %q", value)
+		}
+		return Requirement{Key: key, Operator: op.operator, Values: []string{value}}, nil
+	}
+
+	if strings.ContainsAny(s, " \t") {
+		return Requirement{}, fmt.Errorf("unexpected %q, expected one of =, !=, in or notin", s)
+	}
+	if err := validKey(s); err != nil {
+		return Requirement{}, err
+	}
+	return Requirement{Key: s, Operator: Exists}, nil
+}
+
+// parseSet parses an "in" or "notin" requirement whose values start at the
+// parenthesis at index open.
+func parseSet(s string, open int) (Requirement, error) {
+	if !strings.HasSuffix(s, ")") {
+		return Requirement{}, fmt.Errorf("unexpected characters after ')' in %q", s)
+	}
+
+	fields := strings.Fields(s[:open])
+	if len(fields) != 2 {
+		return Requirement{}, fmt.Errorf("expected '<key> in (<values>)' or '<key> notin (<values>)', got %q", s)
+	}
+	key := fields[0]
+	if err := validKey(key); err != nil {
+		return Requirement{}, err
+	}
+
+	var operator Operator
+	switch fields[1] {
+	case string(In):
+		operator = In
+	case string(NotIn):
+		operator = NotIn
+	default:
+		return Requirement{}, fmt.Errorf("unknown operator %q, expected in or notin", fields[1])
+	}
+
+	var values []string
+	for _, v := range strings.Split(s[open+1:len(s)-1], ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	if len(values) == 0 {
+		return Requirement{}, fmt.Errorf("no values given for %s %s", key, operator)
+	}
+	sort.Strings(values)
+	return Requirement{Key: key, Operator: operator, Values: values}, nil
+}
+
+func validKey(key string) error {
+	if key == "" {
+		return fmt.Errorf("missing tag key")
+	}
+	if strings.ContainsAny(key, "!=() \t") {
+		return fmt.Errorf("invalid tag key %q", key)
+	}
+	return nil
+}
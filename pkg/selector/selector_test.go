@@ -0,0 +1,90 @@
+package selector
+
+import (
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		expression string
+		want       string
+		err        bool
+	}{
+		{expression: "", want: ""},
+		{expression: "env=dev", want: "env=dev"},
+		{expression: "env == dev", want: "env=dev"},
+		{expression: " env=dev , owner!=prod ", want: "env=dev,owner!=prod"},
+		{expression: "!keep,team", want: "!keep,team"},
+		{expression: "cluster in (b, a),env notin (prod)", want: "cluster in (a,b),env notin (prod)"},
+		{expression: "kubernetes.io/cluster/dev=owned", want: "kubernetes.io/cluster/dev=owned"},
+		{expression: "aws:cloudformation:stack-name=web", want: "aws:cloudformation:stack-name=web"},
+		{expression: "env=", want: "env="},
+		{expression: "=dev", err: true},
+		{expression: "!", err: true},
+		{expression: "env=dev=test", err: true},
+		{expression: "cluster in (a,b", err: true},
+		{expression: "cluster in a,b)", err: true},
+		{expression: "cluster in ()", err: true},
+		{expression: "cluster has (a)", err: true},
+		{expression: "cluster in (a) x", err: true},
+		{expression: "env dev", err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			selector, err := Parse(tt.expression)
+			if tt.err {
+				if err == nil {
+					t.Fatalf("expected an error, got %q", selector)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := selector.String(); got != tt.want {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatches(t *testing.T) {
+	tags := map[string]string{
+		"env":     "dev",
+		"owner":   "alice",
+		"cluster": "a",
+	}
+	tests := []struct {
+		expression string
+		want       bool
+	}{
+		{"", true},
+		{"env=dev", true},
+		{"env=prod", false},
+		{"owner!=prod", true},
+		{"missing!=prod", true},
+		{"env!=dev", false},
+		{"!keep", true},
+		{"!env", false},
+		{"owner", true},
+		{"keep", false},
+		{"cluster in (a,b)", true},
+		{"cluster in (b,c)", false},
+		{"cluster notin (b,c)", true},
+		{"missing notin (b,c)", true},
+		{"missing in (b,c)", false},
+		{"env=dev,owner!=prod,!keep,cluster in (a,b)", true},
+		{"env=dev,keep", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			selector, err := Parse(tt.expression)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := selector.Matches(tags); got != tt.want {
+				t.Fatalf("got %t, want %t", got, tt.want)
+			}
+		})
+	}
+}